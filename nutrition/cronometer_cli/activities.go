@@ -0,0 +1,100 @@
+package main
+
+import (
+	"context"
+	"time"
+)
+
+// Post-workout window for -protein-timing: a protein-rich meal is at least
+// 20g of protein within two hours of finishing
+const (
+	postWorkoutWindowMinutes = 120
+	postWorkoutProteinG      = 20.0
+)
+
+// ActivityEntry is a workout logged in Cronometer's exercise export
+type ActivityEntry struct {
+	Date           string    `json:"date"`
+	Start          time.Time `json:"start"`
+	Exercise       string    `json:"exercise"`
+	Minutes        float64   `json:"minutes"`
+	CaloriesBurned float64   `json:"calories_burned"`
+}
+
+// End returns when the workout finished
+func (a ActivityEntry) End() time.Time {
+	return a.Start.Add(time.Duration(a.Minutes * float64(time.Minute)))
+}
+
+// PostWorkoutReport is the protein eaten in the window after a workout
+type PostWorkoutReport struct {
+	Date                string  `json:"date"`
+	Exercise            string  `json:"exercise"`
+	PostWorkoutProteinG float64 `json:"post_workout_protein_g"`
+	WindowMet           bool    `json:"window_met"`
+}
+
+// fetchActivities exports the exercises for the date range
+func fetchActivities(ctx context.Context, client Client, start, end time.Time) ([]ActivityEntry, error) {
+	records, err := client.ExportExercisesParsedWithLocation(ctx, start, end, time.Local)
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]ActivityEntry, 0, len(records))
+	for _, r := range records {
+		entries = append(entries, ActivityEntry{
+			Date:           r.RecordedTime.Format("2006-01-02"),
+			Start:          r.RecordedTime,
+			Exercise:       r.Exercise,
+			Minutes:        r.Minutes,
+			CaloriesBurned: r.CaloriesBurned,
+		})
+	}
+	return entries, nil
+}
+
+// hasClockTime reports whether t has a time of day. Cronometer records
+// without a time are exported at midnight.
+func hasClockTime(t time.Time) bool {
+	return t.Hour() != 0 || t.Minute() != 0
+}
+
+// PostWorkoutProtein totals the protein logged within windowMinutes after the
+// workout ended, and whether it reached a protein-rich meal
+func PostWorkoutProtein(workout ActivityEntry, diary []FoodEntry, windowMinutes int) PostWorkoutReport {
+	report := PostWorkoutReport{Date: workout.Date, Exercise: workout.Exercise}
+	end := workout.End()
+	deadline := end.Add(time.Duration(windowMinutes) * time.Minute)
+	for _, e := range diary {
+		if !hasClockTime(e.Time) || e.Time.Before(end) || e.Time.After(deadline) {
+			continue
+		}
+		report.PostWorkoutProteinG += e.Protein
+	}
+	report.WindowMet = report.PostWorkoutProteinG >= postWorkoutProteinG
+	return report
+}
+
+// postWorkoutReports reports every workout with a logged time of day on one
+// of the records' dates
+func postWorkoutReports(records []DailyNutrition, activities []ActivityEntry, diary []FoodEntry) []PostWorkoutReport {
+	dates := make(map[string]bool, len(records))
+	for _, d := range records {
+		dates[d.Date] = true
+	}
+	byDate := groupEntriesByDate(diary)
+
+	reports := []PostWorkoutReport{}
+	for _, a := range activities {
+		if !dates[a.Date] || !hasClockTime(a.Start) {
+			continue
+		}
+		// Include the next day's entries for late workouts
+		var entries []FoodEntry
+		entries = append(entries, byDate[a.Date]...)
+		entries = append(entries, byDate[a.Start.AddDate(0, 0, 1).Format("2006-01-02")]...)
+		reports = append(reports, PostWorkoutProtein(a, entries, postWorkoutWindowMinutes))
+	}
+	return reports
+}
@@ -0,0 +1,149 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// trendThresholdPct is how many percentage points adherence must move to count as a trend
+const trendThresholdPct = 5.0
+
+// Trend arrows comparing a week's adherence to the prior week
+const (
+	trendImproving = "↑"
+	trendWorsening = "↓"
+	trendStable    = "→"
+)
+
+// WeekAdherence is the share of days each goal was met during one ISO week
+type WeekAdherence struct {
+	Week         string             `json:"week"`
+	AdherencePct map[string]float64 `json:"adherence_pct"`
+	Trend        map[string]string  `json:"trend,omitempty"`
+}
+
+// isoWeek returns the ISO week label (e.g. "2024-W03") for a YYYY-MM-DD date
+func isoWeek(date string) (string, error) {
+	t, err := time.Parse("2006-01-02", date)
+	if err != nil {
+		return "", err
+	}
+	year, week := t.ISOWeek()
+	return fmt.Sprintf("%d-W%02d", year, week), nil
+}
+
+// dayMeetsGoal reports whether a record met the named goal, preferring the
+// per-day comparison from applyGoals so computed goals are respected
+func dayMeetsGoal(d DailyNutrition, name string, target float64) bool {
+	if result, ok := d.Goals[name]; ok {
+		return result.Met
+	}
+	return goalMet(name, *nutrientField(&d, name), target)
+}
+
+// WeeklyAdherenceTrend computes each goal's adherence per ISO week, with a
+// trend arrow comparing every week after the first to the week before it
+func WeeklyAdherenceTrend(records []DailyNutrition, goals map[string]float64) []WeekAdherence {
+	type weekCounts struct {
+		days int
+		met  map[string]int
+	}
+	byWeek := make(map[string]*weekCounts)
+	for _, d := range records {
+		week, err := isoWeek(d.Date)
+		if err != nil {
+			continue
+		}
+		counts, ok := byWeek[week]
+		if !ok {
+			counts = &weekCounts{met: make(map[string]int)}
+			byWeek[week] = counts
+		}
+		counts.days++
+		for name, target := range goals {
+			if dayMeetsGoal(d, name, target) {
+				counts.met[name]++
+			}
+		}
+	}
+
+	weeks := make([]string, 0, len(byWeek))
+	for week := range byWeek {
+		weeks = append(weeks, week)
+	}
+	sort.Strings(weeks)
+
+	results := make([]WeekAdherence, 0, len(weeks))
+	for i, week := range weeks {
+		counts := byWeek[week]
+		adherence := WeekAdherence{Week: week, AdherencePct: make(map[string]float64)}
+		for name := range goals {
+			adherence.AdherencePct[name] = float64(counts.met[name]) / float64(counts.days) * 100
+		}
+		if i > 0 {
+			prior := results[i-1]
+			adherence.Trend = make(map[string]string)
+			for name, pct := range adherence.AdherencePct {
+				adherence.Trend[name] = trendArrow(pct - prior.AdherencePct[name])
+			}
+		}
+		results = append(results, adherence)
+	}
+	return results
+}
+
+// WeekScore is a week's average daily score, with a trend arrow against the week before
+type WeekScore struct {
+	Week         string  `json:"week"`
+	AverageScore float64 `json:"average_score"`
+	Trend        string  `json:"trend,omitempty"`
+}
+
+// weeklyScoreTrend averages date-ordered daily scores per ISO week. A change
+// of at least threshold from the week before counts as a trend.
+func weeklyScoreTrend(dates []string, scores []float64, threshold float64) []WeekScore {
+	totals := make(map[string]float64)
+	counts := make(map[string]int)
+	var weeks []string
+	for i, date := range dates {
+		week, err := isoWeek(date)
+		if err != nil {
+			continue
+		}
+		if counts[week] == 0 {
+			weeks = append(weeks, week)
+		}
+		totals[week] += scores[i]
+		counts[week]++
+	}
+
+	trend := make([]WeekScore, 0, len(weeks))
+	for i, week := range weeks {
+		entry := WeekScore{Week: week, AverageScore: totals[week] / float64(counts[week])}
+		if i > 0 {
+			switch change := entry.AverageScore - trend[i-1].AverageScore; {
+			case change >= threshold:
+				entry.Trend = trendImproving
+			case change <= -threshold:
+				entry.Trend = trendWorsening
+			default:
+				entry.Trend = trendStable
+			}
+		}
+		trend = append(trend, entry)
+	}
+	return trend
+}
+
+// trendArrow turns a change in percentage points into a trend arrow
+func trendArrow(change float64) string {
+	switch {
+	case change > trendThresholdPct:
+		return trendImproving
+	case change < -trendThresholdPct:
+		return trendWorsening
+	default:
+		return trendStable
+	}
+}
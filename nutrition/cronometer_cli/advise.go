@@ -0,0 +1,65 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// parseAdvise parses the -advise flag value, e.g. "meals=2"
+func parseAdvise(value string) (int, error) {
+	pairs, err := parseKeyValues(value)
+	if err != nil {
+		return 0, err
+	}
+	meals, err := strconv.Atoi(pairs["meals"])
+	if err != nil || meals < 1 {
+		return 0, fmt.Errorf("expected meals=N with N >= 1, got %q", value)
+	}
+	return meals, nil
+}
+
+// goalsAsNutrition puts the configured goals into a DailyNutrition, leaving unset goals at zero
+func goalsAsNutrition(goals map[string]float64) DailyNutrition {
+	var d DailyNutrition
+	for name, target := range goals {
+		if field := nutrientField(&d, name); field != nil {
+			*field = target
+		}
+	}
+	return d
+}
+
+// BudgetRemainingMeals splits what is left of each daily goal evenly across
+// the remaining meals. Nutrients already at or past their goal get zero.
+func BudgetRemainingMeals(dailySoFar, dailyGoal DailyNutrition, mealsRemaining int) DailyNutrition {
+	perMeal := func(goal, soFar float64) float64 {
+		if mealsRemaining < 1 {
+			return 0
+		}
+		return math.Max(goal-soFar, 0) / float64(mealsRemaining)
+	}
+	return DailyNutrition{
+		Date:     dailySoFar.Date,
+		Calories: perMeal(dailyGoal.Calories, dailySoFar.Calories),
+		Fat:      perMeal(dailyGoal.Fat, dailySoFar.Fat),
+		Carbs:    perMeal(dailyGoal.Carbs, dailySoFar.Carbs),
+		Protein:  perMeal(dailyGoal.Protein, dailySoFar.Protein),
+		Fiber:    perMeal(dailyGoal.Fiber, dailySoFar.Fiber),
+	}
+}
+
+// formatMealAdvice describes the per-meal target for the goals that are set
+func formatMealAdvice(perMeal DailyNutrition, goals map[string]float64, mealsRemaining int) string {
+	var parts []string
+	for _, name := range goalNames(goals) {
+		value := *nutrientField(&perMeal, name)
+		if name == "calories" {
+			parts = append(parts, fmt.Sprintf("%.0f kcal", value))
+		} else {
+			parts = append(parts, fmt.Sprintf("%.0fg %s", value, name))
+		}
+	}
+	return fmt.Sprintf("For each of the remaining %d meals, aim for %s.", mealsRemaining, strings.Join(parts, ", "))
+}
@@ -0,0 +1,72 @@
+package main
+
+// AGE score boundaries for the daily interpretation
+const (
+	ageModerateScore = 10.0
+	ageHighScore     = 25.0
+)
+
+// ageFactors are relative advanced glycation end product loads by cooking
+// method, loosely following Uribarri et al. (J Am Diet Assoc 2010): dry, high
+// heat forms the most AGEs and moist heat the fewest. Keys are matched as
+// substrings of lowercased diary food names.
+var ageFactors = map[string]float64{
+	"fried":      3.0,
+	"deep fried": 3.5,
+	"fries":      3.0,
+	"crispy":     3.0,
+	"grill":      2.5,
+	"broil":      2.5,
+	"bbq":        2.5,
+	"barbecue":   2.5,
+	"seared":     2.5,
+	"roast":      2.0,
+	"toast":      2.0,
+	"baked":      1.5,
+	"sauteed":    1.5,
+	"sautéed":    1.5,
+	"stewed":     0.6,
+	"poached":    0.5,
+	"steamed":    0.5,
+	"boiled":     0.5,
+}
+
+// AGEReport is a day's estimated AGE load from cooking methods
+type AGEReport struct {
+	Score          float64 `json:"score"`
+	Interpretation string  `json:"interpretation"`
+}
+
+// DailyAGEScore detects each entry's cooking method from its name and adds the
+// method's factor for every 100 kcal. Entries with no recognized method are
+// left out.
+func DailyAGEScore(entries []FoodEntry, ageTable map[string]float64) float64 {
+	var score float64
+	for _, e := range entries {
+		if factor, ok := tableMatch(e.FoodName, ageTable); ok {
+			score += factor * e.Calories / 100
+		}
+	}
+	return score
+}
+
+// AGEInterpretation describes a daily AGE score
+func AGEInterpretation(score float64) string {
+	switch {
+	case score >= ageHighScore:
+		return "high: favor steaming, boiling, and stewing over frying and grilling"
+	case score >= ageModerateScore:
+		return "moderate"
+	default:
+		return "low"
+	}
+}
+
+// applyAGEScores attaches an AGE report to each day from its diary
+func applyAGEScores(records []DailyNutrition, diary []FoodEntry) {
+	byDate := groupEntriesByDate(diary)
+	for i := range records {
+		score := DailyAGEScore(byDate[records[i].Date], ageFactors)
+		records[i].AGE = &AGEReport{Score: score, Interpretation: AGEInterpretation(score)}
+	}
+}
@@ -0,0 +1,112 @@
+package main
+
+import (
+	"math"
+	"sort"
+)
+
+// Alcohol conversions: a UK unit is 10g of alcohol, and alcohol has 7 kcal per gram
+const (
+	gramsPerAlcoholUnit = 10.0
+	kcalPerGramAlcohol  = 7.0
+)
+
+// NHS weekly unit guidance: up to 14 units is low risk for everyone, and
+// above 35 units for women or 50 for men is higher risk
+const (
+	lowRiskWeeklyUnits          = 14.0
+	higherRiskWeeklyUnitsFemale = 35.0
+	higherRiskWeeklyUnitsMale   = 50.0
+)
+
+// alcoholKeywords are lowercase name fragments for alcoholic drinks
+var alcoholKeywords = []string{
+	"beer", "wine", "spirits", "alcohol", "lager", "vodka", "whiskey", "whisky",
+	"bourbon", "tequila", "cocktail", "liqueur", "champagne", "prosecco", "cider",
+}
+
+// nonAlcoholicKeywords are lowercase name fragments that rule out an
+// alcohol keyword match, such as root beer or wine vinegar
+var nonAlcoholicKeywords = []string{
+	"root beer", "ginger beer", "non-alcoholic", "nonalcoholic", "alcohol free",
+	"alcohol-free", "sugar alcohol", "vinegar", "apple cider",
+}
+
+// WeekAlcohol is one ISO week's alcohol units
+type WeekAlcohol struct {
+	Week             string  `json:"week"`
+	Units            float64 `json:"units"`
+	DrinkingCategory string  `json:"drinking_category"`
+}
+
+// isAlcoholic reports whether the entry is an alcoholic drink
+func isAlcoholic(e FoodEntry) bool {
+	return containsAny(e.FoodName, alcoholKeywords) && !containsAny(e.FoodName, nonAlcoholicKeywords)
+}
+
+// alcoholUnits estimates an alcoholic entry's units from the calories its
+// carbs, protein, and fat don't account for
+func alcoholUnits(e FoodEntry) float64 {
+	kcal := e.Calories - kcalPerGramCarbs*e.Carbs - kcalPerGramProtein*e.Protein - kcalPerGramFat*e.Fat
+	return math.Max(kcal, 0) / kcalPerGramAlcohol / gramsPerAlcoholUnit
+}
+
+// WeeklyAlcoholUnits totals the alcohol units in the entries, which are
+// normally one ISO week's diary
+func WeeklyAlcoholUnits(entries []FoodEntry) float64 {
+	var units float64
+	for _, e := range entries {
+		if isAlcoholic(e) {
+			units += alcoholUnits(e)
+		}
+	}
+	return units
+}
+
+// DrinkingCategory rates a week's units by NHS guidance as "none",
+// "low_risk" (up to 14), "increasing_risk", or "higher_risk" (above 35 for
+// women, or 50 for men). The lower limit applies unless sex is "male".
+func DrinkingCategory(weeklyUnits float64, sex string) string {
+	higherRisk := higherRiskWeeklyUnitsFemale
+	if sex == "male" {
+		higherRisk = higherRiskWeeklyUnitsMale
+	}
+	switch {
+	case weeklyUnits == 0:
+		return "none"
+	case weeklyUnits <= lowRiskWeeklyUnits:
+		return "low_risk"
+	case weeklyUnits <= higherRisk:
+		return "increasing_risk"
+	default:
+		return "higher_risk"
+	}
+}
+
+// applyAlcohol sets each day's alcohol units and returns each ISO week's
+// units and drinking category, sorted by week
+func applyAlcohol(records []DailyNutrition, diary []FoodEntry, sex string) []WeekAlcohol {
+	byDate := groupEntriesByDate(diary)
+	for i := range records {
+		units := WeeklyAlcoholUnits(byDate[records[i].Date])
+		records[i].AlcoholUnits = &units
+	}
+
+	byWeek := make(map[string][]FoodEntry)
+	for _, e := range diary {
+		week, err := isoWeek(e.Date)
+		if err != nil {
+			continue
+		}
+		byWeek[week] = append(byWeek[week], e)
+	}
+	weeks := make([]WeekAlcohol, 0, len(byWeek))
+	for week, entries := range byWeek {
+		units := WeeklyAlcoholUnits(entries)
+		weeks = append(weeks, WeekAlcohol{Week: week, Units: units, DrinkingCategory: DrinkingCategory(units, sex)})
+	}
+	sort.Slice(weeks, func(i, j int) bool {
+		return weeks[i].Week < weeks[j].Week
+	})
+	return weeks
+}
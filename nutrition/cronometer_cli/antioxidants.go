@@ -0,0 +1,40 @@
+package main
+
+import "math"
+
+// Adult RDAs for the antioxidant nutrients (vitamin C is the RDA for men)
+const (
+	vitaminCRDAMg = 90.0
+	vitaminERDAMg = 15.0
+	seleniumRDAUg = 55.0
+)
+
+// AntioxidantReport scores a day's vitamin C, vitamin E, and selenium
+type AntioxidantReport struct {
+	Score            float64 `json:"score"`
+	VitaminCAdequate bool    `json:"vitamin_c_adequate"`
+	VitaminEAdequate bool    `json:"vitamin_e_adequate"`
+	SeleniumAdequate bool    `json:"selenium_adequate"`
+}
+
+// AntioxidantScore weighs vitamin C, vitamin E, and selenium equally, each as
+// a share of its RDA capped at 1, for a score from 0 to 100
+func AntioxidantScore(d DailyNutrition) float64 {
+	share := func(value, rda float64) float64 {
+		return math.Min(value/rda, 1)
+	}
+	return 100 * (share(d.VitaminC, vitaminCRDAMg) + share(d.VitaminE, vitaminERDAMg) + share(d.Selenium, seleniumRDAUg)) / 3
+}
+
+// applyAntioxidants attaches an antioxidant report to each day
+func applyAntioxidants(records []DailyNutrition) {
+	for i := range records {
+		d := &records[i]
+		d.Antioxidants = &AntioxidantReport{
+			Score:            AntioxidantScore(*d),
+			VitaminCAdequate: d.VitaminC >= vitaminCRDAMg,
+			VitaminEAdequate: d.VitaminE >= vitaminERDAMg,
+			SeleniumAdequate: d.Selenium >= seleniumRDAUg,
+		}
+	}
+}
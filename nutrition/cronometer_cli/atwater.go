@@ -0,0 +1,59 @@
+package main
+
+import "math"
+
+// Atwater general factors in kcal per gram
+const (
+	kcalPerGramProtein = 4.0
+	kcalPerGramFat     = 9.0
+	kcalPerGramCarbs   = 4.0
+)
+
+// CalorieDiscrepancy summarizes how Cronometer's reported calories differ
+// from the recomputed Atwater values (reported minus recomputed)
+type CalorieDiscrepancy struct {
+	MeanDifference    float64 `json:"mean_difference"`
+	MeanAbsDifference float64 `json:"mean_abs_difference"`
+	MaxAbsDifference  float64 `json:"max_abs_difference"`
+}
+
+// atwaterCalories computes energy from macros using the 4/9/4 kcal/g factors
+func atwaterCalories(d DailyNutrition) float64 {
+	return kcalPerGramProtein*d.Protein + kcalPerGramFat*d.Fat + kcalPerGramCarbs*d.Carbs
+}
+
+// recomputeCalories replaces each record's calories with the Atwater value,
+// keeping the Cronometer-reported value in OriginalCalories
+func recomputeCalories(records []DailyNutrition) {
+	for i := range records {
+		original := records[i].Calories
+		records[i].OriginalCalories = &original
+		records[i].Calories = atwaterCalories(records[i])
+	}
+}
+
+// calorieDiscrepancy summarizes reported vs. recomputed calories for records
+// that went through recomputeCalories
+func calorieDiscrepancy(records []DailyNutrition) *CalorieDiscrepancy {
+	var total, totalAbs, maxAbs float64
+	count := 0
+	for _, d := range records {
+		if d.OriginalCalories == nil {
+			continue
+		}
+		diff := *d.OriginalCalories - d.Calories
+		total += diff
+		totalAbs += math.Abs(diff)
+		maxAbs = math.Max(maxAbs, math.Abs(diff))
+		count++
+	}
+
+	if count == 0 {
+		return &CalorieDiscrepancy{}
+	}
+	return &CalorieDiscrepancy{
+		MeanDifference:    total / float64(count),
+		MeanAbsDifference: totalAbs / float64(count),
+		MaxAbsDifference:  maxAbs,
+	}
+}
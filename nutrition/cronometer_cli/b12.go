@@ -0,0 +1,112 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// b12RDAUg is the adult recommended dietary allowance for vitamin B12
+const b12RDAUg = 2.4
+
+// Dietary patterns for -check-b12
+const (
+	dietAuto       = "auto"
+	dietVegan      = "vegan"
+	dietVegetarian = "vegetarian"
+	dietOmnivore   = "omnivore"
+)
+
+// meatKeywords are lowercase name fragments for meat and fish
+var meatKeywords = []string{
+	"beef", "steak", "pork", "bacon", "prosciutto", "sausage", "chicken", "turkey",
+	"lamb", "veal", "duck", "fish", "salmon", "tuna", "cod", "shrimp", "prawn",
+	"crab", "lobster", "anchov", "sardine", "jerky", "salami", "pepperoni",
+}
+
+// animalProductKeywords are lowercase name fragments for vegetarian animal products
+var animalProductKeywords = []string{
+	"egg", "milk", "cheese", "yogurt", "yoghurt", "butter", "cream", "whey",
+	"casein", "honey", "ghee", "kefir",
+}
+
+// plantLookalikes are plant foods named after animal products; they are
+// removed from a name before matching animalProductKeywords
+var plantLookalikes = []string{
+	"eggplant", "peanut butter", "almond butter", "cashew butter", "nut butter",
+	"cocoa butter", "apple butter", "almond milk", "soy milk", "oat milk",
+	"rice milk", "cashew milk", "coconut milk", "coconut cream",
+}
+
+// B12Report summarizes B12 intake for the dietary pattern
+type B12Report struct {
+	DietaryPattern string  `json:"dietary_pattern"`
+	AverageUg      float64 `json:"average_ug"`
+	DaysBelowRDA   int     `json:"days_below_rda"`
+	Recommendation string  `json:"recommendation,omitempty"`
+}
+
+// parseB12Pattern parses a -check-b12 value such as "dietary-pattern=auto"
+func parseB12Pattern(value string) (string, error) {
+	pairs, err := parseKeyValues(value)
+	if err != nil {
+		return "", err
+	}
+	switch pattern := pairs["dietary-pattern"]; pattern {
+	case dietAuto, dietVegan, dietVegetarian, dietOmnivore:
+		return pattern, nil
+	default:
+		return "", fmt.Errorf("expected dietary-pattern=auto, vegan, vegetarian, or omnivore, got %q", value)
+	}
+}
+
+// B12AdequacyStatus rates a day's B12 as "adequate" or "low" against the RDA
+func B12AdequacyStatus(b12, rdaUg float64) string {
+	if b12 >= rdaUg {
+		return "adequate"
+	}
+	return "low"
+}
+
+// DetectDietaryPattern guesses the diet from the diary: omnivore if any meat or
+// fish was logged, vegetarian if only eggs or dairy (not plant milks and
+// butters), and otherwise vegan
+func DetectDietaryPattern(diary []FoodEntry) string {
+	pattern := dietVegan
+	for _, e := range diary {
+		name := strings.ToLower(e.FoodName)
+		if containsAny(name, meatKeywords) {
+			return dietOmnivore
+		}
+		for _, lookalike := range plantLookalikes {
+			name = strings.ReplaceAll(name, lookalike, "")
+		}
+		if containsAny(name, animalProductKeywords) {
+			pattern = dietVegetarian
+		}
+	}
+	return pattern
+}
+
+// applyB12 rates each day's B12 and summarizes intake, strongly recommending a
+// supplement when the diet is vegan and average intake is below the RDA
+func applyB12(records []DailyNutrition, diary []FoodEntry, pattern string) *B12Report {
+	if pattern == dietAuto {
+		pattern = DetectDietaryPattern(diary)
+	}
+	report := &B12Report{DietaryPattern: pattern}
+	var total float64
+	for i := range records {
+		records[i].B12Status = B12AdequacyStatus(records[i].B12, b12RDAUg)
+		if records[i].B12Status == "low" {
+			report.DaysBelowRDA++
+		}
+		total += records[i].B12
+	}
+	if len(records) > 0 {
+		report.AverageUg = total / float64(len(records))
+	}
+	if pattern == dietVegan && report.AverageUg < b12RDAUg {
+		report.Recommendation = fmt.Sprintf("Strongly recommended: take a B12 supplement. Plant foods provide almost no B12, and average intake of %.1f µg is below the %.1f µg RDA.", report.AverageUg, b12RDAUg)
+	}
+	return report
+}
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// maxPlausibleDailyCalories flags records that are almost certainly data errors
+const maxPlausibleDailyCalories = 10000.0
+
+// ValidateDailyNutrition returns the integrity issues in a record: a missing
+// or non YYYY-MM-DD date, negative nutrient values, or implausible calories
+func ValidateDailyNutrition(d DailyNutrition) []string {
+	var issues []string
+	if _, err := time.Parse("2006-01-02", d.Date); err != nil {
+		issues = append(issues, fmt.Sprintf("invalid date %q", d.Date))
+	}
+	names := []string{"calories", "fat", "carbs", "protein"}
+	for _, n := range optionalNutrients {
+		names = append(names, n.name)
+	}
+	for _, name := range names {
+		if field := nutrientField(&d, name); field != nil && *field < 0 {
+			issues = append(issues, fmt.Sprintf("negative %s %g", name, *field))
+		}
+	}
+	if d.Calories > maxPlausibleDailyCalories {
+		issues = append(issues, fmt.Sprintf("implausible calories %g", d.Calories))
+	}
+	return issues
+}
+
+// validateOutputFile parses a saved JSON output as the array of days and
+// returns its issues, each prefixed with the record's date
+func validateOutputFile(path string) ([]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var days []DailyNutrition
+	if err := json.Unmarshal(data, &days); err != nil {
+		return nil, fmt.Errorf("parsing as an array of days: %v", err)
+	}
+
+	var issues []string
+	seen := make(map[string]bool, len(days))
+	for i, d := range days {
+		if seen[d.Date] {
+			issues = append(issues, fmt.Sprintf("record %d: duplicate date %s", i, d.Date))
+		}
+		seen[d.Date] = true
+		for _, issue := range ValidateDailyNutrition(d) {
+			issues = append(issues, fmt.Sprintf("record %d (%s): %s", i, d.Date, issue))
+		}
+	}
+	return issues, nil
+}
+
+// BatchValidate validates every *.json file in dir, writing each failing
+// file's problems to w. It returns the number of files checked and failed.
+func BatchValidate(dir string, w io.Writer) (checked, failed int, err error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return 0, 0, err
+	}
+	sort.Strings(paths)
+
+	for _, path := range paths {
+		checked++
+		issues, err := validateOutputFile(path)
+		if err != nil {
+			issues = []string{err.Error()}
+		}
+		if len(issues) == 0 {
+			continue
+		}
+		failed++
+		fmt.Fprintf(w, "%s:\n", path)
+		for _, issue := range issues {
+			fmt.Fprintf(w, "  %s\n", issue)
+		}
+	}
+	return checked, failed, nil
+}
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"context"
+	"math"
+	"sort"
+	"strings"
+	"time"
+)
+
+// kgPerLb converts pounds to kilograms
+const kgPerLb = 0.45359237
+
+// BiometricEntry represents a single biometric measurement (weight, body fat, etc.)
+type BiometricEntry struct {
+	Date   string  `json:"date"`
+	Metric string  `json:"metric"`
+	Unit   string  `json:"unit"`
+	Amount float64 `json:"amount"`
+}
+
+// WeightMeasurement is a weight in kilograms on a given date
+type WeightMeasurement struct {
+	Date     string  `json:"date"`
+	WeightKg float64 `json:"weight_kg"`
+}
+
+// fetchBiometrics exports the biometric records for the date range
+func fetchBiometrics(ctx context.Context, client Client, start, end time.Time) ([]BiometricEntry, error) {
+	records, err := client.ExportBiometricRecordsParsedWithLocation(ctx, start, end, time.Local)
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]BiometricEntry, 0, len(records))
+	for _, r := range records {
+		entries = append(entries, BiometricEntry{
+			Date:   r.RecordedTime.Format("2006-01-02"),
+			Metric: r.Metric,
+			Unit:   r.Unit,
+			Amount: r.Amount,
+		})
+	}
+	return entries, nil
+}
+
+// weightSeries returns the weight measurements in kilograms sorted by date,
+// keeping the last measurement for days with several
+func weightSeries(entries []BiometricEntry) []WeightMeasurement {
+	byDate := make(map[string]float64)
+	for _, e := range entries {
+		if !strings.EqualFold(e.Metric, "weight") || e.Amount <= 0 {
+			continue
+		}
+		byDate[e.Date] = toKg(e.Amount, e.Unit)
+	}
+
+	series := make([]WeightMeasurement, 0, len(byDate))
+	for date, kg := range byDate {
+		series = append(series, WeightMeasurement{Date: date, WeightKg: kg})
+	}
+	sort.Slice(series, func(i, j int) bool {
+		return series[i].Date < series[j].Date
+	})
+	return series
+}
+
+// toKg converts a weight to kilograms based on its unit
+func toKg(amount float64, unit string) float64 {
+	switch strings.ToLower(strings.TrimSpace(unit)) {
+	case "lb", "lbs", "pound", "pounds":
+		return amount * kgPerLb
+	}
+	return amount
+}
+
+// nearestWeight returns the weight measured closest to date
+func nearestWeight(date string, series []WeightMeasurement) (float64, bool) {
+	target, err := time.Parse("2006-01-02", date)
+	if err != nil || len(series) == 0 {
+		return 0, false
+	}
+
+	best := 0.0
+	bestGap := math.Inf(1)
+	for _, w := range series {
+		measured, err := time.Parse("2006-01-02", w.Date)
+		if err != nil {
+			continue
+		}
+		if gap := math.Abs(target.Sub(measured).Hours()); gap < bestGap {
+			best = w.WeightKg
+			bestGap = gap
+		}
+	}
+	return best, !math.IsInf(bestGap, 1)
+}
@@ -0,0 +1,44 @@
+package main
+
+import "strings"
+
+// Biotin thresholds: the adult AI, and the daily intake above which biotin
+// from food is flagged as high
+const (
+	biotinAIUg   = 30.0
+	biotinHighUg = 300.0
+)
+
+// BiotinStatus rates a day's biotin as "low" below the AI, "high" above
+// 300µg, or "adequate"
+func BiotinStatus(ug float64) string {
+	switch {
+	case ug < biotinAIUg:
+		return "low"
+	case ug > biotinHighUg:
+		return "high"
+	default:
+		return "adequate"
+	}
+}
+
+// isRawEggWhite reports whether the entry is raw egg white, whose avidin
+// binds biotin and blocks its absorption
+func isRawEggWhite(e FoodEntry) bool {
+	name := strings.ToLower(e.FoodName)
+	return strings.Contains(name, "egg white") && strings.Contains(name, "raw")
+}
+
+// applyBiotin rates each day's biotin and flags days with raw egg white in the diary
+func applyBiotin(records []DailyNutrition, diary []FoodEntry) {
+	byDate := groupEntriesByDate(diary)
+	for i := range records {
+		records[i].BiotinStatus = BiotinStatus(records[i].Biotin)
+		for _, e := range byDate[records[i].Date] {
+			if isRawEggWhite(e) {
+				records[i].RawEggWhite = true
+				break
+			}
+		}
+	}
+}
@@ -0,0 +1,165 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+)
+
+// defaultActivityMultipliers are the standard TDEE multipliers for -compute-bmr
+const defaultActivityMultipliers = "sedentary=1.2,lightly_active=1.375,moderately_active=1.55,very_active=1.725,extra_active=1.9"
+
+// EnergyEstimate is the -compute-bmr output: BMR and TDEE at each activity level
+type EnergyEstimate struct {
+	BMR  float64            `json:"bmr"`
+	TDEE map[string]float64 `json:"tdee"`
+}
+
+// MifflinStJeorBMR estimates basal metabolic rate in kcal/day:
+// 10 × weight + 6.25 × height - 5 × age, plus 5 for men or minus 161 for women
+func MifflinStJeorBMR(weightKg, heightCm, age float64, sex string) (float64, error) {
+	if weightKg <= 0 || heightCm <= 0 || age <= 0 {
+		return 0, fmt.Errorf("weight, height, and age must be positive")
+	}
+	bmr := 10*weightKg + 6.25*heightCm - 5*age
+	switch sex {
+	case "male":
+		return bmr + 5, nil
+	case "female":
+		return bmr - 161, nil
+	default:
+		return 0, fmt.Errorf("expected sex male or female, got %q", sex)
+	}
+}
+
+// parseActivityMultipliers parses the -activity-multipliers value, e.g.
+// "sedentary=1.2,lightly_active=1.375"
+func parseActivityMultipliers(value string) (map[string]float64, error) {
+	pairs, err := parseKeyValues(value)
+	if err != nil {
+		return nil, err
+	}
+	multipliers := make(map[string]float64, len(pairs))
+	for level, raw := range pairs {
+		m, err := strconv.ParseFloat(raw, 64)
+		if err != nil || m <= 0 {
+			return nil, fmt.Errorf("invalid multiplier for %s: %q", level, raw)
+		}
+		multipliers[level] = m
+	}
+	return multipliers, nil
+}
+
+// EstimateEnergy computes BMR and the TDEE at each activity multiplier
+func EstimateEnergy(weightKg, heightCm, age float64, sex string, multipliers map[string]float64) (EnergyEstimate, error) {
+	bmr, err := MifflinStJeorBMR(weightKg, heightCm, age, sex)
+	if err != nil {
+		return EnergyEstimate{}, err
+	}
+	estimate := EnergyEstimate{BMR: bmr, TDEE: make(map[string]float64, len(multipliers))}
+	for level, m := range multipliers {
+		estimate.TDEE[level] = bmr * m
+	}
+	return estimate, nil
+}
+
+// HarrisBenedictBMR estimates basal metabolic rate in kcal/day with the
+// Roza & Shizgal (1984) revision of the Harris-Benedict equation
+func HarrisBenedictBMR(weightKg, heightCm, age float64, sex string) (float64, error) {
+	if weightKg <= 0 || heightCm <= 0 || age <= 0 {
+		return 0, fmt.Errorf("weight, height, and age must be positive")
+	}
+	switch sex {
+	case "male":
+		return 88.362 + 13.397*weightKg + 4.799*heightCm - 5.677*age, nil
+	case "female":
+		return 447.593 + 9.247*weightKg + 3.098*heightCm - 4.330*age, nil
+	default:
+		return 0, fmt.Errorf("expected sex male or female, got %q", sex)
+	}
+}
+
+// KatchMcArdleBMR estimates basal metabolic rate in kcal/day from lean body
+// mass: 370 + 21.6 × weight × (1 - body fat%/100)
+func KatchMcArdleBMR(weightKg, bodyFatPct float64) float64 {
+	return 370 + 21.6*weightKg*(1-bodyFatPct/100)
+}
+
+// BMRComparisonReport is the BMR from each equation and the spread between them.
+// Katch-McArdle is only computed when body fat is known.
+type BMRComparisonReport struct {
+	WeightKg       float64  `json:"weight_kg"`
+	BodyFatPct     float64  `json:"body_fat_pct,omitempty"`
+	MifflinStJeor  float64  `json:"mifflin_st_jeor"`
+	HarrisBenedict float64  `json:"harris_benedict"`
+	KatchMcArdle   *float64 `json:"katch_mcardle,omitempty"`
+	Min            float64  `json:"min"`
+	Max            float64  `json:"max"`
+	Range          float64  `json:"range"`
+}
+
+// BMRComparison computes BMR with Mifflin-St Jeor, Harris-Benedict, and,
+// when bodyFatPct is between 0 and 100, Katch-McArdle
+func BMRComparison(weightKg, heightCm, age float64, sex string, bodyFatPct float64) (BMRComparisonReport, error) {
+	mifflin, err := MifflinStJeorBMR(weightKg, heightCm, age, sex)
+	if err != nil {
+		return BMRComparisonReport{}, err
+	}
+	harris, err := HarrisBenedictBMR(weightKg, heightCm, age, sex)
+	if err != nil {
+		return BMRComparisonReport{}, err
+	}
+	report := BMRComparisonReport{
+		WeightKg:       weightKg,
+		MifflinStJeor:  mifflin,
+		HarrisBenedict: harris,
+		Min:            math.Min(mifflin, harris),
+		Max:            math.Max(mifflin, harris),
+	}
+	if bodyFatPct > 0 && bodyFatPct < 100 {
+		katch := KatchMcArdleBMR(weightKg, bodyFatPct)
+		report.BodyFatPct = bodyFatPct
+		report.KatchMcArdle = &katch
+		report.Min = math.Min(report.Min, katch)
+		report.Max = math.Max(report.Max, katch)
+	}
+	report.Range = report.Max - report.Min
+	return report, nil
+}
+
+// latestMetric returns the most recent positive amount logged for the
+// biometric, converting weights to kg, or false when none was logged
+func latestMetric(entries []BiometricEntry, name string) (float64, bool) {
+	latest := BiometricEntry{}
+	for _, e := range filterMetric(entries, name) {
+		if e.Amount > 0 && e.Date >= latest.Date {
+			latest = e
+		}
+	}
+	if latest.Date == "" {
+		return 0, false
+	}
+	if name == "weight" {
+		return toKg(latest.Amount, latest.Unit), true
+	}
+	return latest.Amount, true
+}
+
+// compareBMR compares the BMR equations using the most recent logged weight
+// and body fat, falling back to weightKg and bodyFatPct
+func compareBMR(biometrics []BiometricEntry, weightKg, heightCm, age float64, sex string, bodyFatPct float64) (*BMRComparisonReport, error) {
+	if logged, ok := latestMetric(biometrics, "weight"); ok {
+		weightKg = logged
+	}
+	if logged, ok := latestMetric(biometrics, "body fat"); ok {
+		bodyFatPct = logged
+	}
+	if weightKg <= 0 {
+		return nil, fmt.Errorf("no weight logged; set -weight-kg")
+	}
+	report, err := BMRComparison(weightKg, heightCm, age, sex, bodyFatPct)
+	if err != nil {
+		return nil, err
+	}
+	return &report, nil
+}
@@ -0,0 +1,139 @@
+package main
+
+// caffeinePerServing is the approximate caffeine, in mg, in one serving of
+// common drinks and supplements. The serving sizes are in caffeineServingSize.
+// Keys are matched as substrings of lowercased diary food names, so
+// decaffeinated and caffeine-free versions have their own zero entries.
+var caffeinePerServing = map[string]float64{
+	"coffee":         95,
+	"cold brew":      155,
+	"espresso":       63,
+	"americano":      126,
+	"latte":          126,
+	"cappuccino":     126,
+	"decaf":          2,
+	"decaffeinated":  2,
+	"decaf coffee":   2,
+	"creamer":        0,
+	"coffee cake":    0,
+	"black tea":      47,
+	"tea, brewed":    47,
+	"earl grey":      47,
+	"chai":           47,
+	"oolong":         38,
+	"green tea":      28,
+	"matcha":         70,
+	"iced tea":       26,
+	"herbal tea":     0,
+	"yerba mate":     80,
+	"cola":           34,
+	"coke":           34,
+	"diet coke":      46,
+	"pepsi":          38,
+	"mountain dew":   54,
+	"dr pepper":      41,
+	"caffeine free":  0,
+	"energy drink":   80,
+	"red bull":       80,
+	"monster energy": 160,
+	"pre-workout":    200,
+	"preworkout":     200,
+	"caffeine":       200,
+	"chocolate":      9,
+	"dark chocolate": 23,
+	"chocolate milk": 5,
+}
+
+// caffeineServingSize is the serving size for each caffeinePerServing food,
+// in ml for drinks and g for everything else. Pure caffeine is a 200 mg
+// (0.2 g) tablet.
+var caffeineServingSize = map[string]float64{
+	"coffee":         240,
+	"cold brew":      355,
+	"espresso":       30,
+	"americano":      355,
+	"latte":          355,
+	"cappuccino":     355,
+	"decaf":          240,
+	"decaffeinated":  240,
+	"decaf coffee":   240,
+	"creamer":        15,
+	"coffee cake":    56,
+	"black tea":      240,
+	"tea, brewed":    240,
+	"earl grey":      240,
+	"chai":           240,
+	"oolong":         240,
+	"green tea":      240,
+	"matcha":         240,
+	"iced tea":       240,
+	"herbal tea":     240,
+	"yerba mate":     240,
+	"cola":           355,
+	"coke":           355,
+	"diet coke":      355,
+	"pepsi":          355,
+	"mountain dew":   355,
+	"dr pepper":      355,
+	"caffeine free":  355,
+	"energy drink":   250,
+	"red bull":       250,
+	"monster energy": 473,
+	"pre-workout":    10,
+	"preworkout":     10,
+	"caffeine":       0.2,
+	"chocolate":      40,
+	"dark chocolate": 28,
+	"chocolate milk": 240,
+}
+
+// caffeineServings returns how many servings an entry is: its volume or
+// weight over the serving size, counting 1 ml as 1 g, or its amount when it
+// was logged in other units (e.g. "2 shots"), or 1 when no amount was logged
+func caffeineServings(e FoodEntry, servingSize float64) float64 {
+	if conv, ok := servingUnits[normalizeUnit(e.Unit)]; ok && e.Amount > 0 && servingSize > 0 {
+		return e.Amount * conv.factor / servingSize
+	}
+	if e.Amount > 0 {
+		return e.Amount
+	}
+	return 1
+}
+
+// DailyCaffeine estimates caffeine in mg from the entries whose food is in
+// caffeineTable, given in mg per serving
+func DailyCaffeine(entries []FoodEntry, caffeineTable map[string]float64) float64 {
+	var total float64
+	for _, e := range entries {
+		mg, ok := tableMatch(e.FoodName, caffeineTable)
+		if !ok {
+			continue
+		}
+		servingSize, _ := tableMatch(e.FoodName, caffeineServingSize)
+		total += mg * caffeineServings(e, servingSize)
+	}
+	return total
+}
+
+// CaffeineCategory buckets a day's caffeine as "low" (<200mg), "moderate"
+// (200-400mg), or "high" (>400mg, above the FDA's safe daily limit for adults)
+func CaffeineCategory(mg float64) string {
+	switch {
+	case mg < 200:
+		return "low"
+	case mg <= 400:
+		return "moderate"
+	default:
+		return "high"
+	}
+}
+
+// applyCaffeine totals each day's caffeine from its diary and categorizes it
+func applyCaffeine(records []DailyNutrition, diary []FoodEntry) {
+	byDate := groupEntriesByDate(diary)
+	for i := range records {
+		mg := DailyCaffeine(byDate[records[i].Date], caffeinePerServing)
+		records[i].Caffeine = &mg
+		records[i].CaffeineCategory = CaffeineCategory(mg)
+	}
+}
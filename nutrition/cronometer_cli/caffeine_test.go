@@ -0,0 +1,28 @@
+package main
+
+import (
+	"math"
+	"testing"
+)
+
+func TestDailyCaffeine(t *testing.T) {
+	tests := []struct {
+		name  string
+		entry FoodEntry
+		want  float64
+	}{
+		{"caffeine by mass in mg", FoodEntry{FoodName: "Caffeine", Amount: 200, Unit: "mg"}, 200},
+		{"caffeine by mass in g", FoodEntry{FoodName: "Caffeine, Anhydrous", Amount: 0.1, Unit: "g"}, 100},
+		{"caffeine tablets", FoodEntry{FoodName: "Caffeine Tablet", Amount: 2, Unit: "tablet"}, 400},
+		{"coffee by volume", FoodEntry{FoodName: "Coffee, Brewed", Amount: 480, Unit: "ml"}, 190},
+		{"unmatched food", FoodEntry{FoodName: "Oatmeal", Amount: 40, Unit: "g"}, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := DailyCaffeine([]FoodEntry{tt.entry}, caffeinePerServing)
+			if math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("DailyCaffeine = %g mg, want %g", got, tt.want)
+			}
+		})
+	}
+}
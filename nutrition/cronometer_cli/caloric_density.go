@@ -0,0 +1,72 @@
+package main
+
+// CaloricDensityReport is the spread of diary entries across caloric density
+// categories and the mean of each day's overall density
+type CaloricDensityReport struct {
+	Categories       map[string]int `json:"categories"`
+	MeanDailyDensity float64        `json:"mean_daily_kcal_per_g"`
+	// Unweighed counts entries logged in units without a known weight
+	Unweighed int `json:"unweighed"`
+}
+
+// entryGrams returns an entry's weight in grams, if it was logged in a mass unit
+func entryGrams(e FoodEntry) (float64, bool) {
+	conv, ok := servingUnits[normalizeUnit(e.Unit)]
+	if !ok || conv.dimension != "mass" || e.Amount <= 0 {
+		return 0, false
+	}
+	return e.Amount * conv.factor, true
+}
+
+// CaloricDensity returns an entry's kcal per gram, or 0 when its weight is unknown
+func CaloricDensity(entry FoodEntry) float64 {
+	grams, ok := entryGrams(entry)
+	if !ok {
+		return 0
+	}
+	return entry.Calories / grams
+}
+
+// CaloricDensityCategory classifies kcal per gram as "very_low" (<0.6),
+// "low" (0.6-1.5), "medium" (1.5-3.5), "high" (3.5-5.5), or "very_high" (>5.5)
+func CaloricDensityCategory(kcalPerG float64) string {
+	switch {
+	case kcalPerG < 0.6:
+		return "very_low"
+	case kcalPerG < 1.5:
+		return "low"
+	case kcalPerG < 3.5:
+		return "medium"
+	case kcalPerG <= 5.5:
+		return "high"
+	default:
+		return "very_high"
+	}
+}
+
+// caloricDensityReport classifies every weighed diary entry and averages each
+// day's calories per gram across its weighed entries
+func caloricDensityReport(diary []FoodEntry) *CaloricDensityReport {
+	report := &CaloricDensityReport{Categories: make(map[string]int)}
+	kcal := make(map[string]float64)
+	grams := make(map[string]float64)
+	for _, e := range diary {
+		g, ok := entryGrams(e)
+		if !ok {
+			report.Unweighed++
+			continue
+		}
+		report.Categories[CaloricDensityCategory(e.Calories/g)]++
+		kcal[e.Date] += e.Calories
+		grams[e.Date] += g
+	}
+
+	var sum float64
+	for date, g := range grams {
+		sum += kcal[date] / g
+	}
+	if len(grams) > 0 {
+		report.MeanDailyDensity = sum / float64(len(grams))
+	}
+	return report
+}
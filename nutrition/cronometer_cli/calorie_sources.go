@@ -0,0 +1,48 @@
+package main
+
+import "sort"
+
+// CalorieSource is a food's share of total calorie intake over the date range
+type CalorieSource struct {
+	FoodName         string  `json:"food_name"`
+	TotalCalories    float64 `json:"total_calories"`
+	Occurrences      int     `json:"occurrences"`
+	PctOfTotalIntake float64 `json:"pct_of_total_intake"`
+}
+
+// TopCalorieSources ranks foods by total calories and returns the top n.
+// A non-positive n returns every food.
+func TopCalorieSources(entries []FoodEntry, n int) []CalorieSource {
+	byFood := make(map[string]*CalorieSource)
+	var total float64
+	for _, e := range entries {
+		key := foodKey(e.FoodName)
+		source, ok := byFood[key]
+		if !ok {
+			source = &CalorieSource{FoodName: e.FoodName}
+			byFood[key] = source
+		}
+		source.TotalCalories += e.Calories
+		source.Occurrences++
+		total += e.Calories
+	}
+
+	sources := make([]CalorieSource, 0, len(byFood))
+	for _, source := range byFood {
+		if total > 0 {
+			source.PctOfTotalIntake = source.TotalCalories / total * 100
+		}
+		sources = append(sources, *source)
+	}
+	sort.Slice(sources, func(i, j int) bool {
+		if sources[i].TotalCalories != sources[j].TotalCalories {
+			return sources[i].TotalCalories > sources[j].TotalCalories
+		}
+		return sources[i].FoodName < sources[j].FoodName
+	})
+
+	if n > 0 && len(sources) > n {
+		sources = sources[:n]
+	}
+	return sources
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"math"
+	"testing"
+)
+
+func TestTopCalorieSourcesPercentages(t *testing.T) {
+	entries := []FoodEntry{
+		{FoodName: "Rice", Calories: 410},
+		{FoodName: "Chicken Breast", Calories: 330},
+		{FoodName: "rice", Calories: 205},
+		{FoodName: "Olive Oil", Calories: 119},
+		{FoodName: "Broccoli", Calories: 55},
+		{FoodName: "Black Coffee", Calories: 2},
+	}
+	tests := []struct {
+		name    string
+		n       int
+		wantLen int
+		wantSum float64
+	}{
+		{"every food", 0, 5, 100},
+		{"top two", 2, 2, (615 + 330) / 1121.0 * 100},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sources := TopCalorieSources(entries, tt.n)
+			if len(sources) != tt.wantLen {
+				t.Fatalf("got %d sources, want %d", len(sources), tt.wantLen)
+			}
+			var sum float64
+			for _, s := range sources {
+				sum += s.PctOfTotalIntake
+			}
+			if math.Abs(sum-tt.wantSum) > 0.01 {
+				t.Errorf("percentages sum to %g, want %g", sum, tt.wantSum)
+			}
+		})
+	}
+}
+
+func TestTopCalorieSourcesRanking(t *testing.T) {
+	entries := []FoodEntry{
+		{FoodName: "Apple", Calories: 95},
+		{FoodName: "Pasta", Calories: 400},
+		{FoodName: "pasta", Calories: 100},
+	}
+	sources := TopCalorieSources(entries, 0)
+	if sources[0].FoodName != "Pasta" || sources[0].Occurrences != 2 || sources[0].TotalCalories != 500 {
+		t.Errorf("top source = %+v, want both pasta entries combined", sources[0])
+	}
+}
+
+func TestTopCalorieSourcesNoCalories(t *testing.T) {
+	sources := TopCalorieSources([]FoodEntry{{FoodName: "Water"}}, 0)
+	if len(sources) != 1 || sources[0].PctOfTotalIntake != 0 {
+		t.Errorf("sources = %+v, want water at 0%%", sources)
+	}
+}
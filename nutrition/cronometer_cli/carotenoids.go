@@ -0,0 +1,51 @@
+package main
+
+// AREDS2 lutein and zeaxanthin amounts in mg/day
+const (
+	areds2CarotenoidMg = 10.0
+	lowCarotenoidMg    = 6.0
+)
+
+// EyeHealthFlag compares a day's lutein and zeaxanthin with the AREDS2 amount
+type EyeHealthFlag struct {
+	CombinedCarotenoidMg float64 `json:"combined_carotenoid_mg"`
+	AREDS2Fraction       float64 `json:"areds2_fraction"`
+	Status               string  `json:"status"`
+	Recommendation       string  `json:"recommendation,omitempty"`
+}
+
+// CombinedCarotenoidMg is the day's lutein plus zeaxanthin in mg. Cronometer
+// usually exports the two as one column, which is used when neither
+// separate column was.
+func CombinedCarotenoidMg(d DailyNutrition) float64 {
+	ug := d.Lutein + d.Zeaxanthin
+	if ug == 0 {
+		ug = d.LuteinZeaxanthin
+	}
+	return ug / 1000
+}
+
+// checkCarotenoids rates a day's carotenoids as "adequate" (10mg or more),
+// "moderate", or "low" (below 6mg, with a recommendation)
+func checkCarotenoids(d DailyNutrition) EyeHealthFlag {
+	mg := CombinedCarotenoidMg(d)
+	flag := EyeHealthFlag{CombinedCarotenoidMg: mg, AREDS2Fraction: mg / areds2CarotenoidMg}
+	switch {
+	case mg >= areds2CarotenoidMg:
+		flag.Status = "adequate"
+	case mg >= lowCarotenoidMg:
+		flag.Status = "moderate"
+	default:
+		flag.Status = "low"
+		flag.Recommendation = "Add leafy greens such as kale, spinach, or collards, or egg yolks, toward the 10mg/day AREDS2 amount"
+	}
+	return flag
+}
+
+// applyCarotenoids attaches an eye health flag to each day
+func applyCarotenoids(records []DailyNutrition) {
+	for i := range records {
+		flag := checkCarotenoids(records[i])
+		records[i].EyeHealth = &flag
+	}
+}
@@ -0,0 +1,156 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Dual-axis chart layout in pixels
+const (
+	chartWidth     = 900
+	chartHeight    = 420
+	chartMarginX   = 70
+	chartMarginTop = 40
+	chartMarginBot = 50
+	chartGridLines = 5
+)
+
+// Series colors
+const (
+	caloriesColor = "#1f77b4"
+	weightColor   = "#ff7f0e"
+)
+
+// axisRange returns a padded min and max for values, never a zero-width range
+func axisRange(values []float64) (float64, float64) {
+	lo, hi := math.Inf(1), math.Inf(-1)
+	for _, v := range values {
+		lo, hi = math.Min(lo, v), math.Max(hi, v)
+	}
+	if math.IsInf(lo, 1) {
+		return 0, 1
+	}
+	pad := (hi - lo) * 0.05
+	if pad == 0 {
+		pad = 1
+	}
+	return lo - pad, hi + pad
+}
+
+// GenerateDualAxisSVG writes an SVG chart of daily calories on the left axis
+// and LOESS-smoothed weight (kg) on the right axis, sharing a date axis, with
+// horizontal gridlines and a legend
+func GenerateDualAxisSVG(calories []DailyNutrition, weights []BiometricEntry, smoothBandwidth float64, outputPath string) error {
+	days := make([]DailyNutrition, len(calories))
+	copy(days, calories)
+	sort.Slice(days, func(i, j int) bool {
+		return days[i].Date < days[j].Date
+	})
+	series := weightSeries(weights)
+
+	var dates []time.Time
+	parse := func(date string) (time.Time, error) {
+		t, err := time.Parse("2006-01-02", date)
+		if err != nil {
+			return t, fmt.Errorf("parsing date %q: %v", date, err)
+		}
+		dates = append(dates, t)
+		return t, nil
+	}
+	calorieDates := make([]time.Time, len(days))
+	calorieValues := make([]float64, len(days))
+	for i, d := range days {
+		t, err := parse(d.Date)
+		if err != nil {
+			return err
+		}
+		calorieDates[i], calorieValues[i] = t, d.Calories
+	}
+	weightDates := make([]time.Time, len(series))
+	weightDays := make([]float64, len(series))
+	weightValues := make([]float64, len(series))
+	for i, w := range series {
+		t, err := parse(w.Date)
+		if err != nil {
+			return err
+		}
+		weightDates[i], weightValues[i] = t, w.WeightKg
+	}
+	if len(dates) == 0 {
+		return fmt.Errorf("no calories or weights to chart")
+	}
+
+	first, last := dates[0], dates[0]
+	for _, t := range dates {
+		if t.Before(first) {
+			first = t
+		}
+		if t.After(last) {
+			last = t
+		}
+	}
+	span := last.Sub(first).Hours() / 24
+	if span == 0 {
+		span = 1
+	}
+	for i, t := range weightDates {
+		weightDays[i] = t.Sub(first).Hours() / 24
+	}
+	smoothed := loess(weightDays, weightValues, smoothBandwidth)
+
+	plotW := float64(chartWidth - 2*chartMarginX)
+	plotH := float64(chartHeight - chartMarginTop - chartMarginBot)
+	xPos := func(t time.Time) float64 {
+		return chartMarginX + t.Sub(first).Hours()/24/span*plotW
+	}
+	calLo, calHi := axisRange(calorieValues)
+	weightLo, weightHi := axisRange(smoothed)
+	yPos := func(v, lo, hi float64) float64 {
+		return chartMarginTop + (1-(v-lo)/(hi-lo))*plotH
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "<svg xmlns=\"http://www.w3.org/2000/svg\" width=\"%d\" height=\"%d\" font-family=\"sans-serif\" font-size=\"12\">\n", chartWidth, chartHeight)
+	fmt.Fprintf(&b, "<rect width=\"%d\" height=\"%d\" fill=\"white\"/>\n", chartWidth, chartHeight)
+
+	// Gridlines with labels on both axes
+	for i := 0; i <= chartGridLines; i++ {
+		share := float64(i) / chartGridLines
+		y := chartMarginTop + (1-share)*plotH
+		fmt.Fprintf(&b, "<line x1=\"%d\" y1=\"%.1f\" x2=\"%d\" y2=\"%.1f\" stroke=\"#ddd\"/>\n", chartMarginX, y, chartWidth-chartMarginX, y)
+		fmt.Fprintf(&b, "<text x=\"%d\" y=\"%.1f\" text-anchor=\"end\" fill=\"%s\">%.0f</text>\n", chartMarginX-6, y+4, caloriesColor, calLo+share*(calHi-calLo))
+		fmt.Fprintf(&b, "<text x=\"%d\" y=\"%.1f\" fill=\"%s\">%.1f</text>\n", chartWidth-chartMarginX+6, y+4, weightColor, weightLo+share*(weightHi-weightLo))
+	}
+	bottom := chartHeight - chartMarginBot
+	fmt.Fprintf(&b, "<text x=\"%d\" y=\"%d\">%s</text>\n", chartMarginX, bottom+20, first.Format("2006-01-02"))
+	fmt.Fprintf(&b, "<text x=\"%d\" y=\"%d\" text-anchor=\"end\">%s</text>\n", chartWidth-chartMarginX, bottom+20, last.Format("2006-01-02"))
+	fmt.Fprintf(&b, "<text x=\"%d\" y=\"%d\" fill=\"%s\">Calories (kcal)</text>\n", 10, chartMarginTop-20, caloriesColor)
+	fmt.Fprintf(&b, "<text x=\"%d\" y=\"%d\" text-anchor=\"end\" fill=\"%s\">Weight (kg)</text>\n", chartWidth-10, chartMarginTop-20, weightColor)
+
+	polyline := func(times []time.Time, values []float64, lo, hi float64, color string) {
+		if len(times) == 0 {
+			return
+		}
+		points := make([]string, len(times))
+		for i := range times {
+			points[i] = fmt.Sprintf("%.1f,%.1f", xPos(times[i]), yPos(values[i], lo, hi))
+		}
+		fmt.Fprintf(&b, "<polyline fill=\"none\" stroke=\"%s\" stroke-width=\"2\" points=\"%s\"/>\n", color, strings.Join(points, " "))
+	}
+	polyline(calorieDates, calorieValues, calLo, calHi, caloriesColor)
+	polyline(weightDates, smoothed, weightLo, weightHi, weightColor)
+
+	// Legend
+	legendX := chartMarginX + 10
+	fmt.Fprintf(&b, "<line x1=\"%d\" y1=\"%d\" x2=\"%d\" y2=\"%d\" stroke=\"%s\" stroke-width=\"2\"/>\n", legendX, chartMarginTop+12, legendX+20, chartMarginTop+12, caloriesColor)
+	fmt.Fprintf(&b, "<text x=\"%d\" y=\"%d\">Daily calories</text>\n", legendX+26, chartMarginTop+16)
+	fmt.Fprintf(&b, "<line x1=\"%d\" y1=\"%d\" x2=\"%d\" y2=\"%d\" stroke=\"%s\" stroke-width=\"2\"/>\n", legendX, chartMarginTop+30, legendX+20, chartMarginTop+30, weightColor)
+	fmt.Fprintf(&b, "<text x=\"%d\" y=\"%d\">Smoothed weight</text>\n", legendX+26, chartMarginTop+34)
+	b.WriteString("</svg>\n")
+
+	return os.WriteFile(outputPath, []byte(b.String()), 0644)
+}
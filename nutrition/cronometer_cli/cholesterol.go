@@ -0,0 +1,63 @@
+package main
+
+// satFatCalorieLimit is the Dietary Guidelines' cap on saturated fat as a
+// share of calories
+const satFatCalorieLimit = 0.10
+
+// LipidSupportProfile describes how well a day's diet supports healthy LDL
+// cholesterol
+type LipidSupportProfile struct {
+	SolubleFiberG         float64 `json:"soluble_fiber_g"`
+	BileAcidBinding       string  `json:"bile_acid_binding"`
+	SaturatedFatG         float64 `json:"saturated_fat_g"`
+	SaturatedFatCalories  float64 `json:"saturated_fat_calorie_share"`
+	SaturatedFatOverLimit bool    `json:"saturated_fat_over_limit"`
+	Profile               string  `json:"profile"`
+}
+
+// BileAcidBindingPotential rates soluble fiber's bile acid binding: "high"
+// above 10g, "moderate" from 5g to 10g, and "low" below 5g
+func BileAcidBindingPotential(solubleFiber float64) string {
+	switch {
+	case solubleFiber > 10:
+		return "high"
+	case solubleFiber >= 5:
+		return "moderate"
+	default:
+		return "low"
+	}
+}
+
+// lipidSupportProfile combines bile acid binding with saturated fat. The day
+// is "supportive" when binding is at least moderate and saturated fat is
+// within 10% of calories, "unsupportive" when neither holds, and "mixed" otherwise.
+func lipidSupportProfile(d DailyNutrition) LipidSupportProfile {
+	profile := LipidSupportProfile{
+		SolubleFiberG:   d.SolubleFiber,
+		BileAcidBinding: BileAcidBindingPotential(d.SolubleFiber),
+		SaturatedFatG:   d.SaturatedFat,
+	}
+	if d.Calories > 0 {
+		profile.SaturatedFatCalories = kcalPerGramFat * d.SaturatedFat / d.Calories
+	}
+	profile.SaturatedFatOverLimit = profile.SaturatedFatCalories > satFatCalorieLimit
+
+	binds := profile.BileAcidBinding != "low"
+	switch {
+	case binds && !profile.SaturatedFatOverLimit:
+		profile.Profile = "supportive"
+	case !binds && profile.SaturatedFatOverLimit:
+		profile.Profile = "unsupportive"
+	default:
+		profile.Profile = "mixed"
+	}
+	return profile
+}
+
+// applyLipidSupport attaches a cholesterol support profile to each day
+func applyLipidSupport(records []DailyNutrition) {
+	for i := range records {
+		profile := lipidSupportProfile(records[i])
+		records[i].LipidSupport = &profile
+	}
+}
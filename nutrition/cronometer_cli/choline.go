@@ -0,0 +1,53 @@
+package main
+
+import "fmt"
+
+// cholineAIMg is the adult choline adequate intake by sex
+var cholineAIMg = map[string]float64{"male": 550, "female": 425}
+
+// highMethionineG is the daily methionine above which choline matters most:
+// both are methyl donors, and a methionine-heavy, choline-poor diet leans on
+// methionine's homocysteine pathway
+const highMethionineG = 2.0
+
+// CholineReport is a day's choline against the AI, with its ratio to methionine
+type CholineReport struct {
+	AIMg float64 `json:"ai_mg"`
+	// Status is "adequate" at or above the AI, else "low"
+	Status string `json:"status"`
+	// CholineMethionineRatio is mg of choline per g of methionine, left out
+	// when no methionine was logged
+	CholineMethionineRatio   *float64 `json:"choline_methionine_ratio,omitempty"`
+	HighMethionineLowCholine bool     `json:"high_methionine_low_choline,omitempty"`
+}
+
+// parseCholineSex validates the -sex value used by -check-choline
+func parseCholineSex(sex string) error {
+	if _, ok := cholineAIMg[sex]; !ok {
+		return fmt.Errorf("-check-choline requires -sex male or female, got %q", sex)
+	}
+	return nil
+}
+
+// CholineStatus rates a day's choline as "adequate" or "low" against the AI
+func CholineStatus(choline, ai float64) string {
+	if choline >= ai {
+		return "adequate"
+	}
+	return "low"
+}
+
+// applyCholine adds a choline report to each record
+func applyCholine(records []DailyNutrition, sex string) {
+	ai := cholineAIMg[sex]
+	for i := range records {
+		d := &records[i]
+		report := &CholineReport{AIMg: ai, Status: CholineStatus(d.Choline, ai)}
+		if d.Methionine > 0 {
+			ratio := d.Choline / d.Methionine
+			report.CholineMethionineRatio = &ratio
+		}
+		report.HighMethionineLowCholine = d.Methionine > highMethionineG && report.Status == "low"
+		d.CholineReport = report
+	}
+}
@@ -0,0 +1,52 @@
+package main
+
+import "math"
+
+// collagenGlycineG is the daily glycine, from diet and synthesis, estimated to
+// support full collagen synthesis (Meléndez-Hevia et al., J Biosci 2009).
+// Typical diets supply only 2-3 g.
+const collagenGlycineG = 15.0
+
+// CollagenReport is a day's glycine against the collagen synthesis target
+type CollagenReport struct {
+	GlycineG float64 `json:"glycine_g"`
+	// Adequacy is the fraction of the 15 g target met, capped at 1
+	Adequacy float64 `json:"adequacy"`
+	Rating   string  `json:"collagen_support_rating"`
+}
+
+// GlycineSynthesisAdequacy returns the fraction of the 15 g/day glycine target met, capped at 1
+func GlycineSynthesisAdequacy(glycineG float64) float64 {
+	if glycineG <= 0 {
+		return 0
+	}
+	return math.Min(glycineG/collagenGlycineG, 1)
+}
+
+// CollagenSupportRating rates glycine adequacy as "low" (under a third of the
+// target, typical of most diets), "moderate" (under two thirds), "good", or
+// "optimal" when the target is met
+func CollagenSupportRating(adequacy float64) string {
+	switch {
+	case adequacy >= 1:
+		return "optimal"
+	case adequacy >= 2.0/3:
+		return "good"
+	case adequacy >= 1.0/3:
+		return "moderate"
+	default:
+		return "low"
+	}
+}
+
+// applyCollagen adds a collagen report to each record with glycine logged
+func applyCollagen(records []DailyNutrition) {
+	for i := range records {
+		d := &records[i]
+		if d.Glycine <= 0 {
+			continue
+		}
+		adequacy := GlycineSynthesisAdequacy(d.Glycine)
+		d.Collagen = &CollagenReport{GlycineG: d.Glycine, Adequacy: adequacy, Rating: CollagenSupportRating(adequacy)}
+	}
+}
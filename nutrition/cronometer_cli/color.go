@@ -0,0 +1,48 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"os"
+)
+
+// ANSI escape codes used for colored terminal output
+const (
+	ansiReset  = "\033[0m"
+	ansiRed    = "\033[31m"
+	ansiGreen  = "\033[32m"
+	ansiYellow = "\033[33m"
+)
+
+// atGoalTolerance is how close (as a fraction of the goal) a value must be to count as at goal
+const atGoalTolerance = 0.05
+
+// ColorizeValue formats value with format and colors it yellow when within 5%
+// of the goal. Otherwise a minimum goal is green above the goal and red below,
+// and a limit goal the reverse.
+func ColorizeValue(value, goal float64, minimum bool, format string) string {
+	text := fmt.Sprintf(format, value)
+	if goal <= 0 {
+		return text
+	}
+
+	var color string
+	switch {
+	case math.Abs(value-goal) <= goal*atGoalTolerance:
+		color = ansiYellow
+	case (value > goal) == minimum:
+		color = ansiGreen
+	default:
+		color = ansiRed
+	}
+	return color + text + ansiReset
+}
+
+// isTerminal reports whether f is attached to a terminal rather than a pipe or file
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
@@ -0,0 +1,103 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestColorizeValue(t *testing.T) {
+	tests := []struct {
+		name        string
+		value, goal float64
+		minimum     bool
+		want        string
+	}{
+		{"above a minimum", 150, 100, true, ansiGreen + "150" + ansiReset},
+		{"below a minimum", 50, 100, true, ansiRed + "50" + ansiReset},
+		{"above a limit", 150, 100, false, ansiRed + "150" + ansiReset},
+		{"below a limit", 50, 100, false, ansiGreen + "50" + ansiReset},
+		{"within 5% above", 105, 100, false, ansiYellow + "105" + ansiReset},
+		{"within 5% below", 95, 100, true, ansiYellow + "95" + ansiReset},
+		{"no goal", 150, 0, true, "150"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ColorizeValue(tt.value, tt.goal, tt.minimum, "%g"); got != tt.want {
+				t.Errorf("ColorizeValue = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTableOutputColorCodes(t *testing.T) {
+	days := []DailyNutrition{{
+		Date:     "2024-03-01",
+		Calories: 2400,
+		Protein:  90,
+		Goals:    map[string]GoalResult{"calories": {Target: 2000}, "protein": {Target: 150}},
+	}}
+	tests := []struct {
+		name      string
+		color     bool
+		wantCodes bool
+	}{
+		{"color off", false, false},
+		{"color on", true, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			if err := (tableFormatter{Color: tt.color}).Format(&out, Result{Days: days}); err != nil {
+				t.Fatal(err)
+			}
+			if got := strings.Contains(out.String(), "\033["); got != tt.wantCodes {
+				t.Errorf("output has color codes = %v, want %v:\n%s", got, tt.wantCodes, out.String())
+			}
+		})
+	}
+}
+
+func TestIsTerminalFalseForFilesAndPipes(t *testing.T) {
+	f, err := os.Create(filepath.Join(t.TempDir(), "out.txt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	if isTerminal(f) {
+		t.Error("isTerminal reported a regular file as a terminal")
+	}
+
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+	defer w.Close()
+	if isTerminal(w) {
+		t.Error("isTerminal reported a pipe as a terminal")
+	}
+}
+
+func TestTableColorsLimitGoals(t *testing.T) {
+	days := []DailyNutrition{{
+		Date:     "2024-03-01",
+		Calories: 2400,
+		Protein:  160,
+		Goals:    map[string]GoalResult{"calories": {Target: 2000}, "protein": {Target: 150}},
+	}}
+	var out bytes.Buffer
+	if err := (tableFormatter{Color: true}).Format(&out, Result{Days: days}); err != nil {
+		t.Fatal(err)
+	}
+	// Over the calorie limit is a miss; over the protein minimum is a hit
+	if want := ansiRed + fmt.Sprintf("%10.1f", 2400.0) + ansiReset; !strings.Contains(out.String(), want) {
+		t.Errorf("calories over the limit not red:\n%q", out.String())
+	}
+	if want := ansiGreen + fmt.Sprintf("%10.1f", 160.0) + ansiReset; !strings.Contains(out.String(), want) {
+		t.Errorf("protein over the minimum not green:\n%q", out.String())
+	}
+}
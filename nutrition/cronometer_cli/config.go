@@ -0,0 +1,391 @@
+package main
+
+import (
+	"flag"
+	"time"
+)
+
+// Config holds the command line options for a pipeline run
+type Config struct {
+	Username    string
+	Password    string
+	Start       string
+	End         string
+	DateLayout  string
+	DiffAccount string
+
+	VaultAddr     string
+	VaultPath     string
+	VaultFallback bool
+
+	// Goals are the positive -goal-* values; ExplicitGoals are every -goal-*
+	// flag given on the command line, so an explicit zero can unset a stored goal
+	Goals            map[string]float64
+	ExplicitGoals    map[string]float64
+	StoreGoals       string
+	LoadGoals        string
+	ComputeFiberGoal bool
+
+	ExcludeDates        string
+	SkipToday           bool
+	StrictDates         bool
+	Clamp               string
+	Saturate            bool
+	ExpectedMinCalories float64
+	Recompute           bool
+	Labels              dayLabels
+
+	LoggingQuality      bool
+	Preferences         bool
+	MinOccurrences      int
+	TopSources          int
+	FoodFrequency       int
+	GroupByFood         bool
+	MonotonyWindow      int
+	ProteinDistribution bool
+	ProteinTiming       bool
+	GHSupport           bool
+	NightEating         bool
+	CutoffHour          int
+	MealSpacing         bool
+	MinGapHours         float64
+	MaxGapHours         float64
+	ProteinQuality      bool
+	GlycemicLoad        bool
+	ComputeNPU          bool
+	CheckPS             bool
+	CheckPC             bool
+	CheckAGE            bool
+	DetectHighGI        bool
+	MergeDiary          bool
+	RandomMeal          bool
+	LongevityScore      bool
+	FoodPairing         bool
+	Meal                string
+	Supplements         bool
+	SupplementDepends   bool
+	LocalFood           bool
+	NormalizeServings   bool
+	InferPortions       bool
+	CaloricDensity      bool
+	CategoryBudget      string
+	NutrientEfficiency  bool
+	TrackHydration      bool
+	TrackCaffeine       bool
+	CheckAlcohol        bool
+	CheckElectrolytes   bool
+	GoalWaterMl         float64
+	AddLocalFood        string
+	AdherenceTrend      bool
+	AdherenceStreak     bool
+	GoalTimeline        bool
+	TargetAdherence     float64
+	Suggest             bool
+	Advise              string
+	SimulateSwap        string
+	MacroRatios         string
+	MacroDistribution   bool
+	NutritionFacts      string
+	DetectFasting       bool
+	TrackEatingWindow   bool
+	Phytochemicals      bool
+	DiversityIndex      bool
+	PlantKeywords       string
+	FastingProtocol     string
+	ValidateGoals       bool
+	DetectStressEating  bool
+	StressTags          string
+	CheckTryptophan     bool
+	PositiveMoodTags    string
+	// Seed is the -seed value, or the current time when not given
+	Seed int64
+
+	CheckSodium       bool
+	CheckCardio       bool
+	CheckOmega3       bool
+	CheckVitaminD     bool
+	CheckCalcium      bool
+	CheckIron         bool
+	CheckBoneHealth   bool
+	CheckZincCopper   bool
+	CheckLeucine      bool
+	CheckFolate       bool
+	CheckVitaminK     bool
+	CheckCholine      bool
+	CreatineSynthesis bool
+	DietaryCreatine   bool
+	CheckGlycine      bool
+	CheckAntioxidants bool
+	CheckInflammation bool
+	ThermicEffect     bool
+	ComputeSatiety    bool
+	ComputeRER        bool
+	ComputeFII        bool
+	Micronutrients    bool
+	CheckPRAL         bool
+	KidneyStoneRisk   bool
+	CheckLipids       bool
+	CheckCarotenoids  bool
+	TraceMinerals     bool
+	CheckBiotin       bool
+	IronVitaminC      bool
+	// CheckMagnesium is the -check-magnesium value, e.g. "sex=male"
+	CheckMagnesium string
+	// CheckB12 is the -check-b12 value, e.g. "dietary-pattern=auto"
+	CheckB12    string
+	KetoMetrics bool
+	// DiffFile is a previous JSON output to compare the current days against
+	DiffFile string
+	// Latitude is nil when -latitude was not given
+	Latitude *float64
+
+	NormalizeWeight      bool
+	WeightKg             float64
+	TargetWeightLbs      float64
+	TDEE                 float64
+	CrossValidateWeights bool
+	ComputeLeanMass      bool
+	RecommendGoals       bool
+	DetectDietBreaks     string
+	SeasonalAnalysis     bool
+	CompareToPopulation  bool
+	Age                  int
+	Sex                  string
+	ComputeBMR           bool
+	CompareBMR           bool
+	ComputeREE           bool
+	VO2Max               float64
+	BodyFatPct           float64
+	HeightCm             float64
+	ActivityMultipliers  string
+	WeightChart          string
+	MonthlyReport        string
+	ReportPath           string
+	SmoothBandwidth      float64
+	PredictNextWeek      bool
+	Alpha                float64
+	Rolling              int
+	CI                   float64
+	CumulativeProtein    bool
+	CumulativeMicros     bool
+	Rebalance            bool
+	WeeklyBudget         float64
+	DaysLeft             int
+
+	ImportMFP     string
+	Timeout       time.Duration
+	ChunkDays     int
+	MaxRetries    int
+	ChunkStrategy string
+	GraphQL       string
+	GyroscopeKey  string
+
+	OutputFormat string
+	ESIndex      string
+	SummaryOnly  bool
+	WeeklyTrend  bool
+	Color        bool
+	ExportReadme bool
+	Verbose      bool
+	// BatchValidate is a directory of saved JSON outputs to lint
+	BatchValidate string
+}
+
+// parseFlags parses the command line into a Config
+func parseFlags() Config {
+	var cfg Config
+	flag.StringVar(&cfg.Username, "username", "", "Cronometer username")
+	flag.StringVar(&cfg.Password, "password", "", "Cronometer password")
+	flag.StringVar(&cfg.Start, "start", "", "Start date (YYYY-MM-DD)")
+	flag.StringVar(&cfg.End, "end", "", "End date (YYYY-MM-DD)")
+	flag.StringVar(&cfg.DateLayout, "format-date", "2006-01-02", "Go time layout used for dates in the output")
+	flag.StringVar(&cfg.VaultAddr, "vault-addr", "", "Vault server address to read the Cronometer credentials from (uses VAULT_TOKEN)")
+	flag.StringVar(&cfg.VaultPath, "vault-path", "", "Vault secret path holding username and password, e.g. secret/data/cronometer")
+	flag.BoolVar(&cfg.VaultFallback, "vault-fallback", false, "Use -username and -password if Vault can't be read")
+	flag.StringVar(&cfg.DiffAccount, "diff-account", "", "Second account (username:password) to compare against")
+	flag.StringVar(&cfg.ExcludeDates, "exclude-dates", "", "Comma-separated dates (YYYY-MM-DD) to remove before analysis")
+	flag.BoolVar(&cfg.Recompute, "recompute-calories", false, "Recompute calories from macros using 4/9/4 Atwater factors")
+	flag.BoolVar(&cfg.LoggingQuality, "logging-quality", false, "Score how specifically foods were logged in the diary")
+	flag.BoolVar(&cfg.Saturate, "saturate-incomplete-days", false, "Scale macros on days below -expected-min-calories up to a full day")
+	flag.Float64Var(&cfg.ExpectedMinCalories, "expected-min-calories", 1200, "Calories below which a day is treated as incompletely logged")
+	flag.BoolVar(&cfg.Preferences, "preferences", false, "Learn food preferences from the diary and add them to the summary")
+	flag.IntVar(&cfg.MinOccurrences, "min-occurrences", 3, "Minimum times a food must be logged to count as a preference")
+	goalFlags := map[string]*float64{
+		"calories": flag.Float64("goal-calories", 0, "Daily calorie limit"),
+		"protein":  flag.Float64("goal-protein", 0, "Daily protein minimum (g)"),
+		"fat":      flag.Float64("goal-fat", 0, "Daily fat limit (g)"),
+		"carbs":    flag.Float64("goal-carbs", 0, "Daily carbs limit (g)"),
+		"fiber":    flag.Float64("goal-fiber", 0, "Daily fiber minimum (g)"),
+	}
+	flag.StringVar(&cfg.StoreGoals, "store-goals", "", "Save the -goal-* values as a named goal set and exit")
+	flag.StringVar(&cfg.LoadGoals, "load-goals", "", "Apply a stored goal set; explicit -goal-* flags take precedence")
+	flag.BoolVar(&cfg.ComputeFiberGoal, "compute-fiber-goal", false, "Set each day's fiber goal to 14g per 1000 kcal logged")
+	flag.IntVar(&cfg.TopSources, "top-calorie-sources", 0, "Add the top N foods by calorie contribution to the summary")
+	flag.BoolVar(&cfg.SupplementDepends, "detect-supplement-dependency", false, "Add whether vitamin D, B12, and omega-3 goals are only met with supplements to the summary")
+	flag.BoolVar(&cfg.Supplements, "track-supplements", false, "Report food and supplement contributions separately")
+	flag.StringVar(&cfg.Advise, "advise", "", "Print per-meal targets for the rest of today (meals=N); requires goals")
+	flag.BoolVar(&cfg.AdherenceTrend, "adherence-trend", false, "Add weekly goal adherence with trend arrows to the summary")
+	flag.BoolVar(&cfg.CheckSodium, "check-sodium", false, "Categorize each day's sodium intake for heart health")
+	flag.BoolVar(&cfg.CheckCardio, "check-cardiovascular", false, "Add each day's potassium-to-sodium ratio and a cardiovascular risk flag; implies -check-sodium")
+	flag.StringVar(&cfg.GyroscopeKey, "gyroscope-key", "", "Gyroscope API key; exports the days as Gyroscope nutrition events")
+	flag.BoolVar(&cfg.CheckOmega3, "check-omega-3", false, "Add an omega-3 (ALA/EPA/DHA) report with an AHA advisory to each day")
+	flag.BoolVar(&cfg.Suggest, "suggest", false, "Suggest habit-stacking foods for goals met on fewer than half of the days")
+	flag.StringVar(&cfg.OutputFormat, "output", "json", "Output format: json, powerbi (flat array for Power BI), table, or elasticsearch (_bulk NDJSON)")
+	flag.StringVar(&cfg.ESIndex, "es-index", "", "Elasticsearch index name for -output elasticsearch")
+	flag.BoolVar(&cfg.Color, "color", false, "Color table values against goals (disabled when stdout is not a terminal)")
+	flag.BoolVar(&cfg.NormalizeWeight, "normalize-by-weight", false, "Add per-kilogram values using the nearest logged weight")
+	flag.Float64Var(&cfg.WeightKg, "weight-kg", 0, "Bodyweight in kg used when no weight is logged")
+	flag.BoolVar(&cfg.LocalFood, "local-food", false, "Fill zero-nutrient diary entries from the local food database")
+	flag.StringVar(&cfg.AddLocalFood, "add-local-food", "", "Add a food to the local database (name=...,serving=...,calories=...,protein=...,fat=...,carbs=...) and exit")
+	flag.IntVar(&cfg.ChunkDays, "chunk-days", 0, "Split the nutrition export into requests of this many days (0 = one request)")
+	flag.IntVar(&cfg.MaxRetries, "max-retries", 2, "Times a failed export request is retried")
+	flag.StringVar(&cfg.ChunkStrategy, "chunk-strategy", "date", "How exports are fetched: date (split by -chunk-days) or type (nutrition, diary, and biometrics concurrently)")
+	flag.StringVar(&cfg.Clamp, "clamp", "", "Upper bounds applied before analysis (e.g. calories=5000,protein=500)")
+	flag.Float64Var(&cfg.TargetWeightLbs, "target-weight-lbs", 0, "Project the date weight reaches this target (lbs); requires -tdee")
+	flag.Float64Var(&cfg.TDEE, "tdee", 0, "Estimated daily energy expenditure (kcal) used to compute the calorie deficit")
+	flag.BoolVar(&cfg.SkipToday, "skip-today", false, "Drop today's partially logged data before any processing")
+	flag.BoolVar(&cfg.StrictDates, "strict-dates", false, "Drop rows dated outside the requested start and end dates")
+	flag.BoolVar(&cfg.Verbose, "verbose", false, "Print extra processing details to stderr")
+	flag.IntVar(&cfg.Rolling, "rolling", 0, "Add a trailing N-day average to each day with a full window")
+	flag.Float64Var(&cfg.CI, "ci", 0, "Add confidence intervals at this level (e.g. 0.95) to -rolling averages")
+	flag.IntVar(&cfg.FoodFrequency, "food-log-frequency", 0, "Add the N most frequently logged foods with times per week to the summary")
+	flag.BoolVar(&cfg.CrossValidateWeights, "cross-validate-weights", false, "Report weigh-ins that changed more than 2 lbs per day from the previous one")
+	flag.BoolVar(&cfg.GroupByFood, "group-by-food", false, "Add per-food macro totals over the date range to the summary")
+	flag.StringVar(&cfg.DetectDietBreaks, "detect-diet-breaks", "", "Report maintenance-calorie breaks during a cut (tdee=N[,deficit=N,break=N,min-days=N])")
+	flag.StringVar(&cfg.GraphQL, "graphql", "", "Run the GraphQL query in this file against Cronometer, print the JSON response, and exit")
+	flag.BoolVar(&cfg.AdherenceStreak, "goal-adherence-streak", false, "Add the current and longest streaks of days meeting every goal to the summary")
+	flag.BoolVar(&cfg.ComputeLeanMass, "compute-lean-mass", false, "Add a lean body mass series from logged weight and body fat to the summary")
+	flag.BoolVar(&cfg.Rebalance, "rebalance", false, "Print the daily calories that keep this week within -weekly-budget")
+	flag.Float64Var(&cfg.WeeklyBudget, "weekly-budget", 0, "Weekly calorie budget used by -rebalance")
+	flag.IntVar(&cfg.DaysLeft, "days-left", 0, "Days left in the week used by -rebalance")
+	flag.BoolVar(&cfg.RecommendGoals, "recommend-goals", false, "Recommend a calorie goal change when weight is falling faster than 1% per week")
+	flag.BoolVar(&cfg.CheckVitaminD, "check-vitamin-d", false, "Add a vitamin D report with estimated sun synthesis to each day; requires -latitude")
+	latitude := flag.Float64("latitude", 0, "Latitude in degrees (negative for south) used by -check-vitamin-d")
+	flag.BoolVar(&cfg.ExportReadme, "export-readme", false, "Write SCHEMA.md documenting every output field and exit")
+	flag.BoolVar(&cfg.DetectFasting, "detect-fasting-days", false, "Add days eaten within an 8-hour window and a fasting consistency score to the summary")
+	flag.BoolVar(&cfg.WeeklyTrend, "weekly-trend-summary", false, "Print one object per ISO week, with means, totals, goal adherence, and a calorie trend, instead of the days")
+	flag.BoolVar(&cfg.SummaryOnly, "summary-only", false, "Print only the summary object, with period averages, instead of the days")
+	flag.StringVar(&cfg.ImportMFP, "import-mfp", "", "Merge a MyFitnessPal measurement export into the biometrics (file=path[,weight-unit=lbs|kg])")
+	flag.BoolVar(&cfg.SeasonalAnalysis, "seasonal-analysis", false, "Add mean nutrition per meteorological season to the summary")
+	timeoutSeconds := flag.Int("timeout-seconds", 30, "Seconds before a Cronometer request is abandoned (0 = no timeout)")
+	flag.StringVar(&cfg.CheckMagnesium, "check-magnesium", "", "Rate each day's magnesium and correlate it with logged sleep (sex=male|female)")
+	flag.StringVar(&cfg.DiffFile, "diff-file", "", "Output the changes since a previously saved JSON output file")
+	flag.BoolVar(&cfg.KetoMetrics, "keto-metrics", false, "Add net carbs, the keto ratio, and a blood ketone estimate to each day")
+	flag.IntVar(&cfg.MonotonyWindow, "monotony", 0, "Add a daily food monotony score over a rolling window of N days to the summary")
+	flag.BoolVar(&cfg.ProteinDistribution, "protein-distribution", false, "Add how evenly protein is split across meals to the summary; requires -weight-kg")
+	flag.BoolVar(&cfg.DetectHighGI, "detect-high-gi", false, "List each day's high glycemic index foods and flag days where they supply over 30% of carbs")
+	flag.BoolVar(&cfg.MergeDiary, "merge-diary-and-nutrition", false, "Output each day's nutrition together with its diary entries")
+	flag.BoolVar(&cfg.GoalTimeline, "goal-timeline", false, "Add the consecutive successful days each goal needs to reach -target-adherence to the summary")
+	flag.Float64Var(&cfg.TargetAdherence, "target-adherence", 0.8, "Adherence rate (0-1] that -goal-timeline projects toward")
+	flag.BoolVar(&cfg.RandomMeal, "random-meal", false, "Suggest one of your frequent meals at random, weighted by how often it was logged")
+	flag.Int64Var(&cfg.Seed, "seed", 0, "Random seed for -random-meal; the same seed gives the same suggestion")
+	flag.BoolVar(&cfg.ValidateGoals, "validate-goals", false, "Warn on stderr about implausible or unsafe goal values")
+	flag.BoolVar(&cfg.DetectStressEating, "detect-stress-eating", false, "Compare calories on days whose notes mention a -stress-tags mood against other days")
+	flag.StringVar(&cfg.StressTags, "stress-tags", "stressed,anxious", "Comma-separated mood words in Cronometer notes that mark stress days")
+	flag.BoolVar(&cfg.CheckTryptophan, "check-tryptophan", false, "Add each day's tryptophan adequacy and ratio to LNAA, and correlate tryptophan with -positive-mood-tags days when notes are logged")
+	flag.StringVar(&cfg.PositiveMoodTags, "positive-mood-tags", "happy,calm,great,good", "Comma-separated mood words in Cronometer notes that mark positive mood days")
+	flag.BoolVar(&cfg.CheckCalcium, "check-calcium-vitamin-d", false, "Rate each day's calcium absorption against its vitamin D")
+	flag.BoolVar(&cfg.LongevityScore, "longevity-score", false, "Add a Blue Zones inspired longevity score to each day and its monthly trend to the summary")
+	flag.BoolVar(&cfg.FoodPairing, "food-pairing", false, "Add the 10 food pairs most often logged in the same meal to the summary")
+	flag.StringVar(&cfg.Meal, "meal", "", "Diary meal group for -food-pairing, e.g. Breakfast (default all meals)")
+	flag.StringVar(&cfg.CheckB12, "check-b12", "", "Rate each day's B12 and recommend a supplement for low intake on a vegan diet (dietary-pattern=auto|vegan|vegetarian|omnivore)")
+	flag.BoolVar(&cfg.CumulativeMicros, "cumulative-micros", false, "Add 7-day rolling totals of vitamin K, B12, and vitamin D to each day")
+	flag.BoolVar(&cfg.CumulativeProtein, "cumulative-protein", false, "Add the running protein total since the first day in the range to each day")
+	flag.BoolVar(&cfg.NightEating, "night-eating-warning", false, "Add each day's calories logged after -cutoff-hour, warning when they are over 20% of the day")
+	flag.IntVar(&cfg.CutoffHour, "cutoff-hour", 21, "Hour of the day (0-23) after which -night-eating-warning counts calories")
+	flag.BoolVar(&cfg.MealSpacing, "check-meal-spacing", false, "Add days with gaps between meals outside -min-gap-hours to -max-gap-hours, and a meal spacing score, to the summary")
+	flag.Float64Var(&cfg.MinGapHours, "min-gap-hours", 4, "Shortest healthy gap between meals for -check-meal-spacing")
+	flag.Float64Var(&cfg.MaxGapHours, "max-gap-hours", 6, "Longest healthy gap between meals for -check-meal-spacing")
+	flag.BoolVar(&cfg.DiversityIndex, "diversity-index", false, "Add a Shannon diversity index of calories across foods to each day, with a weekly trend in the summary")
+	flag.BoolVar(&cfg.Phytochemicals, "phytochemical-estimate", false, "Add daily plant food variety scores and a weekly trend to the summary as a proxy for phytochemical intake")
+	flag.StringVar(&cfg.PlantKeywords, "plant-keywords", "", "File of plant food name keywords, one per line, for -phytochemical-estimate (default: a built-in list)")
+	flag.BoolVar(&cfg.TrackEatingWindow, "track-eating-window", false, "Add each day's first and last meal times and eating window hours (-1 when no times were logged)")
+	flag.BoolVar(&cfg.GHSupport, "check-gh-support", false, "Add each day's arginine plus ornithine, flagging days with more than 3g in the 2 hours before a logged workout")
+	flag.BoolVar(&cfg.ProteinTiming, "protein-timing", false, "Add the protein eaten within 2 hours after each logged workout to the summary")
+	flag.BoolVar(&cfg.CaloricDensity, "caloric-density-report", false, "Add the spread of diary entries across caloric density categories and the mean daily kcal per gram to the summary")
+	flag.BoolVar(&cfg.NutrientEfficiency, "nutrient-calorie-efficiency", false, "Add the 10 diary foods with the most and least micronutrient %RDA per calorie to the summary")
+	flag.StringVar(&cfg.CategoryBudget, "food-category-budget", "", "Compare each food category's share of diary calories to a minimum target (vegetables=30pct,proteins=30pct,...)")
+	flag.BoolVar(&cfg.InferPortions, "infer-portion-size", false, "Replace diary amounts logged generically (no amount, the 100g default, or 1 serving) with standard portions")
+	flag.BoolVar(&cfg.NormalizeServings, "normalize-servings", false, "Convert each diary food to the unit it is most often logged in before the diary analyses")
+	flag.BoolVar(&cfg.TrackHydration, "track-hydration", false, "Add each day's water from the diary and whether it met -goal-water-ml")
+	flag.BoolVar(&cfg.TrackCaffeine, "track-caffeine", false, "Add each day's caffeine from coffee, tea, soda, energy drinks, and pre-workout in the diary, categorized as low, moderate, or high")
+	flag.BoolVar(&cfg.CheckAlcohol, "check-alcohol", false, "Add each day's alcohol units from the diary, with weekly units and an NHS drinking category in the summary")
+	flag.BoolVar(&cfg.CheckElectrolytes, "check-electrolytes", false, "Add a sodium, potassium, magnesium, and calcium balance report to each day, using -track-hydration water when given")
+	flag.Float64Var(&cfg.GoalWaterMl, "goal-water-ml", 2000, "Daily water goal in ml for -track-hydration")
+	flag.StringVar(&cfg.FastingProtocol, "fasting-protocol", "", "Check adherence to a fasting protocol such as 16:8, 18:6, 5:2, or alternate-day")
+	flag.BoolVar(&cfg.CheckIron, "check-iron", false, "Estimate each day's heme and non-heme iron and how much is absorbed")
+	flag.StringVar(&cfg.MonthlyReport, "monthly-pdf-report", "", "Write a PDF report for this month (YYYY-MM), fetching the whole month unless -start or -end is given")
+	flag.StringVar(&cfg.ReportPath, "report-path", "", "Path for -monthly-pdf-report (default: nutrition-report-YYYY-MM.pdf)")
+	flag.StringVar(&cfg.WeightChart, "smoothed-weight-chart", "", "Write an SVG chart of daily calories and LOESS-smoothed weight to this path")
+	flag.Float64Var(&cfg.SmoothBandwidth, "smooth-bandwidth", 0.3, "Share of weigh-ins (0-1] in each LOESS fit for -smoothed-weight-chart")
+	flag.BoolVar(&cfg.PredictNextWeek, "predict-next-week", false, "Add a forecast of the next 7 days' calories and macros from exponential smoothing to the summary")
+	flag.Float64Var(&cfg.Alpha, "alpha", 0.3, "Smoothing factor (0-1] for -predict-next-week; higher values follow recent days more closely")
+	flag.BoolVar(&cfg.CheckBoneHealth, "check-bone-health", false, "Add each day's calcium-to-phosphorus ratio and a bone health flag")
+	flag.BoolVar(&cfg.CheckLeucine, "check-leucine", false, "Add each meal's leucine to the summary, flagging meals below the 2.5g muscle protein synthesis threshold")
+	flag.BoolVar(&cfg.CheckCholine, "check-choline", false, "Add each day's choline against the -sex adequate intake and its ratio to methionine")
+	flag.BoolVar(&cfg.CheckFolate, "check-folate", false, "Add each day's dietary folate equivalents, counting folic acid at 1.7x, against the 400µg DFE RDA")
+	flag.BoolVar(&cfg.CheckVitaminK, "check-vitamin-k", false, "Add each day's vitamin K1 against the 120µg AI and K2 against the 45µg research dose")
+	flag.BoolVar(&cfg.DietaryCreatine, "check-dietary-creatine", false, "Add each day's creatine from meat and fish, suggesting supplements on vegetarian or vegan days with almost none")
+	flag.BoolVar(&cfg.CreatineSynthesis, "creatine-synthesis", false, "Add each day's estimated endogenous creatine synthesis from glycine, arginine, and methionine")
+	flag.BoolVar(&cfg.CheckZincCopper, "check-zinc-copper", false, "Add each day's zinc-to-copper ratio, warning outside the ideal 8:1 to 15:1 range")
+	flag.BoolVar(&cfg.CheckGlycine, "check-glycine", false, "Rate each day's glycine against the 15g needed for collagen synthesis")
+	flag.BoolVar(&cfg.ThermicEffect, "compute-thermic-effect", false, "Add the thermic effect of food and net calories after it to each day")
+	flag.BoolVar(&cfg.CheckPRAL, "check-pral", false, "Add a potential renal acid load (PRAL) score and classification to each day")
+	flag.BoolVar(&cfg.KidneyStoneRisk, "check-kidney-stone-risk", false, "Add a kidney stone risk from protein acid load, PRAL, and -track-hydration water to each day")
+	flag.BoolVar(&cfg.CheckLipids, "check-cholesterol-support", false, "Add a cholesterol support profile from soluble fiber and saturated fat to each day")
+	flag.BoolVar(&cfg.ComputeRER, "compute-rer", false, "Add a respiratory exchange ratio estimated from carbs and fat, and the primary fuel source, to each day")
+	flag.BoolVar(&cfg.ComputeFII, "compute-fii", false, "Add a Food Insulin Index estimated from protein, carbs, fat, and fiber to each day")
+	flag.BoolVar(&cfg.ComputeSatiety, "compute-satiety", false, "Add a satiety factor and hunger risk to each day, flagging low-calorie, low-satiety days as at risk of overeating")
+	flag.BoolVar(&cfg.CheckCarotenoids, "check-carotenoids", false, "Add an eye health flag comparing lutein and zeaxanthin with the AREDS2 10mg/day to each day")
+	flag.BoolVar(&cfg.Micronutrients, "micronutrient-score", false, "Add the mean RDA adequacy of the exported micronutrients to each day, with a 30-day trend in the summary")
+	flag.BoolVar(&cfg.CheckInflammation, "check-inflammatory-index", false, "Add a Dietary Inflammatory Index score and interpretation to each day")
+	flag.BoolVar(&cfg.IronVitaminC, "check-iron-vitamin-c-pairing", false, "Add advice on pairing iron with vitamin C to days with iron below the RDA")
+	flag.BoolVar(&cfg.TraceMinerals, "check-trace-minerals", false, "Add manganese status and a trace mineral adequacy report for manganese, chromium, molybdenum, and iodine to each day")
+	flag.BoolVar(&cfg.CheckBiotin, "check-biotin", false, "Add each day's biotin status against the 30µg AI, flagging intake above 300µg and raw egg white in the diary")
+	flag.BoolVar(&cfg.CheckAntioxidants, "check-antioxidants", false, "Add an antioxidant score from vitamin C, vitamin E, and selenium to each day")
+	flag.BoolVar(&cfg.CheckPC, "check-pc", false, "Add an estimate of phosphatidylcholine from diary foods to each day, flagging days below 500mg")
+	flag.BoolVar(&cfg.CheckPS, "check-ps", false, "Add an estimate of phosphatidylserine from diary foods to each day, flagging days below 100mg")
+	flag.BoolVar(&cfg.CheckAGE, "check-age", false, "Add an advanced glycation end product score from the cooking methods in food names to each day")
+	flag.BoolVar(&cfg.ComputeNPU, "compute-npu", false, "Add a net protein utilization estimate from the amino acid profile to each day")
+	flag.BoolVar(&cfg.GlycemicLoad, "check-glycemic-load", false, "Add each day's glycemic load from diary carbs and a GI table, with a low/medium/high category")
+	flag.BoolVar(&cfg.ProteinQuality, "protein-quality", false, "Add the protein-weighted DIAAS of each day's diary foods")
+	flag.BoolVar(&cfg.CompareToPopulation, "compare-to-population", false, "Add NHANES percentiles of your average intake to the summary; requires -age and -sex")
+	flag.IntVar(&cfg.Age, "age", 0, "Age in years for -compare-to-population, -compute-bmr, and -compare-bmr")
+	flag.StringVar(&cfg.Sex, "sex", "", "Sex (male or female) for -compare-to-population, -compute-bmr, -compare-bmr, -check-choline, and -check-alcohol")
+	flag.BoolVar(&cfg.ComputeBMR, "compute-bmr", false, "Print Mifflin-St Jeor BMR and TDEE estimates from -weight-kg, -height-cm, -age, and -sex as JSON, and exit")
+	flag.BoolVar(&cfg.CompareBMR, "compare-bmr", false, "Add BMR from the Mifflin-St Jeor, Harris-Benedict, and Katch-McArdle equations to the summary, using the latest logged weight and body fat")
+	flag.BoolVar(&cfg.ComputeREE, "compute-ree", false, "Add resting energy expenditure (from VO2 max, else Mifflin-St Jeor with -height-cm, -age, and -sex, else one MET from weight alone) and the TDEE observed from the weight trend to the summary")
+	flag.Float64Var(&cfg.VO2Max, "vo2max", 0, "VO2 max in ml/kg/min for -compute-ree (default: the latest logged VO2 Max)")
+	flag.Float64Var(&cfg.BodyFatPct, "body-fat-pct", 0, "Body fat percentage for -compare-bmr when none is logged")
+	flag.Float64Var(&cfg.HeightCm, "height-cm", 0, "Height in cm for -compute-bmr, -compare-bmr, and -compute-ree")
+	flag.StringVar(&cfg.ActivityMultipliers, "activity-multipliers", defaultActivityMultipliers, "TDEE multiplier for each activity level in -compute-bmr (level=N,...)")
+	flag.StringVar(&cfg.BatchValidate, "batch-validate", "", "Check every saved *.json output in this directory for parse errors and invalid records, and exit")
+	flag.StringVar(&cfg.NutritionFacts, "export-nutrition-facts", "", "Write an FDA Nutrition Facts label for each food in the diary to this path as a JSON array")
+	flag.StringVar(&cfg.MacroRatios, "compare-macro-ratios", "", "Compare each day's share of calories from each macro to a target split (carbs=N,protein=N,fat=N)")
+	flag.BoolVar(&cfg.MacroDistribution, "macro-distribution", false, "Add each day's share of calories from each macro against the DRI ranges, with the fraction of days within all three in the summary")
+	flag.StringVar(&cfg.SimulateSwap, "simulate-swap", "", "Estimate the daily macro change from replacing one food with another (remove=X:add=Y)")
+	cfg.Labels = dayLabels{}
+	flag.Var(cfg.Labels, "label-day", "Label a date in the output (date=label, repeatable)")
+	flag.Parse()
+
+	set := make(map[string]bool)
+	flag.Visit(func(f *flag.Flag) {
+		set[f.Name] = true
+	})
+
+	cfg.Goals = collectGoals(goalFlags)
+	cfg.ExplicitGoals = make(map[string]float64)
+	for name, value := range goalFlags {
+		if set["goal-"+name] {
+			cfg.ExplicitGoals[name] = *value
+		}
+	}
+	cfg.Timeout = time.Duration(*timeoutSeconds) * time.Second
+	if !set["seed"] {
+		cfg.Seed = time.Now().UnixNano()
+	}
+	if set["latitude"] {
+		cfg.Latitude = latitude
+	}
+	return cfg
+}
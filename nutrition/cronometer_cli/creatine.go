@@ -0,0 +1,118 @@
+package main
+
+// Creatine synthesis model. Each creatine molecule (131 g/mol) takes one
+// glycine (75 g/mol), the amidino group of one arginine (174 g/mol), and a
+// methyl group from methionine (149 g/mol) via SAM, so each precursor caps the
+// creatine it could make. Adults synthesize about 1-2 g/day however much
+// precursor is available, so the estimate is capped at 2 g.
+const (
+	creatineMolarMass   = 131.13
+	glycineMolarMass    = 75.07
+	arginineMolarMass   = 174.2
+	methionineMolarMass = 149.21
+	maxCreatineSynthG   = 2.0
+)
+
+// EndogenousCreatineSynthesisPotential estimates daily creatine synthesis in
+// grams from dietary glycine, arginine, and methionine, limited by the
+// scarcest precursor and capped at the physiological 2 g/day
+func EndogenousCreatineSynthesisPotential(glycine, arginine, methionine float64) float64 {
+	potential := maxCreatineSynthG
+	for _, limit := range []float64{
+		glycine / glycineMolarMass * creatineMolarMass,
+		arginine / arginineMolarMass * creatineMolarMass,
+		methionine / methionineMolarMass * creatineMolarMass,
+	} {
+		if limit < potential {
+			potential = limit
+		}
+	}
+	if potential < 0 {
+		return 0
+	}
+	return potential
+}
+
+// applyCreatineSynthesis sets each day's creatine synthesis estimate, skipping
+// days missing any of the three amino acids. It returns how many days were skipped.
+func applyCreatineSynthesis(records []DailyNutrition) int {
+	skipped := 0
+	for i := range records {
+		d := &records[i]
+		if d.Glycine <= 0 || d.Arginine <= 0 || d.Methionine <= 0 {
+			skipped++
+			continue
+		}
+		potential := EndogenousCreatineSynthesisPotential(d.Glycine, d.Arginine, d.Methionine)
+		d.CreatineSynthesis = &potential
+	}
+	return skipped
+}
+
+// nearZeroCreatineG is the dietary creatine below which a vegetarian or vegan
+// day is flagged
+const nearZeroCreatineG = 0.1
+
+// creatineContent is the approximate creatine, in mg per gram, of common raw
+// meats and fish (Harris et al. 1997; Balsom et al. 1994). Cooking loses some,
+// so this is an upper estimate. Keys are matched as substrings of lowercased
+// diary food names.
+var creatineContent = map[string]float64{
+	"herring":  8.0,
+	"pork":     5.0,
+	"bacon":    5.0,
+	"beef":     4.5,
+	"steak":    4.5,
+	"salmon":   4.5,
+	"lamb":     4.5,
+	"veal":     4.5,
+	"tuna":     4.0,
+	"chicken":  3.4,
+	"turkey":   3.4,
+	"duck":     3.4,
+	"cod":      3.0,
+	"sardine":  4.0,
+	"mackerel": 4.0,
+	"shrimp":   0.2,
+	"milk":     0.1,
+}
+
+// DietaryCreatineReport is a day's estimated dietary creatine
+type DietaryCreatineReport struct {
+	EstimateG      float64 `json:"estimate_g"`
+	DietaryPattern string  `json:"dietary_pattern"`
+	Recommendation string  `json:"recommendation,omitempty"`
+}
+
+// DietaryCreatineEstimate estimates dietary creatine in grams from entries
+// whose food is in creatineTable, in mg per gram, and whose amount is logged by weight
+func DietaryCreatineEstimate(entries []FoodEntry, creatineTable map[string]float64) float64 {
+	var mg float64
+	for _, e := range entries {
+		mgPerG, ok := tableMatch(e.FoodName, creatineTable)
+		if !ok {
+			continue
+		}
+		if grams, ok := entryGrams(e); ok {
+			mg += mgPerG * grams
+		}
+	}
+	return mg / 1000
+}
+
+// applyDietaryCreatine estimates each day's dietary creatine and suggests
+// supplementing on vegetarian or vegan days with almost none
+func applyDietaryCreatine(records []DailyNutrition, diary []FoodEntry) {
+	byDate := groupEntriesByDate(diary)
+	for i := range records {
+		entries := byDate[records[i].Date]
+		report := &DietaryCreatineReport{
+			EstimateG:      DietaryCreatineEstimate(entries, creatineContent),
+			DietaryPattern: DetectDietaryPattern(entries),
+		}
+		if report.DietaryPattern != dietOmnivore && report.EstimateG < nearZeroCreatineG {
+			report.Recommendation = "Almost no dietary creatine on a " + report.DietaryPattern + " day; a creatine supplement (3-5 g/day) may be warranted"
+		}
+		records[i].DietaryCreatine = report
+	}
+}
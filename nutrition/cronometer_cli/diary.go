@@ -0,0 +1,141 @@
+package main
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/jrmycanady/gocronometer"
+)
+
+// FoodEntry represents a single food logged in the Cronometer diary
+type FoodEntry struct {
+	Date     string    `json:"date"`
+	Time     time.Time `json:"time"`
+	Meal     string    `json:"meal"`
+	FoodName string    `json:"food_name"`
+	Amount   float64   `json:"amount"`
+	Unit     string    `json:"unit"`
+	Category string    `json:"category"`
+	Calories float64   `json:"calories" unit:"kcal"`
+	Fat      float64   `json:"fat" unit:"g"`
+	Carbs    float64   `json:"carbs" unit:"g"`
+	Protein  float64   `json:"protein" unit:"g"`
+	Leucine  float64   `json:"leucine" unit:"g"`
+
+	// The rest of the FDA label nutrients, for -export-nutrition-facts
+	SaturatedFat float64 `json:"saturated_fat" unit:"g"`
+	TransFat     float64 `json:"trans_fat" unit:"g"`
+	Cholesterol  float64 `json:"cholesterol" unit:"mg"`
+	Sodium       float64 `json:"sodium" unit:"mg"`
+	Fiber        float64 `json:"fiber" unit:"g"`
+	Sugars       float64 `json:"sugars" unit:"g"`
+	AddedSugars  float64 `json:"added_sugars" unit:"g"`
+	VitaminD     float64 `json:"vitamin_d" unit:"IU"`
+	Calcium      float64 `json:"calcium" unit:"mg"`
+	Iron         float64 `json:"iron" unit:"mg"`
+	Potassium    float64 `json:"potassium" unit:"mg"`
+	// For -detect-supplement-dependency
+	B12    float64 `json:"b12" unit:"µg"`
+	Omega3 float64 `json:"omega_3" unit:"g"`
+	// Estimated from the day's totals by -check-gh-support
+	Arginine  float64 `json:"arginine,omitempty" unit:"g"`
+	Ornithine float64 `json:"ornithine,omitempty" unit:"g"`
+	// For -nutrient-calorie-efficiency
+	VitaminC   float64 `json:"vitamin_c" unit:"mg"`
+	VitaminE   float64 `json:"vitamin_e" unit:"mg"`
+	Folate     float64 `json:"folate" unit:"µg"`
+	Magnesium  float64 `json:"magnesium" unit:"mg"`
+	Zinc       float64 `json:"zinc" unit:"mg"`
+	Copper     float64 `json:"copper" unit:"mg"`
+	Selenium   float64 `json:"selenium" unit:"µg"`
+	Phosphorus float64 `json:"phosphorus" unit:"mg"`
+
+	LocalFood bool `json:"local_food,omitempty"`
+	Inferred  bool `json:"inferred,omitempty"`
+}
+
+// fetchDiary exports the food diary (servings) for the date range
+func fetchDiary(ctx context.Context, client Client, start, end time.Time) ([]FoodEntry, error) {
+	servings, err := client.ExportServingsParsedWithLocation(ctx, start, end, time.Local)
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]FoodEntry, 0, len(servings))
+	for _, s := range servings {
+		entries = append(entries, toFoodEntry(s))
+	}
+	return entries, nil
+}
+
+// toFoodEntry converts a gocronometer serving into a FoodEntry
+func toFoodEntry(s gocronometer.ServingRecord) FoodEntry {
+	return FoodEntry{
+		Date:     s.RecordedTime.Format("2006-01-02"),
+		Time:     s.RecordedTime,
+		Meal:     s.Group,
+		FoodName: s.FoodName,
+		Amount:   s.QuantityValue,
+		Unit:     s.QuantityUnits,
+		Category: s.Category,
+		Calories: s.EnergyKcal,
+		Fat:      s.FatG,
+		Carbs:    s.CarbsG,
+		Protein:  s.ProteinG,
+		Leucine:  s.LeucineG,
+
+		SaturatedFat: s.SaturatedG,
+		TransFat:     s.TransFatG,
+		Cholesterol:  s.CholesterolMg,
+		Sodium:       s.SodiumMg,
+		Fiber:        s.FiberG,
+		Sugars:       s.SugarsG,
+		AddedSugars:  s.AddedSugarsG,
+		VitaminD:     s.VitaminDUI,
+		Calcium:      s.CalciumMg,
+		Iron:         s.IronMg,
+		Potassium:    s.PotassiumMg,
+		B12:          s.B12Mg,
+		Omega3:       s.Omega3G,
+
+		VitaminC:   s.VitaminCMg,
+		VitaminE:   s.VitaminEMg,
+		Folate:     s.FolateUg,
+		Magnesium:  s.MagnesiumMg,
+		Zinc:       s.ZincMg,
+		Copper:     s.CopperMg,
+		Selenium:   s.SeleniumUg,
+		Phosphorus: s.PhosphorusMg,
+	}
+}
+
+// groupEntriesByDate groups diary entries by their YYYY-MM-DD date
+func groupEntriesByDate(entries []FoodEntry) map[string][]FoodEntry {
+	byDate := make(map[string][]FoodEntry)
+	for _, e := range entries {
+		byDate[e.Date] = append(byDate[e.Date], e)
+	}
+	return byDate
+}
+
+// excludeEntryDates removes diary entries whose date is in the excluded list
+func excludeEntryDates(entries []FoodEntry, excluded []string) []FoodEntry {
+	skip := make(map[string]bool, len(excluded))
+	for _, date := range excluded {
+		skip[date] = true
+	}
+
+	var kept []FoodEntry
+	for _, e := range entries {
+		if !skip[e.Date] {
+			kept = append(kept, e)
+		}
+	}
+	return kept
+}
+
+// foodKey normalizes a food name so differently capitalized entries match
+func foodKey(name string) string {
+	return strings.ToLower(strings.TrimSpace(name))
+}
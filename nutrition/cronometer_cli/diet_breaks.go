@@ -0,0 +1,109 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+)
+
+// DietBreak is a run of maintenance-calorie days following a cut
+type DietBreak struct {
+	StartDate       string  `json:"start_date"`
+	EndDate         string  `json:"end_date"`
+	Days            int     `json:"days"`
+	AverageCalories float64 `json:"average_calories"`
+}
+
+// dietBreakOptions holds the -detect-diet-breaks settings
+type dietBreakOptions struct {
+	TDEE             float64
+	DeficitThreshold float64
+	BreakThreshold   float64
+	MinBreakDays     int
+}
+
+// parseDietBreaks parses a -detect-diet-breaks value such as
+// "tdee=2500,deficit=300,break=200,min-days=7". Only tdee is required.
+func parseDietBreaks(value string) (dietBreakOptions, error) {
+	pairs, err := parseKeyValues(value)
+	if err != nil {
+		return dietBreakOptions{}, err
+	}
+	options := dietBreakOptions{DeficitThreshold: 300, BreakThreshold: 200, MinBreakDays: 7}
+	for key, raw := range pairs {
+		switch key {
+		case "tdee", "deficit", "break":
+			v, err := strconv.ParseFloat(raw, 64)
+			if err != nil || v <= 0 {
+				return dietBreakOptions{}, fmt.Errorf("expected a positive number for %s, got %q", key, raw)
+			}
+			switch key {
+			case "tdee":
+				options.TDEE = v
+			case "deficit":
+				options.DeficitThreshold = v
+			case "break":
+				options.BreakThreshold = v
+			}
+		case "min-days":
+			days, err := strconv.Atoi(raw)
+			if err != nil || days < 1 {
+				return dietBreakOptions{}, fmt.Errorf("expected min-days=N with N >= 1, got %q", raw)
+			}
+			options.MinBreakDays = days
+		default:
+			return dietBreakOptions{}, fmt.Errorf("unknown setting %q", key)
+		}
+	}
+	if options.TDEE == 0 {
+		return dietBreakOptions{}, fmt.Errorf("tdee is required")
+	}
+	return options, nil
+}
+
+// DetectDietBreaks finds runs of at least minBreakDays logged days within
+// breakThreshold kcal of tdee that come after a cutting day, one at least
+// deficitThreshold kcal below tdee
+func DetectDietBreaks(records []DailyNutrition, tdee float64, deficitThreshold, breakThreshold float64, minBreakDays int) []DietBreak {
+	days := make([]DailyNutrition, len(records))
+	copy(days, records)
+	sort.Slice(days, func(i, j int) bool {
+		return days[i].Date < days[j].Date
+	})
+
+	var breaks []DietBreak
+	cutting := false
+	runStart := -1
+	closeRun := func(end int) {
+		if runStart >= 0 && end-runStart >= minBreakDays {
+			var total float64
+			for _, d := range days[runStart:end] {
+				total += d.Calories
+			}
+			breaks = append(breaks, DietBreak{
+				StartDate:       days[runStart].Date,
+				EndDate:         days[end-1].Date,
+				Days:            end - runStart,
+				AverageCalories: total / float64(end-runStart),
+			})
+		}
+		runStart = -1
+	}
+
+	for i, d := range days {
+		switch {
+		case math.Abs(d.Calories-tdee) <= breakThreshold:
+			if cutting && runStart < 0 {
+				runStart = i
+			}
+		case tdee-d.Calories >= deficitThreshold:
+			closeRun(i)
+			cutting = true
+		default:
+			closeRun(i)
+		}
+	}
+	closeRun(len(days))
+	return breaks
+}
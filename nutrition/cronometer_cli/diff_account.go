@@ -0,0 +1,127 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jrmycanady/gocronometer"
+)
+
+// accountCredentials holds the login details for a single Cronometer account
+type accountCredentials struct {
+	Username string
+	Password string
+}
+
+// AccountComparison joins one date's nutrition from two accounts
+type AccountComparison struct {
+	Date       string               `json:"date"`
+	Self       *DailyNutrition      `json:"self"`
+	Other      *DailyNutrition      `json:"other"`
+	Difference *NutritionDifference `json:"difference,omitempty"`
+}
+
+// NutritionDifference holds self minus other for each macro
+type NutritionDifference struct {
+	Calories float64 `json:"calories"`
+	Fat      float64 `json:"fat"`
+	Carbs    float64 `json:"carbs"`
+	Protein  float64 `json:"protein"`
+}
+
+// parseAccountCredentials parses a "username:password" flag value
+func parseAccountCredentials(value string) (accountCredentials, error) {
+	username, password, found := strings.Cut(value, ":")
+	if !found || username == "" || password == "" {
+		return accountCredentials{}, fmt.Errorf("expected username:password, got %q", value)
+	}
+	return accountCredentials{Username: username, Password: password}, nil
+}
+
+// loginAccounts logs in to every account concurrently and returns the clients
+// in the same order. Each client's requests are abandoned after timeout (0 = never).
+func loginAccounts(ctx context.Context, accounts []accountCredentials, timeout time.Duration) ([]*gocronometer.Client, error) {
+	clients := make([]*gocronometer.Client, len(accounts))
+	errs := make([]error, len(accounts))
+
+	var wg sync.WaitGroup
+	for i, account := range accounts {
+		wg.Add(1)
+		go func(i int, account accountCredentials) {
+			defer wg.Done()
+			client := gocronometer.NewClient(nil)
+			client.HTTPClient.Timeout = timeout
+			if err := client.Login(ctx, account.Username, account.Password); err != nil {
+				errs[i] = fmt.Errorf("%s: %v", account.Username, err)
+				return
+			}
+			clients[i] = client
+		}(i, account)
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+	}
+	return clients, nil
+}
+
+// compareAccounts joins two accounts' records by date, sorted by date.
+// Dates logged by only one account have a nil entry for the other side.
+func compareAccounts(self, other []DailyNutrition) []AccountComparison {
+	byDate := make(map[string]*AccountComparison)
+	for i := range self {
+		d := self[i]
+		byDate[d.Date] = &AccountComparison{Date: d.Date, Self: &d}
+	}
+	for i := range other {
+		d := other[i]
+		comparison, ok := byDate[d.Date]
+		if !ok {
+			comparison = &AccountComparison{Date: d.Date}
+			byDate[d.Date] = comparison
+		}
+		comparison.Other = &d
+	}
+
+	results := make([]AccountComparison, 0, len(byDate))
+	for _, comparison := range byDate {
+		if comparison.Self != nil && comparison.Other != nil {
+			comparison.Difference = &NutritionDifference{
+				Calories: comparison.Self.Calories - comparison.Other.Calories,
+				Fat:      comparison.Self.Fat - comparison.Other.Fat,
+				Carbs:    comparison.Self.Carbs - comparison.Other.Carbs,
+				Protein:  comparison.Self.Protein - comparison.Other.Protein,
+			}
+		}
+		results = append(results, *comparison)
+	}
+	sort.Slice(results, func(i, j int) bool {
+		return results[i].Date < results[j].Date
+	})
+	return results
+}
+
+// formatComparisonDates rewrites the comparison's YYYY-MM-DD dates using the output layout
+func formatComparisonDates(comparisons []AccountComparison, layout string) error {
+	for i := range comparisons {
+		formatted, err := formatDate(comparisons[i].Date, layout)
+		if err != nil {
+			return err
+		}
+		comparisons[i].Date = formatted
+		if comparisons[i].Self != nil {
+			comparisons[i].Self.Date = formatted
+		}
+		if comparisons[i].Other != nil {
+			comparisons[i].Other.Date = formatted
+		}
+	}
+	return nil
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"context"
+	"testing"
+)
+
+func TestCompareAccountsDatesInOneAccount(t *testing.T) {
+	self := []DailyNutrition{{Date: "2024-03-01", Calories: 2000}, {Date: "2024-03-02", Calories: 2100}}
+	other := []DailyNutrition{{Date: "2024-03-02", Calories: 1900}, {Date: "2024-03-03", Calories: 1800}}
+
+	got := compareAccounts(self, other)
+	if len(got) != 3 {
+		t.Fatalf("got %d comparisons, want 3", len(got))
+	}
+	tests := []struct {
+		date      string
+		hasSelf   bool
+		hasOther  bool
+		wantDelta float64
+	}{
+		{"2024-03-01", true, false, 0},
+		{"2024-03-02", true, true, 200},
+		{"2024-03-03", false, true, 0},
+	}
+	for i, tt := range tests {
+		c := got[i]
+		if c.Date != tt.date || (c.Self != nil) != tt.hasSelf || (c.Other != nil) != tt.hasOther {
+			t.Errorf("comparison %d = %s self=%v other=%v, want %s self=%v other=%v",
+				i, c.Date, c.Self != nil, c.Other != nil, tt.date, tt.hasSelf, tt.hasOther)
+			continue
+		}
+		inBoth := tt.hasSelf && tt.hasOther
+		switch {
+		case !inBoth && c.Difference != nil:
+			t.Errorf("%s: difference = %+v, want none for a date in one account", c.Date, c.Difference)
+		case inBoth && (c.Difference == nil || c.Difference.Calories != tt.wantDelta):
+			t.Errorf("%s: difference = %+v, want %g kcal", c.Date, c.Difference, tt.wantDelta)
+		}
+	}
+}
+
+func TestFetchOtherMatchesMainPreprocessing(t *testing.T) {
+	parser := mockParser{days: map[string][]DailyNutrition{
+		"partner:2024-03-01..2024-03-03": {
+			{Date: "2024-02-29", Calories: 2000},
+			{Date: "2024-03-01", Calories: 9000, Protein: 100, Fat: 80, Carbs: 220},
+			{Date: "2024-03-02", Calories: 2000},
+		},
+	}}
+	p := &Pipeline{
+		Config:      Config{StrictDates: true, Recompute: true},
+		Other:       &mockClient{account: "partner"},
+		Parser:      parser,
+		start:       date(t, "2024-03-01"),
+		end:         date(t, "2024-03-03"),
+		accounts:    []accountCredentials{{Username: "me"}, {Username: "partner"}},
+		excluded:    []string{"2024-03-02"},
+		clampBounds: map[string]float64{"protein": 50},
+	}
+
+	days, err := p.fetchOther(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	// -strict-dates drops 2024-02-29 and -exclude-dates drops 2024-03-02
+	if len(days) != 1 || days[0].Date != "2024-03-01" {
+		t.Fatalf("days = %+v, want only 2024-03-01", days)
+	}
+	// Protein is clamped to 50g before the recompute: 4*50 + 9*80 + 4*220
+	if got := days[0].Calories; got != 1800 {
+		t.Errorf("calories = %g, want 1800 recomputed from the clamped macros", got)
+	}
+}
@@ -0,0 +1,110 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+)
+
+// OutputDiff describes how the current run differs from a saved output file
+type OutputDiff struct {
+	AddedDates   []string                          `json:"added_dates"`
+	RemovedDates []string                          `json:"removed_dates"`
+	Changed      map[string]map[string]FieldChange `json:"changed"`
+}
+
+// FieldChange holds one field's old and new value. PercentChange is set for
+// numeric fields whose old value is non-zero.
+type FieldChange struct {
+	Old           interface{} `json:"old"`
+	New           interface{} `json:"new"`
+	PercentChange *float64    `json:"percent_change,omitempty"`
+}
+
+// loadOutputFile reads a saved JSON output, either the bare array of days or a
+// report object with "days", and flattens each day keyed by its date
+func loadOutputFile(path string) (map[string]map[string]interface{}, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var days []map[string]interface{}
+	if err := json.Unmarshal(data, &days); err != nil {
+		var report struct {
+			Days []map[string]interface{} `json:"days"`
+		}
+		if err := json.Unmarshal(data, &report); err != nil {
+			return nil, fmt.Errorf("expected a JSON array of days or a report with days: %v", err)
+		}
+		days = report.Days
+	}
+
+	byDate := make(map[string]map[string]interface{}, len(days))
+	for _, day := range days {
+		flat := make(map[string]interface{})
+		flattenInto(flat, "", day)
+		date, ok := flat["date"].(string)
+		if !ok {
+			return nil, fmt.Errorf("day without a date")
+		}
+		byDate[date] = flat
+	}
+	return byDate, nil
+}
+
+// DiffOutput compares the current days against a previous output, by date
+func DiffOutput(previous map[string]map[string]interface{}, current []DailyNutrition) OutputDiff {
+	diff := OutputDiff{
+		AddedDates:   []string{},
+		RemovedDates: []string{},
+		Changed:      make(map[string]map[string]FieldChange),
+	}
+
+	seen := make(map[string]bool, len(current))
+	for _, d := range current {
+		seen[d.Date] = true
+		old, ok := previous[d.Date]
+		if !ok {
+			diff.AddedDates = append(diff.AddedDates, d.Date)
+			continue
+		}
+		if changes := diffFields(old, FlattenNutrition(d)); len(changes) > 0 {
+			diff.Changed[d.Date] = changes
+		}
+	}
+	for date := range previous {
+		if !seen[date] {
+			diff.RemovedDates = append(diff.RemovedDates, date)
+		}
+	}
+	sort.Strings(diff.AddedDates)
+	sort.Strings(diff.RemovedDates)
+	return diff
+}
+
+// diffFields returns the fields whose values differ between two flattened days.
+// Both sides round-trip through JSON, so numbers are float64 on both.
+func diffFields(before, after map[string]interface{}) map[string]FieldChange {
+	changes := make(map[string]FieldChange)
+	for key, newValue := range after {
+		oldValue, ok := before[key]
+		if ok && oldValue == newValue {
+			continue
+		}
+		change := FieldChange{Old: oldValue, New: newValue}
+		oldNum, oldIsNum := oldValue.(float64)
+		newNum, newIsNum := newValue.(float64)
+		if oldIsNum && newIsNum && oldNum != 0 {
+			pct := (newNum - oldNum) / oldNum * 100
+			change.PercentChange = &pct
+		}
+		changes[key] = change
+	}
+	for key, oldValue := range before {
+		if _, ok := after[key]; !ok {
+			changes[key] = FieldChange{Old: oldValue}
+		}
+	}
+	return changes
+}
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"math"
+	"sort"
+)
+
+// diversityTrendThreshold is how much a week's average Shannon index must
+// change from the week before to count as a trend
+const diversityTrendThreshold = 0.1
+
+// ShannonDiversityIndex is H = -Σ p·ln(p), where each distinct food is a
+// species and p is its share of the entries' calories. One food gives 0 and
+// n foods contributing equally give the maximum, ln(n).
+func ShannonDiversityIndex(entries []FoodEntry) float64 {
+	calories := make(map[string]float64)
+	var total float64
+	for _, e := range entries {
+		if e.Calories > 0 {
+			calories[foodKey(e.FoodName)] += e.Calories
+			total += e.Calories
+		}
+	}
+	var h float64
+	for _, c := range calories {
+		p := c / total
+		h -= p * math.Log(p)
+	}
+	return h
+}
+
+// applyDiversityIndex sets each day's Shannon diversity index from its diary
+// and returns the weekly trend
+func applyDiversityIndex(records []DailyNutrition, diary []FoodEntry) []WeekScore {
+	byDate := groupEntriesByDate(diary)
+	order := make([]int, len(records))
+	for i := range records {
+		order[i] = i
+		h := ShannonDiversityIndex(byDate[records[i].Date])
+		records[i].DiversityIndex = &h
+	}
+	sort.Slice(order, func(a, b int) bool {
+		return records[order[a]].Date < records[order[b]].Date
+	})
+
+	dates := make([]string, len(order))
+	scores := make([]float64, len(order))
+	for i, idx := range order {
+		dates[i], scores[i] = records[idx].Date, *records[idx].DiversityIndex
+	}
+	return weeklyScoreTrend(dates, scores, diversityTrendThreshold)
+}
@@ -0,0 +1,41 @@
+package main
+
+import (
+	"math"
+	"testing"
+)
+
+func TestShannonDiversityIndex(t *testing.T) {
+	tests := []struct {
+		name    string
+		entries []FoodEntry
+		want    float64
+	}{
+		{"no entries", nil, 0},
+		{"single food", []FoodEntry{{FoodName: "Rice", Calories: 400}}, 0},
+		{"one food logged twice", []FoodEntry{{FoodName: "Rice", Calories: 200}, {FoodName: "rice ", Calories: 300}}, 0},
+		{"two foods equally", []FoodEntry{{FoodName: "Rice", Calories: 300}, {FoodName: "Beans", Calories: 300}}, math.Log(2)},
+		{"four foods equally", []FoodEntry{
+			{FoodName: "Rice", Calories: 250}, {FoodName: "Beans", Calories: 250},
+			{FoodName: "Kale", Calories: 250}, {FoodName: "Eggs", Calories: 250},
+		}, math.Log(4)},
+		{"uneven split", []FoodEntry{{FoodName: "Rice", Calories: 750}, {FoodName: "Beans", Calories: 250}},
+			-(0.75*math.Log(0.75) + 0.25*math.Log(0.25))},
+		{"zero-calorie entries ignored", []FoodEntry{{FoodName: "Rice", Calories: 500}, {FoodName: "Water", Calories: 0}}, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ShannonDiversityIndex(tt.entries); math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("ShannonDiversityIndex = %g, want %g", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestShannonDiversityIndexUniformIsMaximum(t *testing.T) {
+	uniform := []FoodEntry{{FoodName: "A", Calories: 100}, {FoodName: "B", Calories: 100}, {FoodName: "C", Calories: 100}}
+	skewed := []FoodEntry{{FoodName: "A", Calories: 200}, {FoodName: "B", Calories: 50}, {FoodName: "C", Calories: 50}}
+	if u, s := ShannonDiversityIndex(uniform), ShannonDiversityIndex(skewed); u <= s || math.Abs(u-math.Log(3)) > 1e-9 {
+		t.Errorf("uniform H = %g, skewed H = %g; want uniform to be the maximum ln(3)", u, s)
+	}
+}
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// bulkAction is the action line preceding each document in a _bulk payload
+type bulkAction struct {
+	Index struct {
+		Index string `json:"_index"`
+		ID    string `json:"_id"`
+	} `json:"index"`
+}
+
+// ExportElasticsearch returns an NDJSON payload for Elasticsearch's _bulk API
+// that indexes each record into indexName with its date as the _id. Each
+// document gets an @timestamp of midnight UTC on its date; records must still
+// have YYYY-MM-DD dates.
+func ExportElasticsearch(records []DailyNutrition, indexName string) (string, error) {
+	var b strings.Builder
+	for _, d := range records {
+		date, err := time.Parse("2006-01-02", d.Date)
+		if err != nil {
+			return "", fmt.Errorf("parsing date %q: %v", d.Date, err)
+		}
+
+		var action bulkAction
+		action.Index.Index = indexName
+		action.Index.ID = d.Date
+		line, err := json.Marshal(action)
+		if err != nil {
+			return "", err
+		}
+
+		data, err := json.Marshal(d)
+		if err != nil {
+			return "", err
+		}
+		var doc map[string]interface{}
+		if err := json.Unmarshal(data, &doc); err != nil {
+			return "", err
+		}
+		doc["@timestamp"] = date.UTC().Format(time.RFC3339)
+		source, err := json.Marshal(doc)
+		if err != nil {
+			return "", err
+		}
+
+		b.Write(line)
+		b.WriteByte('\n')
+		b.Write(source)
+		b.WriteByte('\n')
+	}
+	return b.String(), nil
+}
@@ -0,0 +1,75 @@
+package main
+
+import "math"
+
+// Adult electrolyte targets for men: the sodium AI and upper limit and the
+// potassium AI, in mg, plus the magnesium and calcium RDAs
+const (
+	sodiumAIMg          = 1500.0
+	sodiumLimitMg       = 2300.0
+	potassiumAIMg       = 3400.0
+	magnesiumRDAMg      = 420.0
+	rehydrationNaMgPerL = 460.0
+)
+
+// ElectrolyteReport rates a day's sodium, potassium, magnesium, and calcium.
+// The concentrations are per litre of water drunk and are only set with
+// -track-hydration.
+type ElectrolyteReport struct {
+	SodiumAdequate    bool     `json:"sodium_adequate"`
+	PotassiumAdequate bool     `json:"potassium_adequate"`
+	MagnesiumAdequate bool     `json:"magnesium_adequate"`
+	CalciumAdequate   bool     `json:"calcium_adequate"`
+	SodiumMgPerL      *float64 `json:"sodium_mg_per_l,omitempty"`
+	PotassiumMgPerL   *float64 `json:"potassium_mg_per_l,omitempty"`
+	// HydrationElectrolyteScore runs from 0 to 100
+	HydrationElectrolyteScore float64 `json:"hydration_electrolyte_score"`
+}
+
+// ElectrolyteBalance rates the day's electrolytes. Sodium is adequate from the
+// AI to the upper limit, and the others at or above their target. The score
+// averages each electrolyte's share of its target, capped at 1, with sodium
+// above the limit scaled down by how far over it is. When hydrationMl is
+// positive, sodium per litre of water against the 460 mg/L minimum for
+// rehydration fluids is averaged in too.
+func ElectrolyteBalance(d DailyNutrition, hydrationMl float64) ElectrolyteReport {
+	report := ElectrolyteReport{
+		SodiumAdequate:    d.Sodium >= sodiumAIMg && d.Sodium <= sodiumLimitMg,
+		PotassiumAdequate: d.Potassium >= potassiumAIMg,
+		MagnesiumAdequate: d.Magnesium >= magnesiumRDAMg,
+		CalciumAdequate:   d.Calcium >= highCalciumMg,
+	}
+
+	sodium := math.Min(d.Sodium/sodiumAIMg, 1)
+	if d.Sodium > sodiumLimitMg {
+		sodium = sodiumLimitMg / d.Sodium
+	}
+	shares := []float64{
+		sodium,
+		math.Min(d.Potassium/potassiumAIMg, 1),
+		math.Min(d.Magnesium/magnesiumRDAMg, 1),
+		math.Min(d.Calcium/highCalciumMg, 1),
+	}
+	if hydrationMl > 0 {
+		litres := hydrationMl / 1000
+		sodiumPerL, potassiumPerL := d.Sodium/litres, d.Potassium/litres
+		report.SodiumMgPerL, report.PotassiumMgPerL = &sodiumPerL, &potassiumPerL
+		shares = append(shares, math.Min(sodiumPerL/rehydrationNaMgPerL, 1))
+	}
+	report.HydrationElectrolyteScore = 100 * mean(shares)
+	return report
+}
+
+// applyElectrolytes attaches an electrolyte report to each day, using the
+// day's water from -track-hydration when it was totalled
+func applyElectrolytes(records []DailyNutrition) {
+	for i := range records {
+		d := &records[i]
+		var hydrationMl float64
+		if d.Hydration != nil {
+			hydrationMl = d.Hydration.AmountMl
+		}
+		report := ElectrolyteBalance(*d, hydrationMl)
+		d.Electrolytes = &report
+	}
+}
@@ -0,0 +1,216 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// defaultEatingWindowHours is the longest eating window of a 16:8 fasting day
+const defaultEatingWindowHours = 8.0
+
+// FastingDay is a day whose diary entries all fall within the eating window
+type FastingDay struct {
+	Date               string  `json:"date"`
+	EatingWindowHours  float64 `json:"eating_window_hours"`
+	FastingWindowHours float64 `json:"fasting_window_hours"`
+}
+
+// FastingReport lists the fasting days and the share of timed days that were fasting days
+type FastingReport struct {
+	Days        []FastingDay `json:"days"`
+	Consistency float64      `json:"consistency"`
+}
+
+// DailyEatingWindow is a day's first and last timed diary entries. Hours is
+// -1 and the times are omitted when nothing that day was logged with a time.
+type DailyEatingWindow struct {
+	FirstMeal *time.Time `json:"first_meal,omitempty"`
+	LastMeal  *time.Time `json:"last_meal,omitempty"`
+	Hours     float64    `json:"eating_window_hours"`
+}
+
+// EatingWindow returns the first and last diary entries logged on date and
+// the hours between them. windowHours is -1 when the day has entries but
+// none with a time of day; it is an error for the day to have no entries.
+func EatingWindow(entries []FoodEntry, date string) (firstMeal, lastMeal time.Time, windowHours float64, err error) {
+	found := false
+	for _, e := range entries {
+		if e.Date != date {
+			continue
+		}
+		found = true
+		if !hasClockTime(e.Time) {
+			continue
+		}
+		if firstMeal.IsZero() || e.Time.Before(firstMeal) {
+			firstMeal = e.Time
+		}
+		if e.Time.After(lastMeal) {
+			lastMeal = e.Time
+		}
+	}
+	if !found {
+		return time.Time{}, time.Time{}, 0, fmt.Errorf("no diary entries on %s", date)
+	}
+	if firstMeal.IsZero() {
+		return time.Time{}, time.Time{}, -1, nil
+	}
+	return firstMeal, lastMeal, lastMeal.Sub(firstMeal).Hours(), nil
+}
+
+// eatingWindows returns the hours between each day's first and last diary
+// entry. Days logged without times (every entry at midnight) are skipped.
+func eatingWindows(entries []FoodEntry) map[string]float64 {
+	windows := make(map[string]float64)
+	for date, dayEntries := range groupEntriesByDate(entries) {
+		if _, _, hours, err := EatingWindow(dayEntries, date); err == nil && hours >= 0 {
+			windows[date] = hours
+		}
+	}
+	return windows
+}
+
+// applyEatingWindows sets every day's eating window, leaving days without
+// diary entries unset
+func applyEatingWindows(records []DailyNutrition, diary []FoodEntry) {
+	byDate := groupEntriesByDate(diary)
+	for i := range records {
+		first, last, hours, err := EatingWindow(byDate[records[i].Date], records[i].Date)
+		if err != nil {
+			continue
+		}
+		window := &DailyEatingWindow{Hours: hours}
+		if hours >= 0 {
+			window.FirstMeal, window.LastMeal = &first, &last
+		}
+		records[i].EatingWindow = window
+	}
+}
+
+// DetectFastingDays returns the days whose entries span at most
+// eatingWindowHours, sorted by date
+func DetectFastingDays(entries []FoodEntry, eatingWindowHours float64) []FastingDay {
+	var days []FastingDay
+	for date, window := range eatingWindows(entries) {
+		if window <= eatingWindowHours {
+			days = append(days, FastingDay{
+				Date:               date,
+				EatingWindowHours:  window,
+				FastingWindowHours: 24 - window,
+			})
+		}
+	}
+	sort.Slice(days, func(i, j int) bool {
+		return days[i].Date < days[j].Date
+	})
+	return days
+}
+
+// FastingConsistency is the fraction of days logged with times that were fasting days
+func FastingConsistency(entries []FoodEntry, eatingWindowHours float64) float64 {
+	timed := len(eatingWindows(entries))
+	if timed == 0 {
+		return 0
+	}
+	return float64(len(DetectFastingDays(entries, eatingWindowHours))) / float64(timed)
+}
+
+// fastDayMaxCalories is the calorie limit of a fast day in 5:2 and
+// alternate-day protocols
+const fastDayMaxCalories = 500.0
+
+// FastingProtocol is a parsed -fasting-protocol. A spec "A:B" whose parts sum
+// to 24 is time-restricted eating: A fasting hours and a B hour eating window.
+// Any other "A:B" is a cycle of A normal days and B fast days, aligned to
+// calendar weeks when the cycle is 7 days long. "alternate-day" is 1:1.
+type FastingProtocol struct {
+	Spec              string
+	EatingWindowHours float64
+	CycleDays         int
+	FastDays          int
+}
+
+// ProtocolReport is adherence to a fasting protocol. Periods are timed diary
+// days for time-restricted eating, or whole cycles inside the date range.
+type ProtocolReport struct {
+	Protocol             string  `json:"protocol"`
+	PeriodsChecked       int     `json:"periods_checked"`
+	PeriodsAdherent      int     `json:"periods_adherent"`
+	ProtocolAdherencePct float64 `json:"protocol_adherence_pct"`
+}
+
+// parseFastingProtocol parses a -fasting-protocol value such as "16:8" or "5:2"
+func parseFastingProtocol(value string) (FastingProtocol, error) {
+	spec := value
+	if strings.EqualFold(value, "alternate-day") {
+		spec = "1:1"
+	}
+	left, right, found := strings.Cut(spec, ":")
+	a, errA := strconv.Atoi(strings.TrimSpace(left))
+	b, errB := strconv.Atoi(strings.TrimSpace(right))
+	if !found || errA != nil || errB != nil || a < 1 || b < 1 {
+		return FastingProtocol{}, fmt.Errorf("expected A:B such as 16:8 or 5:2, or alternate-day, got %q", value)
+	}
+
+	protocol := FastingProtocol{Spec: value}
+	if a+b == 24 {
+		protocol.EatingWindowHours = float64(b)
+	} else {
+		protocol.CycleDays, protocol.FastDays = a+b, b
+	}
+	return protocol, nil
+}
+
+// timeRestricted reports whether the protocol limits the daily eating window
+func (f FastingProtocol) timeRestricted() bool {
+	return f.EatingWindowHours > 0
+}
+
+// ProtocolAdherence checks the protocol over the date range. Time-restricted
+// protocols check each timed diary day's eating window; cycle protocols check
+// that each cycle has at least FastDays logged days under 500 kcal.
+func ProtocolAdherence(protocol FastingProtocol, records []DailyNutrition, diary []FoodEntry, start, end time.Time) ProtocolReport {
+	report := ProtocolReport{Protocol: protocol.Spec}
+	if protocol.timeRestricted() {
+		for _, window := range eatingWindows(diary) {
+			report.PeriodsChecked++
+			if window <= protocol.EatingWindowHours {
+				report.PeriodsAdherent++
+			}
+		}
+	} else {
+		fastDays := make(map[string]bool)
+		for _, d := range records {
+			if d.Calories < fastDayMaxCalories {
+				fastDays[d.Date] = true
+			}
+		}
+
+		cycleStart := start
+		if protocol.CycleDays == 7 {
+			for cycleStart.Weekday() != time.Monday {
+				cycleStart = cycleStart.AddDate(0, 0, 1)
+			}
+		}
+		for !cycleStart.AddDate(0, 0, protocol.CycleDays-1).After(end) {
+			fasts := 0
+			for offset := 0; offset < protocol.CycleDays; offset++ {
+				if fastDays[cycleStart.AddDate(0, 0, offset).Format("2006-01-02")] {
+					fasts++
+				}
+			}
+			report.PeriodsChecked++
+			if fasts >= protocol.FastDays {
+				report.PeriodsAdherent++
+			}
+			cycleStart = cycleStart.AddDate(0, 0, protocol.CycleDays)
+		}
+	}
+	if report.PeriodsChecked > 0 {
+		report.ProtocolAdherencePct = float64(report.PeriodsAdherent) / float64(report.PeriodsChecked) * 100
+	}
+	return report
+}
@@ -0,0 +1,46 @@
+package main
+
+// ahaEPADHAMg is the AHA-recommended daily EPA+DHA intake in milligrams
+const ahaEPADHAMg = 500.0
+
+// Omega3Report summarizes a day's omega-3 intake against AHA guidance
+type Omega3Report struct {
+	TotalOmega3 float64 `json:"total_omega_3"`
+	EPADHAMg    float64 `json:"epa_dha_mg"`
+	OmegaRatio  float64 `json:"omega_ratio"`
+	Advisory    string  `json:"advisory"`
+}
+
+// totalOmega3 sums ALA, EPA, and DHA, falling back to Cronometer's total
+// omega-3 column when the individual fatty acids were not exported
+func totalOmega3(d DailyNutrition) float64 {
+	if total := d.ALA + d.EPA + d.DHA; total > 0 {
+		return total
+	}
+	return d.Omega3
+}
+
+// checkOmega3 builds the omega-3 report for a day. The advisory is
+// "adequate" when EPA+DHA exceeds 500mg and "low" otherwise.
+func checkOmega3(d DailyNutrition) Omega3Report {
+	report := Omega3Report{
+		TotalOmega3: totalOmega3(d),
+		EPADHAMg:    (d.EPA + d.DHA) * 1000,
+		Advisory:    "low",
+	}
+	if report.TotalOmega3 > 0 {
+		report.OmegaRatio = d.Omega6 / report.TotalOmega3
+	}
+	if report.EPADHAMg > ahaEPADHAMg {
+		report.Advisory = "adequate"
+	}
+	return report
+}
+
+// applyOmega3 adds the omega-3 report to each record
+func applyOmega3(records []DailyNutrition) {
+	for i := range records {
+		report := checkOmega3(records[i])
+		records[i].Omega3Report = &report
+	}
+}
@@ -0,0 +1,145 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// chunkWorkers is how many chunk requests run at the same time
+const chunkWorkers = 3
+
+// retryBackoff is the delay added per previous attempt before a job is retried
+const retryBackoff = time.Second
+
+// FetchJob is one date-range chunk of an export
+type FetchJob struct {
+	Index    int
+	Start    time.Time
+	End      time.Time
+	Attempts int
+}
+
+// fetchFunc exports the raw CSV for an inclusive date range
+type fetchFunc func(ctx context.Context, start, end time.Time) (string, error)
+
+// splitDateRange splits [start, end] into consecutive chunks of chunkDays days
+func splitDateRange(start, end time.Time, chunkDays int) []FetchJob {
+	var jobs []FetchJob
+	for chunkStart := start; !chunkStart.After(end); chunkStart = chunkStart.AddDate(0, 0, chunkDays) {
+		chunkEnd := chunkStart.AddDate(0, 0, chunkDays-1)
+		if chunkEnd.After(end) {
+			chunkEnd = end
+		}
+		jobs = append(jobs, FetchJob{Index: len(jobs), Start: chunkStart, End: chunkEnd})
+	}
+	return jobs
+}
+
+// fetchChunked runs every job through fetch with a few concurrent workers.
+// Jobs are dequeued by chunk start date, and failed jobs go back on the queue
+// with their original priority, so earlier date ranges finish first. Results
+// are returned in chunk order.
+func fetchChunked(ctx context.Context, fetch fetchFunc, jobs []FetchJob, maxRetries int) ([]string, error) {
+	queue := NewPriorityQueue(func(a, b FetchJob) bool {
+		return a.Start.Before(b.Start)
+	})
+	for _, job := range jobs {
+		queue.Enqueue(job)
+	}
+
+	type outcome struct {
+		job  FetchJob
+		data string
+		err  error
+	}
+	// Buffered so in-flight workers never block if we return early
+	outcomes := make(chan outcome, len(jobs))
+
+	results := make([]string, len(jobs))
+	inFlight := 0
+	for queue.Len() > 0 || inFlight > 0 {
+		for inFlight < chunkWorkers && queue.Len() > 0 {
+			job := queue.Dequeue()
+			inFlight++
+			go func(job FetchJob) {
+				if job.Attempts > 0 {
+					select {
+					case <-time.After(time.Duration(job.Attempts) * retryBackoff):
+					case <-ctx.Done():
+						outcomes <- outcome{job: job, err: ctx.Err()}
+						return
+					}
+				}
+				data, err := fetch(ctx, job.Start, job.End)
+				outcomes <- outcome{job: job, data: data, err: err}
+			}(job)
+		}
+
+		o := <-outcomes
+		inFlight--
+		if o.err != nil {
+			o.job.Attempts++
+			if o.job.Attempts > maxRetries {
+				return nil, fmt.Errorf("chunk %s to %s failed after %d attempts: %v",
+					o.job.Start.Format("2006-01-02"), o.job.End.Format("2006-01-02"), o.job.Attempts, o.err)
+			}
+			queue.Enqueue(o.job)
+			continue
+		}
+		results[o.job.Index] = o.data
+	}
+	return results, nil
+}
+
+// typeFetch holds the exports fetched concurrently by the "type" chunk strategy
+type typeFetch struct {
+	Nutrition  string
+	Diary      []FoodEntry
+	Biometrics []BiometricEntry
+}
+
+// fetchByType exports the daily nutrition, and the diary and biometrics when
+// requested, all at the same time. The nutrition export is retried like a
+// single date chunk.
+func fetchByType(ctx context.Context, client Client, start, end time.Time, withDiary, withBiometrics bool, maxRetries int) (typeFetch, error) {
+	var fetched typeFetch
+	var nutritionErr, diaryErr, biometricsErr error
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		var chunks []string
+		chunks, nutritionErr = fetchChunked(ctx, client.ExportDailyNutrition, []FetchJob{{Start: start, End: end}}, maxRetries)
+		if nutritionErr == nil {
+			fetched.Nutrition = chunks[0]
+		}
+	}()
+	if withDiary {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			fetched.Diary, diaryErr = fetchDiary(ctx, client, start, end)
+		}()
+	}
+	if withBiometrics {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			fetched.Biometrics, biometricsErr = fetchBiometrics(ctx, client, start, end)
+		}()
+	}
+	wg.Wait()
+
+	switch {
+	case nutritionErr != nil:
+		return typeFetch{}, fmt.Errorf("exporting nutrition data: %v", describeTimeout(nutritionErr))
+	case diaryErr != nil:
+		return typeFetch{}, fmt.Errorf("exporting food diary: %v", describeTimeout(diaryErr))
+	case biometricsErr != nil:
+		return typeFetch{}, fmt.Errorf("exporting biometrics: %v", describeTimeout(biometricsErr))
+	}
+	return fetched, nil
+}
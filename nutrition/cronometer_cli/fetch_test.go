@@ -0,0 +1,129 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+func date(t *testing.T, value string) time.Time {
+	t.Helper()
+	d, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return d
+}
+
+func TestSplitDateRange(t *testing.T) {
+	tests := []struct {
+		name      string
+		start     string
+		end       string
+		chunkDays int
+		want      [][2]string
+	}{
+		{"single day", "2024-03-01", "2024-03-01", 7, [][2]string{{"2024-03-01", "2024-03-01"}}},
+		{"exact chunks", "2024-03-01", "2024-03-14", 7, [][2]string{{"2024-03-01", "2024-03-07"}, {"2024-03-08", "2024-03-14"}}},
+		{"short last chunk", "2024-03-01", "2024-03-10", 7, [][2]string{{"2024-03-01", "2024-03-07"}, {"2024-03-08", "2024-03-10"}}},
+		{"start after end", "2024-03-10", "2024-03-01", 7, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			jobs := splitDateRange(date(t, tt.start), date(t, tt.end), tt.chunkDays)
+			if len(jobs) != len(tt.want) {
+				t.Fatalf("got %d jobs, want %d", len(jobs), len(tt.want))
+			}
+			for i, job := range jobs {
+				got := [2]string{job.Start.Format("2006-01-02"), job.End.Format("2006-01-02")}
+				if got != tt.want[i] || job.Index != i {
+					t.Errorf("job %d = %v (index %d), want %v", i, got, job.Index, tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestPriorityQueueRequeuedJobKeepsPriority(t *testing.T) {
+	jobs := splitDateRange(date(t, "2024-03-01"), date(t, "2024-03-28"), 7)
+	queue := NewPriorityQueue(func(a, b FetchJob) bool {
+		return a.Start.Before(b.Start)
+	})
+	for i := len(jobs) - 1; i >= 0; i-- {
+		queue.Enqueue(jobs[i])
+	}
+
+	// The first chunk fails and is requeued ahead of the later chunks
+	failed := queue.Dequeue()
+	failed.Attempts++
+	queue.Enqueue(failed)
+
+	var order []int
+	for queue.Len() > 0 {
+		order = append(order, queue.Dequeue().Index)
+	}
+	want := []int{0, 1, 2, 3}
+	for i := range want {
+		if order[i] != want[i] {
+			t.Fatalf("dequeue order = %v, want %v", order, want)
+		}
+	}
+}
+
+func TestFetchChunkedRetriesFailedChunks(t *testing.T) {
+	jobs := splitDateRange(date(t, "2024-03-01"), date(t, "2024-03-28"), 7)
+
+	var mu sync.Mutex
+	calls := make(map[string]int)
+	fetch := func(ctx context.Context, start, end time.Time) (string, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		key := start.Format("2006-01-02")
+		calls[key]++
+		// The first and third chunks fail once before succeeding
+		if (key == "2024-03-01" || key == "2024-03-15") && calls[key] == 1 {
+			return "", errors.New("timeout")
+		}
+		return key, nil
+	}
+
+	results, err := fetchChunked(context.Background(), fetch, jobs, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"2024-03-01", "2024-03-08", "2024-03-15", "2024-03-22"}
+	if len(results) != len(want) {
+		t.Fatalf("got %d results, want %d", len(results), len(want))
+	}
+	for i := range want {
+		if results[i] != want[i] {
+			t.Errorf("results[%d] = %q, want %q", i, results[i], want[i])
+		}
+	}
+	if calls["2024-03-01"] != 2 || calls["2024-03-08"] != 1 {
+		t.Errorf("calls = %v, want the failed chunks retried once", calls)
+	}
+}
+
+func TestFetchChunkedGivesUpAfterMaxRetries(t *testing.T) {
+	jobs := splitDateRange(date(t, "2024-03-01"), date(t, "2024-03-07"), 7)
+	fetch := func(ctx context.Context, start, end time.Time) (string, error) {
+		return "", errors.New("timeout")
+	}
+	if _, err := fetchChunked(context.Background(), fetch, jobs, 0); err == nil {
+		t.Fatal("expected an error once retries are exhausted")
+	}
+}
+
+func TestFetchChunkedNoJobs(t *testing.T) {
+	fetch := func(ctx context.Context, start, end time.Time) (string, error) {
+		t.Fatal("fetch called with no jobs")
+		return "", nil
+	}
+	results, err := fetchChunked(context.Background(), fetch, nil, 1)
+	if err != nil || len(results) != 0 {
+		t.Fatalf("got %v, %v; want no results and no error", results, err)
+	}
+}
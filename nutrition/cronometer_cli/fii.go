@@ -0,0 +1,39 @@
+package main
+
+import "math"
+
+// proteinInsulinFactor is the share of protein grams counted as insulinogenic,
+// from fitting the Food Insulin Index table (Bell et al. 2014)
+const proteinInsulinFactor = 0.56
+
+// fiiTolerance is how many index points FIIEstimate may differ from the
+// published FII of starchy, high-protein, and high-fat foods
+const fiiTolerance = 15.0
+
+// FIIEstimate approximates the Food Insulin Index, on a scale where pure
+// glucose is 100, as the share of calories from insulinogenic grams: net
+// carbs plus 56% of protein. Fat is counted only in the calories, so refined
+// carbs and lean protein score highest. Against the published index (Bao et
+// al. 2009) it lands within fiiTolerance points for starches, eggs, nuts, and
+// meat; fatty meat scores low and fruit high, since fructose raises insulin
+// less than starch. It returns 0 when there are no calories.
+func FIIEstimate(protein, carbs, fat, fiber float64) float64 {
+	calories := kcalPerGramCarbs*carbs + kcalPerGramProtein*protein + kcalPerGramFat*fat
+	if calories <= 0 {
+		return 0
+	}
+	insulinogenic := math.Max(carbs-fiber, 0) + proteinInsulinFactor*protein
+	return kcalPerGramCarbs * insulinogenic / calories * 100
+}
+
+// applyFII sets each day's estimated Food Insulin Index, skipping days without calories
+func applyFII(records []DailyNutrition) {
+	for i := range records {
+		d := &records[i]
+		fii := FIIEstimate(d.Protein, d.Carbs, d.Fat, d.Fiber)
+		if fii == 0 {
+			continue
+		}
+		d.FII = &fii
+	}
+}
@@ -0,0 +1,37 @@
+package main
+
+import (
+	"math"
+	"testing"
+)
+
+func TestFIIEstimateAgainstPublishedValues(t *testing.T) {
+	// Macros per 100g from USDA FoodData Central; published FII on the
+	// glucose = 100 scale from Bao et al. 2009
+	tests := []struct {
+		food                       string
+		protein, carbs, fat, fiber float64
+		published                  float64
+	}{
+		{"glucose", 0, 100, 0, 0, 100},
+		{"white bread", 9, 49, 3.2, 2.7, 73},
+		{"baked potato", 2.5, 21, 0.1, 2.2, 88},
+		{"whole egg", 12.6, 1.1, 9.5, 0, 23},
+		{"beef steak", 26, 0, 15, 0, 37},
+		{"peanuts", 25.8, 16.1, 49.2, 8.5, 15},
+	}
+	for _, tt := range tests {
+		t.Run(tt.food, func(t *testing.T) {
+			got := FIIEstimate(tt.protein, tt.carbs, tt.fat, tt.fiber)
+			if math.Abs(got-tt.published) > fiiTolerance {
+				t.Errorf("FIIEstimate = %.1f, published %g, more than %g points apart", got, tt.published, fiiTolerance)
+			}
+		})
+	}
+}
+
+func TestFIIEstimateNoCalories(t *testing.T) {
+	if got := FIIEstimate(0, 0, 0, 5); got != 0 {
+		t.Errorf("FIIEstimate with no calories = %g, want 0", got)
+	}
+}
@@ -0,0 +1,112 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// parseDateList parses a comma-separated list of YYYY-MM-DD dates
+func parseDateList(value string) ([]string, error) {
+	var dates []string
+	for _, part := range strings.Split(value, ",") {
+		date := strings.TrimSpace(part)
+		if date == "" {
+			continue
+		}
+		if _, err := time.Parse("2006-01-02", date); err != nil {
+			return nil, fmt.Errorf("invalid date %q: expected YYYY-MM-DD", date)
+		}
+		dates = append(dates, date)
+	}
+	return dates, nil
+}
+
+// filterDateRange removes records dated outside [start, end] and returns how many were removed
+func filterDateRange(records []DailyNutrition, start, end time.Time) ([]DailyNutrition, int) {
+	first, last := start.Format("2006-01-02"), end.Format("2006-01-02")
+	kept := make([]DailyNutrition, 0, len(records))
+	for _, d := range records {
+		if d.Date >= first && d.Date <= last {
+			kept = append(kept, d)
+		}
+	}
+	return kept, len(records) - len(kept)
+}
+
+// excludeDates removes records whose date is in the excluded list
+func excludeDates(records []DailyNutrition, excluded []string) []DailyNutrition {
+	skip := make(map[string]bool, len(excluded))
+	for _, date := range excluded {
+		skip[date] = true
+	}
+
+	kept := make([]DailyNutrition, 0, len(records))
+	for _, d := range records {
+		if !skip[d.Date] {
+			kept = append(kept, d)
+		}
+	}
+	return kept
+}
+
+// saturateIncompleteDays scales up every macro and optional nutrient on days
+// logged below expectedMinCalories by the same factor so the day's calories
+// reach that minimum, marking the record as extrapolated
+func saturateIncompleteDays(records []DailyNutrition, expectedMinCalories float64) {
+	for i := range records {
+		d := &records[i]
+		if d.Calories <= 0 || d.Calories >= expectedMinCalories {
+			continue
+		}
+		factor := expectedMinCalories / d.Calories
+		d.Calories *= factor
+		d.Fat *= factor
+		d.Carbs *= factor
+		d.Protein *= factor
+		for _, n := range optionalNutrients {
+			*n.field(d) *= factor
+		}
+		d.Extrapolated = true
+	}
+}
+
+// parseClamp parses the -clamp value (e.g. "calories=5000,protein=500") into upper bounds
+func parseClamp(value string) (map[string]float64, error) {
+	pairs, err := parseKeyValues(value)
+	if err != nil {
+		return nil, err
+	}
+	bounds := make(map[string]float64, len(pairs))
+	var probe DailyNutrition
+	for name, raw := range pairs {
+		if nutrientField(&probe, name) == nil {
+			return nil, fmt.Errorf("unknown nutrient %q", name)
+		}
+		bound, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid bound for %s: %q", name, raw)
+		}
+		bounds[name] = bound
+	}
+	return bounds, nil
+}
+
+// clampRecords caps nutrients at their upper bounds, recording each replaced
+// value in the record's OriginalValues
+func clampRecords(records []DailyNutrition, bounds map[string]float64) {
+	for i := range records {
+		for name, bound := range bounds {
+			field := nutrientField(&records[i], name)
+			if *field <= bound {
+				continue
+			}
+			if records[i].OriginalValues == nil {
+				records[i].OriginalValues = make(map[string]float64)
+			}
+			records[i].OriginalValues[name] = *field
+			*field = bound
+		}
+	}
+}
@@ -0,0 +1,150 @@
+package main
+
+import (
+	"context"
+	"testing"
+)
+
+func TestExcludedDatesLeaveEveryAnalysis(t *testing.T) {
+	p := &Pipeline{
+		Config:   Config{SummaryOnly: true, AdherenceStreak: true, GoalTimeline: true, TargetAdherence: 0.9},
+		goals:    map[string]float64{"calories": 2500},
+		excluded: []string{"2024-03-01"},
+		start:    date(t, "2024-03-01"),
+		end:      date(t, "2024-03-04"),
+	}
+	// The excluded day is a 6000 kcal holiday that misses the calorie goal
+	result := Result{Days: []DailyNutrition{
+		{Date: "2024-03-01", Calories: 6000},
+		{Date: "2024-03-02", Calories: 2000},
+		{Date: "2024-03-03", Calories: 2100},
+		{Date: "2024-03-04", Calories: 1900},
+	}}
+
+	if err := p.analyze(context.Background(), &result, nil, nil); err != nil {
+		t.Fatal(err)
+	}
+	summary := result.Summary
+	if summary.DaysLogged != 3 || summary.Averages["calories"] != 2000 {
+		t.Errorf("days logged %d averaging %g kcal, want 3 days averaging 2000", summary.DaysLogged, summary.Averages["calories"])
+	}
+	if summary.AdherenceStreak == nil || summary.AdherenceStreak.Current != 3 || summary.AdherenceStreak.Longest != 3 {
+		t.Errorf("streak = %+v, want current and longest 3", summary.AdherenceStreak)
+	}
+	if timeline := summary.GoalTimeline["calories"]; timeline.Adherence != 1 {
+		t.Errorf("calorie adherence = %g, want 1 with the holiday excluded", timeline.Adherence)
+	}
+	for _, d := range result.Days {
+		if d.Date == "2024-03-01" {
+			t.Errorf("excluded date still in the days")
+		}
+	}
+}
+
+func TestExcludeDates(t *testing.T) {
+	records := []DailyNutrition{{Date: "2024-03-01"}, {Date: "2024-03-02"}, {Date: "2024-03-03"}}
+	tests := []struct {
+		name     string
+		excluded []string
+		want     []string
+	}{
+		{"none", nil, []string{"2024-03-01", "2024-03-02", "2024-03-03"}},
+		{"middle", []string{"2024-03-02"}, []string{"2024-03-01", "2024-03-03"}},
+		{"not in range", []string{"2024-04-01"}, []string{"2024-03-01", "2024-03-02", "2024-03-03"}},
+		{"all", []string{"2024-03-01", "2024-03-02", "2024-03-03"}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := excludeDates(records, tt.excluded)
+			if len(got) != len(tt.want) {
+				t.Fatalf("excludeDates = %+v, want %v", got, tt.want)
+			}
+			for i := range tt.want {
+				if got[i].Date != tt.want[i] {
+					t.Errorf("day %d = %s, want %s", i, got[i].Date, tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestClampAppliesBeforeAnalysis(t *testing.T) {
+	p := &Pipeline{
+		Config:      Config{Rolling: 2, AdherenceStreak: true},
+		goals:       map[string]float64{"calories": 3000},
+		clampBounds: map[string]float64{"calories": 3000},
+		start:       date(t, "2024-03-01"),
+		end:         date(t, "2024-03-02"),
+	}
+	// A mistyped 30000 kcal entry on the second day
+	result := Result{Days: []DailyNutrition{
+		{Date: "2024-03-01", Calories: 2000},
+		{Date: "2024-03-02", Calories: 30000},
+	}}
+
+	if err := p.analyze(context.Background(), &result, nil, nil); err != nil {
+		t.Fatal(err)
+	}
+	d := result.Days[1]
+	if d.Calories != 3000 || d.OriginalValues["calories"] != 30000 {
+		t.Fatalf("calories = %g (original %g), want 3000 clamped from 30000", d.Calories, d.OriginalValues["calories"])
+	}
+	if d.Rolling == nil || d.Rolling.Calories.Mean != 2500 {
+		t.Errorf("rolling = %+v, want a 2500 kcal mean of the clamped values", d.Rolling)
+	}
+	if !d.Goals["calories"].Met || d.Goals["calories"].Actual != 3000 {
+		t.Errorf("calorie goal = %+v, want met at the clamped 3000", d.Goals["calories"])
+	}
+	if streak := result.Summary.AdherenceStreak; streak == nil || streak.Longest != 2 {
+		t.Errorf("streak = %+v, want both days counted", streak)
+	}
+}
+
+func TestClampRecords(t *testing.T) {
+	records := []DailyNutrition{{Protein: 120, Fat: 700}, {Protein: 600, Fat: 50}}
+	clampRecords(records, map[string]float64{"protein": 500, "fat": 500})
+
+	// An original of 0 means the value was under its bound and kept
+	tests := []struct {
+		record   int
+		field    string
+		want     float64
+		original float64
+	}{
+		{0, "protein", 120, 0},
+		{0, "fat", 500, 700},
+		{1, "protein", 500, 600},
+		{1, "fat", 50, 0},
+	}
+	for _, tt := range tests {
+		d := &records[tt.record]
+		if got := *nutrientField(d, tt.field); got != tt.want {
+			t.Errorf("record %d %s = %g, want %g", tt.record, tt.field, got, tt.want)
+		}
+		if got := d.OriginalValues[tt.field]; got != tt.original {
+			t.Errorf("record %d original %s = %g, want %g", tt.record, tt.field, got, tt.original)
+		}
+	}
+}
+
+func TestSaturateIncompleteDaysScalesEveryNutrient(t *testing.T) {
+	records := []DailyNutrition{
+		{Date: "2024-03-01", Calories: 1000, Fat: 30, Carbs: 120, Protein: 60, Fiber: 12, Sodium: 900, VitaminC: 40, Biotin: 10},
+		{Date: "2024-03-02", Calories: 2200, Fat: 80, Carbs: 250, Protein: 120, Fiber: 30, Sodium: 2000},
+	}
+	saturateIncompleteDays(records, 1500)
+
+	partial := records[0]
+	want := DailyNutrition{Date: "2024-03-01", Calories: 1500, Fat: 45, Carbs: 180, Protein: 90, Fiber: 18, Sodium: 1350, VitaminC: 60, Biotin: 15, Extrapolated: true}
+	if partial.Calories != want.Calories || partial.Fat != want.Fat || partial.Carbs != want.Carbs || partial.Protein != want.Protein || !partial.Extrapolated {
+		t.Errorf("macros = %+v, want %+v", partial, want)
+	}
+	for _, n := range optionalNutrients {
+		if got, w := *n.field(&partial), *n.field(&want); got != w {
+			t.Errorf("%s = %g, want %g", n.name, got, w)
+		}
+	}
+	if full := records[1]; full.Extrapolated || full.Fiber != 30 || full.Sodium != 2000 {
+		t.Errorf("complete day changed to %+v", full)
+	}
+}
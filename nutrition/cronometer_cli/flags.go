@@ -0,0 +1,25 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// parseKeyValues parses a comma-separated list of key=value pairs
+func parseKeyValues(value string) (map[string]string, error) {
+	pairs := make(map[string]string)
+	for _, part := range strings.Split(value, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		key, val, found := strings.Cut(part, "=")
+		key = strings.TrimSpace(key)
+		val = strings.TrimSpace(val)
+		if !found || key == "" || val == "" {
+			return nil, fmt.Errorf("expected key=value, got %q", part)
+		}
+		pairs[key] = val
+	}
+	return pairs, nil
+}
@@ -0,0 +1,53 @@
+package main
+
+// Folate guidance: the adult RDA is 400µg DFE, folic acid is absorbed 1.7
+// times as well as food folate, and the upper limit of 1000µg applies to
+// folic acid only
+const (
+	folateRDAUgDFE        = 400.0
+	folicAcidDFEFactor    = 1.7
+	folicAcidUpperLimitUg = 1000.0
+)
+
+// FolateReport is a day's dietary folate equivalents against the RDA
+type FolateReport struct {
+	NaturalUg   float64 `json:"natural_ug"`
+	FolicAcidUg float64 `json:"folic_acid_ug"`
+	DFEUg       float64 `json:"dfe_ug"`
+	RDAUg       float64 `json:"rda_ug"`
+	// Status is "adequate", "inadequate", or "above_upper_limit" when folic
+	// acid exceeds 1000µg
+	Status string `json:"status"`
+}
+
+// DietaryFolateEquivalents returns natural folate plus 1.7 times folic acid
+func DietaryFolateEquivalents(naturalUg, folicAcidUg float64) float64 {
+	return naturalUg + folicAcidDFEFactor*folicAcidUg
+}
+
+// checkFolate rates a day's folate, treating the export's folate column as
+// natural food folate
+func checkFolate(d DailyNutrition) FolateReport {
+	report := FolateReport{
+		NaturalUg:   d.Folate,
+		FolicAcidUg: d.FolicAcid,
+		DFEUg:       DietaryFolateEquivalents(d.Folate, d.FolicAcid),
+		RDAUg:       folateRDAUgDFE,
+		Status:      "inadequate",
+	}
+	switch {
+	case d.FolicAcid > folicAcidUpperLimitUg:
+		report.Status = "above_upper_limit"
+	case report.DFEUg >= folateRDAUgDFE:
+		report.Status = "adequate"
+	}
+	return report
+}
+
+// applyFolate adds a folate report to each record
+func applyFolate(records []DailyNutrition) {
+	for i := range records {
+		report := checkFolate(records[i])
+		records[i].FolateReport = &report
+	}
+}
@@ -0,0 +1,50 @@
+package main
+
+import "sort"
+
+// FoodAggregate totals a food's diary entries over the date range
+type FoodAggregate struct {
+	FoodName      string  `json:"food_name"`
+	TotalCalories float64 `json:"total_calories"`
+	TotalProtein  float64 `json:"total_protein"`
+	TotalFat      float64 `json:"total_fat"`
+	TotalCarbs    float64 `json:"total_carbs"`
+	TotalServings int     `json:"total_servings"`
+	DaysLogged    int     `json:"days_logged"`
+}
+
+// AggregateByFood sums macros per food, treating names that differ only in
+// case or surrounding whitespace as the same food. Each diary entry counts as
+// one serving. Results are sorted by total calories, highest first.
+func AggregateByFood(entries []FoodEntry) []FoodAggregate {
+	byFood := make(map[string]*FoodAggregate)
+	days := make(map[string]map[string]bool)
+	for _, e := range entries {
+		key := foodKey(e.FoodName)
+		aggregate, ok := byFood[key]
+		if !ok {
+			aggregate = &FoodAggregate{FoodName: e.FoodName}
+			byFood[key] = aggregate
+			days[key] = make(map[string]bool)
+		}
+		aggregate.TotalCalories += e.Calories
+		aggregate.TotalProtein += e.Protein
+		aggregate.TotalFat += e.Fat
+		aggregate.TotalCarbs += e.Carbs
+		aggregate.TotalServings++
+		days[key][e.Date] = true
+	}
+
+	aggregates := make([]FoodAggregate, 0, len(byFood))
+	for key, aggregate := range byFood {
+		aggregate.DaysLogged = len(days[key])
+		aggregates = append(aggregates, *aggregate)
+	}
+	sort.Slice(aggregates, func(i, j int) bool {
+		if aggregates[i].TotalCalories != aggregates[j].TotalCalories {
+			return aggregates[i].TotalCalories > aggregates[j].TotalCalories
+		}
+		return aggregates[i].FoodName < aggregates[j].FoodName
+	})
+	return aggregates
+}
@@ -0,0 +1,40 @@
+package main
+
+import "testing"
+
+func TestAggregateByFoodIgnoresCase(t *testing.T) {
+	tests := []struct {
+		name  string
+		names []string
+	}{
+		{"same case", []string{"Chicken Breast", "Chicken Breast", "Chicken Breast"}},
+		{"mixed case", []string{"Chicken Breast", "chicken breast", "CHICKEN BREAST"}},
+		{"surrounding whitespace", []string{"Chicken Breast", " chicken breast", "Chicken Breast  "}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var entries []FoodEntry
+			for i, name := range tt.names {
+				entries = append(entries, FoodEntry{Date: []string{"2024-03-01", "2024-03-01", "2024-03-02"}[i], FoodName: name, Calories: 165, Protein: 31})
+			}
+			got := AggregateByFood(entries)
+			if len(got) != 1 {
+				t.Fatalf("got %d foods, want 1: %+v", len(got), got)
+			}
+			a := got[0]
+			if a.FoodName != "Chicken Breast" || a.TotalServings != 3 || a.DaysLogged != 2 || a.TotalCalories != 495 || a.TotalProtein != 93 {
+				t.Errorf("aggregate = %+v, want 3 servings over 2 days totalling 495 kcal and 93g protein", a)
+			}
+		})
+	}
+}
+
+func TestAggregateByFoodKeepsDistinctFoods(t *testing.T) {
+	got := AggregateByFood([]FoodEntry{
+		{FoodName: "Chicken Breast", Calories: 165},
+		{FoodName: "Chicken Thigh", Calories: 209},
+	})
+	if len(got) != 2 || got[0].FoodName != "Chicken Thigh" {
+		t.Errorf("aggregates = %+v, want two foods, thigh first", got)
+	}
+}
@@ -0,0 +1,143 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// foodCategoryKeywords map lowercase food name and Cronometer category
+// fragments to the food categories -food-category-budget targets
+var foodCategoryKeywords = map[string]string{
+	"vegetable": "vegetables", "broccoli": "vegetables", "spinach": "vegetables",
+	"kale": "vegetables", "cabbage": "vegetables", "carrot": "vegetables",
+	"tomato": "vegetables", "squash": "vegetables", "pepper": "vegetables",
+	"onion": "vegetables", "lettuce": "vegetables", "greens": "vegetables",
+	"cauliflower": "vegetables", "zucchini": "vegetables", "asparagus": "vegetables",
+	"cucumber": "vegetables", "mushroom": "vegetables", "sweet potato": "vegetables",
+	"eggplant": "vegetables", "butternut": "vegetables",
+
+	"fruit": "fruits", "apple": "fruits", "banana": "fruits", "berries": "fruits",
+	"berry": "fruits", "orange": "fruits", "grape": "fruits", "mango": "fruits",
+	"pear": "fruits", "peach": "fruits", "melon": "fruits", "pineapple": "fruits",
+
+	"beef": "proteins", "chicken": "proteins", "turkey": "proteins", "pork": "proteins",
+	"lamb": "proteins", "fish": "proteins", "salmon": "proteins", "tuna": "proteins",
+	"sardine": "proteins", "shrimp": "proteins", "egg": "proteins", "tofu": "proteins",
+	"tempeh": "proteins", "poultry": "proteins", "meat": "proteins", "finfish": "proteins",
+	"pepperoni": "proteins",
+
+	"bean": "legumes", "lentil": "legumes", "chickpea": "legumes", "hummus": "legumes",
+	"legume": "legumes", "edamame": "legumes",
+
+	"rice": "grains", "bread": "grains", "pasta": "grains", "oat": "grains",
+	"quinoa": "grains", "cereal": "grains", "tortilla": "grains",
+
+	"milk": "dairy", "yogurt": "dairy", "cheese": "dairy", "dairy": "dairy",
+
+	"olive oil": "fats", "avocado": "fats", "almond": "fats", "walnut": "fats",
+	"peanut": "fats", "cashew": "fats", "pecan": "fats", "nuts": "fats", "seed": "fats",
+	"butter": "fats", " oil": "fats", "oil,": "fats", "fats and oils": "fats",
+}
+
+// CategoryBudgetResult compares a food category's share of diary calories to its target
+type CategoryBudgetResult struct {
+	Category      string  `json:"category"`
+	TargetPercent float64 `json:"target_percent"`
+	ActualPercent float64 `json:"actual_percent"`
+	Difference    float64 `json:"difference"`
+	Met           bool    `json:"met"`
+}
+
+// parseCategoryBudget parses a -food-category-budget value such as
+// "vegetables=30pct,proteins=30pct". The "pct" or "%" suffix is optional and
+// the percentages may not sum to more than 100. Each category must be one
+// foodCategoryKeywords assigns.
+func parseCategoryBudget(value string) (map[string]float64, error) {
+	pairs, err := parseKeyValues(value)
+	if err != nil {
+		return nil, err
+	}
+	known := make(map[string]bool)
+	for _, category := range foodCategoryKeywords {
+		known[category] = true
+	}
+	targets := make(map[string]float64, len(pairs))
+	total := 0.0
+	for category, raw := range pairs {
+		category = strings.ToLower(category)
+		if !known[category] {
+			return nil, fmt.Errorf("unknown food category %q", category)
+		}
+		trimmed := strings.TrimSuffix(strings.TrimSuffix(raw, "pct"), "%")
+		v, err := strconv.ParseFloat(trimmed, 64)
+		if err != nil || v < 0 || v > 100 {
+			return nil, fmt.Errorf("expected a percentage from 0 to 100 for %s, got %q", category, raw)
+		}
+		targets[category] = v
+		total += v
+	}
+	if total > 100.5 {
+		return nil, fmt.Errorf("percentages must not sum to more than 100, got %g", total)
+	}
+	return targets, nil
+}
+
+// classifyFood returns the category of the longest classifier keyword in the
+// entry's name, falling back to its Cronometer category, or "" if none match.
+// Equal-length matches go to the alphabetically first keyword.
+func classifyFood(e FoodEntry, classifier map[string]string) string {
+	for _, text := range []string{e.FoodName, e.Category} {
+		text = strings.ToLower(text)
+		best := ""
+		for keyword := range classifier {
+			if !strings.Contains(text, keyword) {
+				continue
+			}
+			if len(keyword) > len(best) || (len(keyword) == len(best) && keyword < best) {
+				best = keyword
+			}
+		}
+		if best != "" {
+			return classifier[best]
+		}
+	}
+	return ""
+}
+
+// FoodCategoryBudget compares each target category's share of the entries'
+// calories to its target percentage, sorted by category. A target is met
+// when the category supplies at least that share.
+func FoodCategoryBudget(entries []FoodEntry, classifier map[string]string, targets map[string]float64) []CategoryBudgetResult {
+	calories := make(map[string]float64)
+	total := 0.0
+	for _, e := range entries {
+		if e.Calories <= 0 {
+			continue
+		}
+		total += e.Calories
+		calories[classifyFood(e, classifier)] += e.Calories
+	}
+
+	results := make([]CategoryBudgetResult, 0, len(targets))
+	for category, target := range targets {
+		actual := 0.0
+		if total > 0 {
+			actual = calories[category] / total * 100
+		}
+		actual = math.Round(actual*10) / 10
+		results = append(results, CategoryBudgetResult{
+			Category:      category,
+			TargetPercent: target,
+			ActualPercent: actual,
+			Difference:    math.Round((actual-target)*10) / 10,
+			Met:           actual >= target,
+		})
+	}
+	sort.Slice(results, func(i, j int) bool {
+		return results[i].Category < results[j].Category
+	})
+	return results
+}
@@ -0,0 +1,40 @@
+package main
+
+import "sort"
+
+// FrequencyEntry is how often a food was logged over the date range
+type FrequencyEntry struct {
+	FoodName         string  `json:"food_name"`
+	TimesPerWeek     float64 `json:"times_per_week"`
+	TotalOccurrences int     `json:"total_occurrences"`
+}
+
+// FoodLogFrequency counts each food's diary entries and divides by
+// totalWeeks, ordered from most to least frequent
+func FoodLogFrequency(entries []FoodEntry, totalWeeks float64) []FrequencyEntry {
+	byFood := make(map[string]*FrequencyEntry)
+	for _, e := range entries {
+		key := foodKey(e.FoodName)
+		entry, ok := byFood[key]
+		if !ok {
+			entry = &FrequencyEntry{FoodName: e.FoodName}
+			byFood[key] = entry
+		}
+		entry.TotalOccurrences++
+	}
+
+	frequencies := make([]FrequencyEntry, 0, len(byFood))
+	for _, entry := range byFood {
+		if totalWeeks > 0 {
+			entry.TimesPerWeek = float64(entry.TotalOccurrences) / totalWeeks
+		}
+		frequencies = append(frequencies, *entry)
+	}
+	sort.Slice(frequencies, func(i, j int) bool {
+		if frequencies[i].TotalOccurrences != frequencies[j].TotalOccurrences {
+			return frequencies[i].TotalOccurrences > frequencies[j].TotalOccurrences
+		}
+		return frequencies[i].FoodName < frequencies[j].FoodName
+	})
+	return frequencies
+}
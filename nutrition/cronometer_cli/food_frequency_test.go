@@ -0,0 +1,46 @@
+package main
+
+import (
+	"math"
+	"testing"
+)
+
+func TestFoodLogFrequencyFractionalWeeks(t *testing.T) {
+	entries := make([]FoodEntry, 6)
+	for i := range entries {
+		entries[i] = FoodEntry{FoodName: "Greek Yogurt"}
+	}
+	tests := []struct {
+		name string
+		days int
+		want float64
+	}{
+		{"exactly one week", 7, 6},
+		{"two weeks", 14, 3},
+		{"ten days", 10, 4.2},
+		{"three days", 3, 14},
+		{"no days", 0, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := FoodLogFrequency(entries, float64(tt.days)/7)
+			if len(got) != 1 || got[0].TotalOccurrences != 6 {
+				t.Fatalf("FoodLogFrequency = %+v, want one food logged 6 times", got)
+			}
+			if math.Abs(got[0].TimesPerWeek-tt.want) > 1e-9 {
+				t.Errorf("times per week over %d days = %g, want %g", tt.days, got[0].TimesPerWeek, tt.want)
+			}
+		})
+	}
+}
+
+func TestFoodLogFrequencyOrder(t *testing.T) {
+	entries := []FoodEntry{{FoodName: "Banana"}, {FoodName: "Apple"}, {FoodName: "Banana"}, {FoodName: "Carrot"}}
+	got := FoodLogFrequency(entries, 1)
+	want := []string{"Banana", "Apple", "Carrot"}
+	for i := range want {
+		if got[i].FoodName != want[i] {
+			t.Errorf("food %d = %s, want %s", i, got[i].FoodName, want[i])
+		}
+	}
+}
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"sort"
+	"strings"
+)
+
+// maxFoodPairs is how many pairings -food-pairing reports
+const maxFoodPairs = 10
+
+// FoodPair is two foods logged together in the same meal, with Food1
+// sorting before Food2 so each pair is counted once
+type FoodPair struct {
+	Food1             string `json:"food_1"`
+	Food2             string `json:"food_2"`
+	CooccurrenceCount int    `json:"cooccurrence_count"`
+}
+
+// FoodPairings counts how often each pair of foods appears in the same meal
+// (a diary meal group on one date), ordered from most to least common. An
+// empty meal counts every meal group; single-food meals add no pairs.
+func FoodPairings(entries []FoodEntry, meal string) []FoodPair {
+	meals := make(map[string]map[string]string)
+	for _, e := range entries {
+		if meal != "" && !strings.EqualFold(e.Meal, meal) {
+			continue
+		}
+		key := e.Date + "|" + e.Meal
+		if meals[key] == nil {
+			meals[key] = make(map[string]string)
+		}
+		meals[key][foodKey(e.FoodName)] = e.FoodName
+	}
+
+	pairs := make(map[[2]string]*FoodPair)
+	for _, foods := range meals {
+		keys := make([]string, 0, len(foods))
+		for key := range foods {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for i := 0; i < len(keys); i++ {
+			for j := i + 1; j < len(keys); j++ {
+				id := [2]string{keys[i], keys[j]}
+				if pairs[id] == nil {
+					pairs[id] = &FoodPair{Food1: foods[keys[i]], Food2: foods[keys[j]]}
+				}
+				pairs[id].CooccurrenceCount++
+			}
+		}
+	}
+
+	result := make([]FoodPair, 0, len(pairs))
+	for _, pair := range pairs {
+		result = append(result, *pair)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].CooccurrenceCount != result[j].CooccurrenceCount {
+			return result[i].CooccurrenceCount > result[j].CooccurrenceCount
+		}
+		if result[i].Food1 != result[j].Food1 {
+			return result[i].Food1 < result[j].Food1
+		}
+		return result[i].Food2 < result[j].Food2
+	})
+	return result
+}
@@ -0,0 +1,59 @@
+package main
+
+import "testing"
+
+func TestFoodPairingsSymmetric(t *testing.T) {
+	tests := []struct {
+		name    string
+		entries []FoodEntry
+	}{
+		{"logged in order", []FoodEntry{
+			{Date: "2024-03-01", Meal: "Breakfast", FoodName: "Eggs"},
+			{Date: "2024-03-01", Meal: "Breakfast", FoodName: "Toast"},
+			{Date: "2024-03-02", Meal: "Breakfast", FoodName: "Eggs"},
+			{Date: "2024-03-02", Meal: "Breakfast", FoodName: "Toast"},
+		}},
+		{"logged in reverse one day", []FoodEntry{
+			{Date: "2024-03-01", Meal: "Breakfast", FoodName: "Eggs"},
+			{Date: "2024-03-01", Meal: "Breakfast", FoodName: "Toast"},
+			{Date: "2024-03-02", Meal: "Breakfast", FoodName: "Toast"},
+			{Date: "2024-03-02", Meal: "Breakfast", FoodName: "eggs"},
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pairs := FoodPairings(tt.entries, "")
+			if len(pairs) != 1 {
+				t.Fatalf("pairs = %+v, want (eggs, toast) counted as one pair", pairs)
+			}
+			if pairs[0].CooccurrenceCount != 2 || foodKey(pairs[0].Food1) != "eggs" || foodKey(pairs[0].Food2) != "toast" {
+				t.Errorf("pair = %+v, want eggs before toast twice", pairs[0])
+			}
+		})
+	}
+}
+
+func TestFoodPairingsSingleFoodMeals(t *testing.T) {
+	entries := []FoodEntry{
+		{Date: "2024-03-01", Meal: "Breakfast", FoodName: "Oatmeal"},
+		{Date: "2024-03-01", Meal: "Lunch", FoodName: "Salad"},
+		{Date: "2024-03-01", Meal: "Dinner", FoodName: "Soup"},
+		{Date: "2024-03-01", Meal: "Dinner", FoodName: "soup"},
+	}
+	if pairs := FoodPairings(entries, ""); len(pairs) != 0 {
+		t.Errorf("pairs = %+v, want none from single-food meals", pairs)
+	}
+}
+
+func TestFoodPairingsMealFilter(t *testing.T) {
+	entries := []FoodEntry{
+		{Date: "2024-03-01", Meal: "Breakfast", FoodName: "Eggs"},
+		{Date: "2024-03-01", Meal: "Breakfast", FoodName: "Toast"},
+		{Date: "2024-03-01", Meal: "Dinner", FoodName: "Rice"},
+		{Date: "2024-03-01", Meal: "Dinner", FoodName: "Beans"},
+	}
+	pairs := FoodPairings(entries, "dinner")
+	if len(pairs) != 1 || pairs[0].Food1 != "Beans" || pairs[0].Food2 != "Rice" {
+		t.Errorf("pairs = %+v, want only the dinner pair", pairs)
+	}
+}
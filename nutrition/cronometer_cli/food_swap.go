@@ -0,0 +1,113 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// foodSwap is a parsed -simulate-swap value
+type foodSwap struct {
+	Remove string
+	Add    string
+}
+
+// SwapImpact is the average daily change in macros from replacing one food with another
+type SwapImpact struct {
+	RemoveFood        string  `json:"remove_food"`
+	AddFood           string  `json:"add_food"`
+	Swaps             int     `json:"swaps"`
+	Days              int     `json:"days"`
+	DailyCalorieDelta float64 `json:"daily_calorie_delta"`
+	DailyProteinDelta float64 `json:"daily_protein_delta"`
+	DailyFatDelta     float64 `json:"daily_fat_delta"`
+	DailyCarbsDelta   float64 `json:"daily_carbs_delta"`
+}
+
+// parseFoodSwap parses the -simulate-swap value, e.g.
+// "remove=Peanut Butter:add=Almond Butter"
+func parseFoodSwap(value string) (foodSwap, error) {
+	var swap foodSwap
+	for _, part := range strings.Split(value, ":") {
+		key, val, found := strings.Cut(part, "=")
+		key = strings.TrimSpace(key)
+		val = strings.TrimSpace(val)
+		if !found || val == "" {
+			return foodSwap{}, fmt.Errorf("expected key=value, got %q", part)
+		}
+		switch key {
+		case "remove":
+			swap.Remove = val
+		case "add":
+			swap.Add = val
+		default:
+			return foodSwap{}, fmt.Errorf("unknown key %q", key)
+		}
+	}
+	if swap.Remove == "" || swap.Add == "" {
+		return foodSwap{}, fmt.Errorf("expected remove=X:add=Y, got %q", value)
+	}
+	return swap, nil
+}
+
+// matchesFood reports whether a diary entry's name contains the food, ignoring case
+func matchesFood(e FoodEntry, food string) bool {
+	return strings.Contains(foodKey(e.FoodName), foodKey(food))
+}
+
+// SimulateFoodSwap replaces every diary entry matching removeFood with one
+// serving of addFood and returns the change in macros, averaged over the
+// days in the diary
+func SimulateFoodSwap(diary []FoodEntry, removeFood, addFood string, addFoodMacros DailyNutrition) SwapImpact {
+	impact := SwapImpact{RemoveFood: removeFood, AddFood: addFood}
+	dates := make(map[string]bool)
+	for _, e := range diary {
+		dates[e.Date] = true
+		if !matchesFood(e, removeFood) {
+			continue
+		}
+		impact.Swaps++
+		impact.DailyCalorieDelta += addFoodMacros.Calories - e.Calories
+		impact.DailyProteinDelta += addFoodMacros.Protein - e.Protein
+		impact.DailyFatDelta += addFoodMacros.Fat - e.Fat
+		impact.DailyCarbsDelta += addFoodMacros.Carbs - e.Carbs
+	}
+
+	impact.Days = len(dates)
+	if impact.Days > 0 {
+		n := float64(impact.Days)
+		impact.DailyCalorieDelta /= n
+		impact.DailyProteinDelta /= n
+		impact.DailyFatDelta /= n
+		impact.DailyCarbsDelta /= n
+	}
+	return impact
+}
+
+// swapFoodMacros returns one serving of the food to add: the local food
+// database's serving if it has one, otherwise the average diary entry for it
+func swapFoodMacros(name string, db FoodDatabase, diary []FoodEntry) (DailyNutrition, error) {
+	if food, ok := LookupLocalFood(db, name); ok {
+		return DailyNutrition{Calories: food.Calories, Protein: food.Protein, Fat: food.Fat, Carbs: food.Carbs}, nil
+	}
+
+	var macros DailyNutrition
+	n := 0
+	for _, e := range diary {
+		if !matchesFood(e, name) {
+			continue
+		}
+		macros.Calories += e.Calories
+		macros.Protein += e.Protein
+		macros.Fat += e.Fat
+		macros.Carbs += e.Carbs
+		n++
+	}
+	if n == 0 {
+		return DailyNutrition{}, fmt.Errorf("no nutrition found for %q; add it with -add-local-food", name)
+	}
+	macros.Calories /= float64(n)
+	macros.Protein /= float64(n)
+	macros.Fat /= float64(n)
+	macros.Carbs /= float64(n)
+	return macros, nil
+}
@@ -0,0 +1,96 @@
+package main
+
+import (
+	"math"
+	"sort"
+	"time"
+)
+
+// forecastDays is how many days -predict-next-week forecasts
+const forecastDays = 7
+
+// PredictedDay is a forecast of one future day's calories and macros
+type PredictedDay struct {
+	Date     string  `json:"date"`
+	Calories float64 `json:"calories"`
+	Fat      float64 `json:"fat"`
+	Carbs    float64 `json:"carbs"`
+	Protein  float64 `json:"protein"`
+}
+
+// ExponentialSmoothing returns the simple exponentially smoothed series,
+// starting from the first value and weighting each new value by alpha
+func ExponentialSmoothing(values []float64, alpha float64) []float64 {
+	if len(values) == 0 {
+		return nil
+	}
+	smoothed := make([]float64, len(values))
+	smoothed[0] = values[0]
+	for i := 1; i < len(values); i++ {
+		smoothed[i] = alpha*values[i] + (1-alpha)*smoothed[i-1]
+	}
+	return smoothed
+}
+
+// ForecastExponential predicts the next n values with Holt's linear
+// exponential smoothing, smoothing both the level and the trend by alpha.
+// A single value is forecast flat, and forecasts never go below zero.
+func ForecastExponential(values []float64, alpha float64, n int) []float64 {
+	if len(values) == 0 || n <= 0 {
+		return nil
+	}
+	level, trend := values[0], 0.0
+	if len(values) > 1 {
+		trend = values[1] - values[0]
+	}
+	for i := 1; i < len(values); i++ {
+		previous := level
+		level = alpha*values[i] + (1-alpha)*(level+trend)
+		trend = alpha*(level-previous) + (1-alpha)*trend
+	}
+	forecast := make([]float64, n)
+	for h := range forecast {
+		forecast[h] = math.Max(level+float64(h+1)*trend, 0)
+	}
+	return forecast
+}
+
+// predictNextWeek forecasts calories and macros for the 7 days after the
+// last record, treating the logged days as consecutive
+func predictNextWeek(records []DailyNutrition, alpha float64) ([]PredictedDay, error) {
+	if len(records) == 0 {
+		return nil, nil
+	}
+	sorted := make([]DailyNutrition, len(records))
+	copy(sorted, records)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].Date < sorted[j].Date
+	})
+	last, err := time.Parse("2006-01-02", sorted[len(sorted)-1].Date)
+	if err != nil {
+		return nil, err
+	}
+	series := func(value func(DailyNutrition) float64) []float64 {
+		values := make([]float64, len(sorted))
+		for i, d := range sorted {
+			values[i] = value(d)
+		}
+		return ForecastExponential(values, alpha, forecastDays)
+	}
+	calories := series(func(d DailyNutrition) float64 { return d.Calories })
+	fat := series(func(d DailyNutrition) float64 { return d.Fat })
+	carbs := series(func(d DailyNutrition) float64 { return d.Carbs })
+	protein := series(func(d DailyNutrition) float64 { return d.Protein })
+
+	days := make([]PredictedDay, forecastDays)
+	for i := range days {
+		days[i] = PredictedDay{
+			Date:     last.AddDate(0, 0, i+1).Format("2006-01-02"),
+			Calories: math.Round(calories[i]),
+			Fat:      math.Round(fat[i]*10) / 10,
+			Carbs:    math.Round(carbs[i]*10) / 10,
+			Protein:  math.Round(protein[i]*10) / 10,
+		}
+	}
+	return days, nil
+}
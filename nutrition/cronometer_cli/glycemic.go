@@ -0,0 +1,242 @@
+package main
+
+import (
+	"sort"
+	"strings"
+)
+
+// highGICarbShare is the share of a day's carbs from high-GI foods above which
+// the day is flagged
+const highGICarbShare = 0.3
+
+// highGIFoods maps common high glycemic index foods (GI of 70 or more, from the
+// University of Sydney GI database) to their typical GI. Keys are matched as
+// substrings of lowercased diary food names.
+var highGIFoods = map[string]int{
+	"white bread":   75,
+	"bagel":         72,
+	"white rice":    73,
+	"jasmine rice":  89,
+	"rice cake":     82,
+	"corn flakes":   81,
+	"rice krispies": 82,
+	"puffed rice":   87,
+	"instant oat":   79,
+	"baked potato":  85,
+	"mashed potato": 87,
+	"french fries":  75,
+	"pretzel":       83,
+	"watermelon":    76,
+	"sports drink":  78,
+	"gatorade":      78,
+	"doughnut":      76,
+	"donut":         76,
+	"waffle":        76,
+	"cracker":       74,
+	"glucose":       100,
+	"dates":         103,
+}
+
+// HighGIEntry is a high-GI food and how many diary entries logged it. An
+// entry of "3 slices" counts once, since the amounts are in mixed units.
+type HighGIEntry struct {
+	FoodName string `json:"food_name"`
+	GI       int    `json:"gi"`
+	Entries  int    `json:"entries"`
+}
+
+// HighGIDay lists a day's high-GI foods and the share of carbs they supplied
+type HighGIDay struct {
+	Foods     []HighGIEntry `json:"foods"`
+	CarbShare float64       `json:"carb_share"`
+	Flagged   bool          `json:"flagged"`
+}
+
+// giMatch returns the GI for a food name, preferring the longest matching key
+func giMatch(name string, giTable map[string]int) (int, bool) {
+	key := foodKey(name)
+	best := ""
+	for food := range giTable {
+		if strings.Contains(key, food) && len(food) > len(best) {
+			best = food
+		}
+	}
+	if best == "" {
+		return 0, false
+	}
+	return giTable[best], true
+}
+
+// DetectHighGIFoods returns the entries' high-GI foods with how many entries
+// logged each, ordered by GI from highest
+func DetectHighGIFoods(entries []FoodEntry, hiGITable map[string]int) []HighGIEntry {
+	byFood := make(map[string]*HighGIEntry)
+	var order []string
+	for _, e := range entries {
+		gi, ok := giMatch(e.FoodName, hiGITable)
+		if !ok {
+			continue
+		}
+		key := foodKey(e.FoodName)
+		if byFood[key] == nil {
+			byFood[key] = &HighGIEntry{FoodName: e.FoodName, GI: gi}
+			order = append(order, key)
+		}
+		byFood[key].Entries++
+	}
+
+	foods := make([]HighGIEntry, 0, len(order))
+	for _, key := range order {
+		foods = append(foods, *byFood[key])
+	}
+	sort.SliceStable(foods, func(i, j int) bool {
+		return foods[i].GI > foods[j].GI
+	})
+	return foods
+}
+
+// applyHighGI attaches each day's high-GI foods, flagging days where they
+// supplied more than 30% of the diary's carbs
+func applyHighGI(records []DailyNutrition, diary []FoodEntry) {
+	byDate := groupEntriesByDate(diary)
+	for i := range records {
+		entries := byDate[records[i].Date]
+		foods := DetectHighGIFoods(entries, highGIFoods)
+		if len(foods) == 0 {
+			continue
+		}
+
+		var carbs, highGICarbs float64
+		for _, e := range entries {
+			carbs += e.Carbs
+			if _, ok := giMatch(e.FoodName, highGIFoods); ok {
+				highGICarbs += e.Carbs
+			}
+		}
+		day := &HighGIDay{Foods: foods}
+		if carbs > 0 {
+			day.CarbShare = highGICarbs / carbs
+		}
+		day.Flagged = day.CarbShare > highGICarbShare
+		records[i].HighGI = day
+	}
+}
+
+// Daily glycemic load boundaries: below 80 is low and above 120 is high
+const (
+	lowGlycemicLoad  = 80.0
+	highGlycemicLoad = 120.0
+)
+
+// glycemicIndexFoods maps common foods to their typical GI (glucose = 100),
+// from the International Tables of Glycemic Index (Atkinson et al. 2008).
+// Keys are matched as substrings of lowercased diary food names.
+var glycemicIndexFoods = map[string]int{
+	"white bread":    75,
+	"whole wheat":    74,
+	"sourdough":      54,
+	"rye bread":      58,
+	"bagel":          72,
+	"white rice":     73,
+	"brown rice":     68,
+	"basmati":        58,
+	"jasmine rice":   89,
+	"rice":           73,
+	"pasta":          49,
+	"spaghetti":      49,
+	"noodle":         53,
+	"oatmeal":        55,
+	"rolled oats":    55,
+	"instant oat":    79,
+	"corn flakes":    81,
+	"muesli":         57,
+	"quinoa":         53,
+	"couscous":       65,
+	"barley":         28,
+	"potato":         78,
+	"baked potato":   85,
+	"mashed potato":  87,
+	"french fries":   75,
+	"sweet potato":   63,
+	"corn":           52,
+	"apple":          36,
+	"banana":         51,
+	"orange":         43,
+	"grape":          59,
+	"mango":          51,
+	"pineapple":      59,
+	"watermelon":     76,
+	"strawberr":      40,
+	"cherr":          22,
+	"pear":           38,
+	"peach":          42,
+	"kiwi":           53,
+	"raisin":         64,
+	"dates":          103,
+	"milk":           39,
+	"yogurt":         41,
+	"ice cream":      51,
+	"chickpea":       28,
+	"lentil":         32,
+	"kidney bean":    24,
+	"black bean":     30,
+	"soybean":        16,
+	"peanut":         14,
+	"honey":          61,
+	"sugar":          65,
+	"glucose":        100,
+	"cola":           63,
+	"orange juice":   50,
+	"apple juice":    41,
+	"pretzel":        83,
+	"popcorn":        65,
+	"rice cake":      82,
+	"cracker":        74,
+	"chocolate":      40,
+	"doughnut":       76,
+	"pizza":          80,
+	"pancake":        67,
+	"sports drink":   78,
+	"chocolate milk": 37,
+	"rice krispies":  82,
+}
+
+// DailyGlycemicLoad sums GL = GI × available carbs / 100 over the entries
+// found in giTable, with available carbs being carbs less fiber
+func DailyGlycemicLoad(entries []FoodEntry, giTable map[string]int) float64 {
+	var load float64
+	for _, e := range entries {
+		gi, ok := giMatch(e.FoodName, giTable)
+		if !ok {
+			continue
+		}
+		if carbs := e.Carbs - e.Fiber; carbs > 0 {
+			load += float64(gi) * carbs / 100
+		}
+	}
+	return load
+}
+
+// GlycemicLoadCategory rates a daily glycemic load as "low" (below 80),
+// "medium" (80 to 120), or "high" (above 120)
+func GlycemicLoadCategory(load float64) string {
+	switch {
+	case load < lowGlycemicLoad:
+		return "low"
+	case load <= highGlycemicLoad:
+		return "medium"
+	default:
+		return "high"
+	}
+}
+
+// applyGlycemicLoad sets each day's glycemic load from its diary
+func applyGlycemicLoad(records []DailyNutrition, diary []FoodEntry) {
+	byDate := groupEntriesByDate(diary)
+	for i := range records {
+		d := &records[i]
+		load := DailyGlycemicLoad(byDate[d.Date], glycemicIndexFoods)
+		d.GlycemicLoad = &load
+		d.GlycemicLoadCategory = GlycemicLoadCategory(load)
+	}
+}
@@ -0,0 +1,44 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDetectHighGIFoodsCountsEntries(t *testing.T) {
+	entries := []FoodEntry{
+		{FoodName: "White Bread", Amount: 3, Unit: "slice"},
+		{FoodName: "Jasmine Rice, Cooked", Amount: 200, Unit: "g"},
+		{FoodName: "White Bread", Amount: 1, Unit: "slice"},
+		{FoodName: "Broccoli", Amount: 100, Unit: "g"},
+	}
+	want := []HighGIEntry{
+		{FoodName: "Jasmine Rice, Cooked", GI: 89, Entries: 1},
+		{FoodName: "White Bread", GI: 75, Entries: 2},
+	}
+	if got := DetectHighGIFoods(entries, highGIFoods); !reflect.DeepEqual(got, want) {
+		t.Errorf("DetectHighGIFoods = %+v, want %+v", got, want)
+	}
+}
+
+func TestApplyHighGIFlagsCarbShare(t *testing.T) {
+	diary := []FoodEntry{
+		{Date: "2024-03-01", FoodName: "Bagel", Carbs: 50},
+		{Date: "2024-03-01", FoodName: "Lentils", Carbs: 50},
+		{Date: "2024-03-02", FoodName: "Bagel", Carbs: 20},
+		{Date: "2024-03-02", FoodName: "Lentils", Carbs: 80},
+		{Date: "2024-03-03", FoodName: "Lentils", Carbs: 80},
+	}
+	records := []DailyNutrition{{Date: "2024-03-01"}, {Date: "2024-03-02"}, {Date: "2024-03-03"}}
+	applyHighGI(records, diary)
+
+	if day := records[0].HighGI; day == nil || day.CarbShare != 0.5 || !day.Flagged {
+		t.Errorf("half the carbs from a bagel = %+v, want a flagged 0.5 share", day)
+	}
+	if day := records[1].HighGI; day == nil || day.CarbShare != 0.2 || day.Flagged {
+		t.Errorf("a fifth of the carbs from a bagel = %+v, want an unflagged 0.2 share", day)
+	}
+	if records[2].HighGI != nil {
+		t.Errorf("day without high-GI foods = %+v, want none", records[2].HighGI)
+	}
+}
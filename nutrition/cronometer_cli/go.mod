@@ -1,5 +1,10 @@
 module cronometer_cli
 
-go 1.21
+go 1.24.0
 
-require github.com/jrmycanady/gocronometer v1.5.1
+require (
+	github.com/go-pdf/fpdf v0.9.0
+	github.com/jrmycanady/gocronometer v1.5.1
+)
+
+require golang.org/x/net v0.46.0 // indirect
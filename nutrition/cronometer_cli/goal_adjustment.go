@@ -0,0 +1,67 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+// maxWeeklyLossPct is the fastest weekly loss, as a percent of bodyweight,
+// before the deficit risks muscle loss
+const maxWeeklyLossPct = 1.0
+
+// kcalPerLb is the approximate energy in a pound of body fat
+const kcalPerLb = 3500.0
+
+// GoalAdjustment is a recommended change to the daily calorie goal
+type GoalAdjustment struct {
+	WeightTrendLbsPerWeek    float64 `json:"weight_trend_lbs_per_week"`
+	CurrentCalorieGoal       float64 `json:"current_calorie_goal"`
+	RecommendedCalorieChange int     `json:"recommended_calorie_change"`
+	Reason                   string  `json:"reason"`
+}
+
+// RecommendGoalAdjustment recommends eating more when weight is falling
+// faster than 1% of bodyweight per week. weightTrend is in lbs per week,
+// negative when losing. The change closes the excess loss at 3500 kcal per
+// pound, rounded to the nearest 50 kcal.
+func RecommendGoalAdjustment(weightTrend float64, currentCalorieGoal, bodyWeightLbs float64) GoalAdjustment {
+	adjustment := GoalAdjustment{WeightTrendLbsPerWeek: weightTrend, CurrentCalorieGoal: currentCalorieGoal}
+	maxLoss := bodyWeightLbs * maxWeeklyLossPct / 100
+	if -weightTrend <= maxLoss {
+		adjustment.Reason = fmt.Sprintf("losing %.1f lbs/week is within %.0f%% of bodyweight (%.1f lbs/week)", math.Max(-weightTrend, 0), maxWeeklyLossPct, maxLoss)
+		return adjustment
+	}
+
+	excessPerDay := (-weightTrend - maxLoss) * kcalPerLb / 7
+	adjustment.RecommendedCalorieChange = int(math.Round(excessPerDay/50) * 50)
+	adjustment.Reason = fmt.Sprintf("losing %.1f lbs/week is faster than %.0f%% of bodyweight (%.1f lbs/week) and risks muscle loss", -weightTrend, maxWeeklyLossPct, maxLoss)
+	return adjustment
+}
+
+// weeklyWeightTrend fits weight in lbs against days elapsed and returns the
+// slope in lbs per week along with the latest weight
+func weeklyWeightTrend(series []WeightMeasurement) (trend, latestLbs float64, err error) {
+	if len(series) == 0 {
+		return 0, 0, fmt.Errorf("no weight measurements")
+	}
+	first, err := time.Parse("2006-01-02", series[0].Date)
+	if err != nil {
+		return 0, 0, err
+	}
+	x := make([]float64, 0, len(series))
+	y := make([]float64, 0, len(series))
+	for _, w := range series {
+		date, err := time.Parse("2006-01-02", w.Date)
+		if err != nil {
+			continue
+		}
+		x = append(x, date.Sub(first).Hours()/24)
+		y = append(y, w.WeightKg/kgPerLb)
+	}
+	model, err := fitOLS(x, y)
+	if err != nil {
+		return 0, 0, err
+	}
+	return model.Slope * 7, series[len(series)-1].WeightKg / kgPerLb, nil
+}
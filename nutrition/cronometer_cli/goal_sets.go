@@ -0,0 +1,93 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// GoalSets are named goal sets stored as JSON, keyed by set name then nutrient
+type GoalSets map[string]map[string]float64
+
+// goalSetsPath returns the location of goals.json
+func goalSetsPath() (string, error) {
+	dir, err := configDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "goals.json"), nil
+}
+
+// loadGoalSets reads the goal sets at path; a missing file has no sets
+func loadGoalSets(path string) (GoalSets, error) {
+	sets := GoalSets{}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return sets, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %v", path, err)
+	}
+	if err := json.Unmarshal(data, &sets); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %v", path, err)
+	}
+	return sets, nil
+}
+
+// StoreGoals saves goals under name at path, replacing any set with that name
+func StoreGoals(path, name string, goals map[string]float64) error {
+	sets, err := loadGoalSets(path)
+	if err != nil {
+		return err
+	}
+	sets[name] = goals
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("failed to create %s: %v", filepath.Dir(path), err)
+	}
+	data, err := json.MarshalIndent(sets, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode goal sets: %v", err)
+	}
+	return os.WriteFile(path, data, 0o644)
+}
+
+// LoadGoals returns the goal set stored under name at path. Every goal must
+// name a known nutrient, so a hand-edited typo is reported rather than
+// breaking the goal comparison later.
+func LoadGoals(path, name string) (map[string]float64, error) {
+	sets, err := loadGoalSets(path)
+	if err != nil {
+		return nil, err
+	}
+	goals, ok := sets[name]
+	if !ok {
+		return nil, fmt.Errorf("no goal set named %q in %s", name, path)
+	}
+	var probe DailyNutrition
+	for nutrient := range goals {
+		if nutrientField(&probe, nutrient) == nil {
+			return nil, fmt.Errorf("goal set %q in %s has unknown nutrient %q", name, path, nutrient)
+		}
+	}
+	return goals, nil
+}
+
+// mergeGoals applies explicitly set -goal-* flags on top of a loaded goal set.
+// An explicit zero removes the loaded goal.
+func mergeGoals(loaded, explicit map[string]float64) map[string]float64 {
+	merged := make(map[string]float64, len(loaded))
+	for name, target := range loaded {
+		merged[name] = target
+	}
+	for name, value := range explicit {
+		if value > 0 {
+			merged[name] = value
+		} else {
+			delete(merged, name)
+		}
+	}
+	return merged
+}
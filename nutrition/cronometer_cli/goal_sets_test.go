@@ -0,0 +1,110 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLoadGoalsRejectsUnknownNutrients(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "goals.json")
+	data := `{"cut": {"calories": 1800, "protein": 160}, "typo": {"calories": 1800, "protien": 160}}`
+	if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		set     string
+		wantErr string
+	}{
+		{"cut", ""},
+		{"typo", `unknown nutrient "protien"`},
+		{"bulk", `no goal set named "bulk"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.set, func(t *testing.T) {
+			goals, err := LoadGoals(path, tt.set)
+			if tt.wantErr == "" {
+				if err != nil || goals["protein"] != 160 {
+					t.Fatalf("LoadGoals = %v, %v; want the cut set", goals, err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("LoadGoals error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestStoreAndLoadGoalsRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config", "goals.json")
+	if err := StoreGoals(path, "maintain", map[string]float64{"calories": 2400}); err != nil {
+		t.Fatal(err)
+	}
+	if err := StoreGoals(path, "cut", map[string]float64{"calories": 1900}); err != nil {
+		t.Fatal(err)
+	}
+	goals, err := LoadGoals(path, "maintain")
+	if err != nil || goals["calories"] != 2400 {
+		t.Errorf("LoadGoals(maintain) = %v, %v; want 2400 kcal kept alongside cut", goals, err)
+	}
+}
+
+func TestMergeGoals(t *testing.T) {
+	loaded := map[string]float64{"calories": 1800, "protein": 160, "fiber": 30}
+	tests := []struct {
+		name     string
+		explicit map[string]float64
+		want     map[string]float64
+	}{
+		{"loaded set alone", nil, map[string]float64{"calories": 1800, "protein": 160, "fiber": 30}},
+		{"flag overrides the set", map[string]float64{"protein": 180}, map[string]float64{"calories": 1800, "protein": 180, "fiber": 30}},
+		{"flag adds to the set", map[string]float64{"sodium": 2300}, map[string]float64{"calories": 1800, "protein": 160, "fiber": 30, "sodium": 2300}},
+		{"zero flag removes a goal", map[string]float64{"fiber": 0}, map[string]float64{"calories": 1800, "protein": 160}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := mergeGoals(loaded, tt.explicit)
+			if len(got) != len(tt.want) {
+				t.Fatalf("mergeGoals = %v, want %v", got, tt.want)
+			}
+			for name, want := range tt.want {
+				if got[name] != want {
+					t.Errorf("%s = %g, want %g", name, got[name], want)
+				}
+			}
+		})
+	}
+	if loaded["protein"] != 160 || len(loaded) != 3 {
+		t.Errorf("mergeGoals changed the loaded set: %v", loaded)
+	}
+}
+
+func TestNewPipelineLoadsGoalSet(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	path := filepath.Join(home, ".config", "cronometer_cli", "goals.json")
+	if err := StoreGoals(path, "cut", map[string]float64{"calories": 1800, "protein": 160}); err != nil {
+		t.Fatal(err)
+	}
+
+	// cfg.Goals holds every -goal-* value including defaults; only the
+	// explicitly set flags survive over the loaded set
+	p, err := NewPipeline(Config{
+		OutputFormat:  "json",
+		DateLayout:    "2006-01-02",
+		ChunkStrategy: "date",
+		Username:      "me",
+		Password:      "secret",
+		LoadGoals:     "cut",
+		Goals:         map[string]float64{"calories": 2000, "protein": 180},
+		ExplicitGoals: map[string]float64{"protein": 180},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.goals["calories"] != 1800 || p.goals["protein"] != 180 {
+		t.Errorf("goals = %v, want the set's 1800 kcal and the flag's 180g protein", p.goals)
+	}
+}
@@ -0,0 +1,132 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+)
+
+// fiberPerThousandKcal is the USDA fiber recommendation in grams per 1000 kcal
+const fiberPerThousandKcal = 14.0
+
+// minimumGoals are nutrients where meeting the goal means reaching at least
+// the target; every other goal is a limit that must not be exceeded
+var minimumGoals = map[string]bool{
+	"protein":   true,
+	"fiber":     true,
+	"vitamin_d": true,
+	"b12":       true,
+	"omega_3":   true,
+}
+
+// Plausibility limits for -validate-goals. The protein floor is 0.5 g/kg for
+// a 50 kg adult, so it is safe for anyone heavier.
+const (
+	minPlausibleCalories = 1000.0
+	minPlausibleProtein  = 0.5 * 50
+	minPlausibleFat      = 20.0
+	maxPlausibleCarbs    = 500.0
+)
+
+// GoalWarning flags a goal value that is physiologically implausible or unsafe
+type GoalWarning struct {
+	Goal    string
+	Value   float64
+	Message string
+}
+
+// ValidateGoals checks goals against rough physiological limits, in goal order
+func ValidateGoals(goals map[string]float64) []GoalWarning {
+	var warnings []GoalWarning
+	for _, name := range goalNames(goals) {
+		value := goals[name]
+		var message string
+		switch {
+		case name == "calories" && value < minPlausibleCalories:
+			message = fmt.Sprintf("below %.0f kcal risks starvation", minPlausibleCalories)
+		case name == "protein" && value < minPlausibleProtein:
+			message = fmt.Sprintf("below %.0fg is under 0.5 g/kg even for a 50 kg adult", minPlausibleProtein)
+		case name == "fat" && value < minPlausibleFat:
+			message = fmt.Sprintf("below %.0fg risks essential fatty acid deficiency", minPlausibleFat)
+		case name == "carbs" && value > maxPlausibleCarbs:
+			message = fmt.Sprintf("above %.0fg is very high", maxPlausibleCarbs)
+		default:
+			continue
+		}
+		warnings = append(warnings, GoalWarning{Goal: name, Value: value, Message: message})
+	}
+	return warnings
+}
+
+// GoalResult compares one day's intake of a nutrient against its goal
+type GoalResult struct {
+	Target float64 `json:"target"`
+	Actual float64 `json:"actual"`
+	Met    bool    `json:"met"`
+}
+
+// collectGoals returns the goals whose flag was set to a positive value
+func collectGoals(flags map[string]*float64) map[string]float64 {
+	goals := make(map[string]float64)
+	for name, value := range flags {
+		if *value > 0 {
+			goals[name] = *value
+		}
+	}
+	return goals
+}
+
+// goalNames returns the goal names in sorted order
+func goalNames(goals map[string]float64) []string {
+	names := make([]string, 0, len(goals))
+	for name := range goals {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// fiberGoalForCalories scales the fiber goal to a day's calorie intake
+func fiberGoalForCalories(calories float64) float64 {
+	return fiberPerThousandKcal * calories / 1000
+}
+
+// dailyGoals returns the goals that apply to a single day. With
+// computeFiberGoal set, the fiber goal is derived from the day's calories and
+// overrides any static fiber goal.
+func dailyGoals(d DailyNutrition, goals map[string]float64, computeFiberGoal bool) map[string]float64 {
+	day := make(map[string]float64, len(goals)+1)
+	for name, target := range goals {
+		day[name] = target
+	}
+	if computeFiberGoal {
+		day["fiber"] = fiberGoalForCalories(d.Calories)
+	}
+	return day
+}
+
+// goalMet reports whether actual satisfies the target for the named nutrient
+func goalMet(name string, actual, target float64) bool {
+	if minimumGoals[name] {
+		return actual >= target
+	}
+	return actual <= target
+}
+
+// applyGoals adds a goal comparison for every configured goal to each record
+func applyGoals(records []DailyNutrition, goals map[string]float64, computeFiberGoal bool) {
+	for i := range records {
+		day := dailyGoals(records[i], goals, computeFiberGoal)
+		if len(day) == 0 {
+			continue
+		}
+		records[i].Goals = make(map[string]GoalResult, len(day))
+		for name, target := range day {
+			actual := *nutrientField(&records[i], name)
+			records[i].Goals[name] = GoalResult{
+				Target: target,
+				Actual: actual,
+				Met:    goalMet(name, actual, target),
+			}
+		}
+	}
+}
@@ -0,0 +1,36 @@
+package main
+
+import "testing"
+
+func TestComputedFiberGoal(t *testing.T) {
+	tests := []struct {
+		calories float64
+		want     float64
+	}{
+		{1000, 14},
+		{1500, 21},
+		{2000, 28},
+		{2500, 35},
+	}
+	for _, tt := range tests {
+		// A static -goal-fiber of 50 is overridden by the computed goal
+		records := []DailyNutrition{{Date: "2024-03-01", Calories: tt.calories, Fiber: 30}}
+		applyGoals(records, map[string]float64{"fiber": 50}, true)
+
+		got := records[0].Goals["fiber"]
+		if got.Target != tt.want {
+			t.Errorf("%g kcal: fiber goal = %g, want %g", tt.calories, got.Target, tt.want)
+		}
+		if wantMet := 30 >= tt.want; got.Met != wantMet {
+			t.Errorf("%g kcal: 30g fiber met = %v, want %v", tt.calories, got.Met, wantMet)
+		}
+	}
+}
+
+func TestStaticFiberGoalWithoutCompute(t *testing.T) {
+	records := []DailyNutrition{{Date: "2024-03-01", Calories: 2000, Fiber: 30}}
+	applyGoals(records, map[string]float64{"fiber": 50}, false)
+	if got := records[0].Goals["fiber"]; got.Target != 50 || got.Met {
+		t.Errorf("fiber goal = %+v, want the static 50g target unmet", got)
+	}
+}
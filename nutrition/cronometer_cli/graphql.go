@@ -0,0 +1,67 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/jrmycanady/gocronometer"
+)
+
+// cronometerGraphQLURL is the Cronometer GraphQL endpoint
+var cronometerGraphQLURL = "https://cronometer.com/graphql"
+
+// graphQLResponse is the standard GraphQL response envelope
+type graphQLResponse struct {
+	Data   json.RawMessage `json:"data"`
+	Errors []struct {
+		Message string `json:"message"`
+	} `json:"errors"`
+}
+
+// FetchNutritionGraphQL sends a GraphQL query using the logged-in client's
+// session and returns the response's data field
+func FetchNutritionGraphQL(ctx context.Context, client *gocronometer.Client, query string, variables map[string]interface{}) (json.RawMessage, error) {
+	body, err := json.Marshal(map[string]interface{}{"query": query, "variables": variables})
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode GraphQL request: %v", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, cronometerGraphQLURL, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to build GraphQL request: %v", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := client.HTTPClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to post GraphQL query: %v", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read GraphQL response: %v", err)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("GraphQL endpoint returned status %d: %s", resp.StatusCode, string(respBody))
+	}
+
+	var parsed graphQLResponse
+	if err := json.Unmarshal(respBody, &parsed); err != nil {
+		return nil, fmt.Errorf("failed to decode GraphQL response: %v", err)
+	}
+	if len(parsed.Errors) > 0 {
+		messages := make([]string, len(parsed.Errors))
+		for i, e := range parsed.Errors {
+			messages[i] = e.Message
+		}
+		return nil, fmt.Errorf("GraphQL errors: %s", strings.Join(messages, "; "))
+	}
+	return parsed.Data, nil
+}
@@ -0,0 +1,79 @@
+package main
+
+import "time"
+
+// GH support thresholds: arginine plus ornithine above 3g in the 2 hours
+// before a workout is the amount studied for growth hormone release
+const (
+	ghSupportAminoG       = 3.0
+	preWorkoutWindowHours = 2
+)
+
+// GHSupportReport is a day's arginine plus ornithine, overall and before its workouts
+type GHSupportReport struct {
+	TotalG float64 `json:"total_g"`
+	// PreWorkoutG is the most logged in the window before any of the day's
+	// timed workouts, left out on days without one
+	PreWorkoutG *float64 `json:"pre_workout_g,omitempty"`
+	Supportive  bool     `json:"supportive"`
+}
+
+// ArgininePlusOrnithine sums the arginine and ornithine of the diary entries
+func ArgininePlusOrnithine(diary []FoodEntry) float64 {
+	var total float64
+	for _, e := range diary {
+		total += e.Arginine + e.Ornithine
+	}
+	return total
+}
+
+// allocateAminos spreads the day's arginine and ornithine over its entries by
+// their share of the day's protein, since the servings export has neither
+func allocateAminos(d DailyNutrition, entries []FoodEntry) []FoodEntry {
+	var protein float64
+	for _, e := range entries {
+		protein += e.Protein
+	}
+	allocated := make([]FoodEntry, len(entries))
+	for i, e := range entries {
+		if protein > 0 {
+			share := e.Protein / protein
+			e.Arginine, e.Ornithine = d.Arginine*share, d.Ornithine*share
+		}
+		allocated[i] = e
+	}
+	return allocated
+}
+
+// applyGHSupport sets each day's arginine plus ornithine and flags days where
+// more than 3g was logged in the 2 hours before a workout
+func applyGHSupport(records []DailyNutrition, diary []FoodEntry, activities []ActivityEntry) {
+	byDate := groupEntriesByDate(diary)
+	workouts := make(map[string][]ActivityEntry)
+	for _, a := range activities {
+		if hasClockTime(a.Start) {
+			workouts[a.Date] = append(workouts[a.Date], a)
+		}
+	}
+
+	for i := range records {
+		d := &records[i]
+		entries := allocateAminos(*d, byDate[d.Date])
+		report := &GHSupportReport{TotalG: d.Arginine + d.Ornithine}
+		for _, a := range workouts[d.Date] {
+			since := a.Start.Add(-preWorkoutWindowHours * time.Hour)
+			var before []FoodEntry
+			for _, e := range entries {
+				if hasClockTime(e.Time) && !e.Time.Before(since) && !e.Time.After(a.Start) {
+					before = append(before, e)
+				}
+			}
+			amount := ArgininePlusOrnithine(before)
+			if report.PreWorkoutG == nil || amount > *report.PreWorkoutG {
+				report.PreWorkoutG = &amount
+			}
+		}
+		report.Supportive = report.PreWorkoutG != nil && *report.PreWorkoutG > ghSupportAminoG
+		d.GHSupport = report
+	}
+}
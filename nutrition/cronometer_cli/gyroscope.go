@@ -0,0 +1,82 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+// gyroscopeEventsURL is the Gyroscope REST endpoint that accepts nutrition events
+var gyroscopeEventsURL = "https://api.gyrosco.pe/v1/events"
+
+// gyroscopeEvent is a single nutrition event in Gyroscope's event format
+type gyroscopeEvent struct {
+	ExternalID string             `json:"external_id"`
+	Type       string             `json:"type"`
+	Timestamp  string             `json:"timestamp"`
+	Data       gyroscopeNutrition `json:"data"`
+}
+
+// gyroscopeNutrition are the nutrition values attached to a Gyroscope event
+type gyroscopeNutrition struct {
+	Calories float64 `json:"calories"`
+	Fat      float64 `json:"fat_g"`
+	Carbs    float64 `json:"carbs_g"`
+	Protein  float64 `json:"protein_g"`
+	Fiber    float64 `json:"fiber_g"`
+	Sodium   float64 `json:"sodium_mg"`
+}
+
+// ExportToGyroscope posts each day as a Gyroscope nutrition event. The date
+// is used as the event's external_id so re-exporting a day replaces it
+// instead of creating a duplicate.
+func ExportToGyroscope(ctx context.Context, apiKey string, records []DailyNutrition) error {
+	events := make([]gyroscopeEvent, 0, len(records))
+	for _, d := range records {
+		date, err := time.Parse("2006-01-02", d.Date)
+		if err != nil {
+			return fmt.Errorf("failed to parse date %q: %v", d.Date, err)
+		}
+		events = append(events, gyroscopeEvent{
+			ExternalID: d.Date,
+			Type:       "nutrition",
+			Timestamp:  date.Format(time.RFC3339),
+			Data: gyroscopeNutrition{
+				Calories: d.Calories,
+				Fat:      d.Fat,
+				Carbs:    d.Carbs,
+				Protein:  d.Protein,
+				Fiber:    d.Fiber,
+				Sodium:   d.Sodium,
+			},
+		})
+	}
+
+	body, err := json.Marshal(map[string]interface{}{"events": events})
+	if err != nil {
+		return fmt.Errorf("failed to encode Gyroscope events: %v", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, gyroscopeEventsURL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to build Gyroscope request: %v", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+apiKey)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to post to Gyroscope: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		respBody, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("Gyroscope returned status %d: %s", resp.StatusCode, string(respBody))
+	}
+	return nil
+}
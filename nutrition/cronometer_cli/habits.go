@@ -0,0 +1,68 @@
+package main
+
+import "sort"
+
+// gapAdherenceThreshold is the share of days below which a minimum goal counts as a gap
+const gapAdherenceThreshold = 0.5
+
+// habitFoods are easy additions that address a nutrient gap
+var habitFoods = map[string]string{
+	"protein": "a cup of Greek yogurt",
+	"fiber":   "a handful of almonds",
+	"omega_3": "a serving of salmon or sardines",
+	"sodium":  "a pinch of salt",
+}
+
+// HabitSuggestion links a food that fills a nutrient gap to an already consistent meal
+type HabitSuggestion struct {
+	LinkedMeal        string `json:"linked_meal"`
+	SuggestedFood     string `json:"suggested_food"`
+	NutrientAddressed string `json:"nutrient_addressed"`
+}
+
+// HabitStackingSuggestions suggests adding a food for each nutrient gap to the
+// meal the user logs most consistently. Gaps without a known food are skipped.
+func HabitStackingSuggestions(preferences FoodPreferenceModel, gaps []string) []HabitSuggestion {
+	meal := mostFrequent(preferences.MealDays)
+	if meal == "" {
+		return nil
+	}
+
+	var suggestions []HabitSuggestion
+	for _, gap := range gaps {
+		food, ok := habitFoods[gap]
+		if !ok {
+			continue
+		}
+		suggestions = append(suggestions, HabitSuggestion{
+			LinkedMeal:        meal,
+			SuggestedFood:     food,
+			NutrientAddressed: gap,
+		})
+	}
+	return suggestions
+}
+
+// nutrientGaps returns the minimum goals met on fewer than half of the days
+func nutrientGaps(records []DailyNutrition, goals map[string]float64) []string {
+	var gaps []string
+	if len(records) == 0 {
+		return gaps
+	}
+	for name, target := range goals {
+		if !minimumGoals[name] {
+			continue
+		}
+		met := 0
+		for _, d := range records {
+			if dayMeetsGoal(d, name, target) {
+				met++
+			}
+		}
+		if float64(met)/float64(len(records)) < gapAdherenceThreshold {
+			gaps = append(gaps, name)
+		}
+	}
+	sort.Strings(gaps)
+	return gaps
+}
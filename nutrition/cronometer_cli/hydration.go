@@ -0,0 +1,85 @@
+package main
+
+import (
+	"sort"
+	"strings"
+)
+
+// nonWaterFoods are foods whose names mention water but aren't drinks
+var nonWaterFoods = []string{"watermelon", "water chestnut", "watercress"}
+
+// HydrationEntry is one drink of water from the diary
+type HydrationEntry struct {
+	Date     string  `json:"date"`
+	AmountMl float64 `json:"amount_ml"`
+}
+
+// DailyHydration is a day's total water and whether it met the goal
+type DailyHydration struct {
+	AmountMl         float64 `json:"amount_ml"`
+	HydrationGoalMet bool    `json:"hydration_goal_met"`
+}
+
+// HydrationSummary summarizes water intake across the range
+type HydrationSummary struct {
+	GoalMl      float64 `json:"goal_ml"`
+	AverageMl   float64 `json:"average_ml"`
+	DaysGoalMet int     `json:"days_goal_met"`
+}
+
+// isWater reports whether a diary entry is plain water
+func isWater(e FoodEntry) bool {
+	name := foodKey(e.FoodName)
+	if !strings.Contains(name, "water") {
+		return false
+	}
+	return !containsAny(name, nonWaterFoods)
+}
+
+// ExtractHydrationEntries returns the diary's water entries in millilitres,
+// sorted by date. Ounces are read as fluid ounces and grams as millilitres;
+// entries in other units, such as "serving", are skipped.
+func ExtractHydrationEntries(entries []FoodEntry) []HydrationEntry {
+	var water []HydrationEntry
+	for _, e := range entries {
+		if !isWater(e) {
+			continue
+		}
+		unit := normalizeUnit(e.Unit)
+		if unit == "oz" {
+			unit = "fl oz"
+		}
+		conversion, ok := servingUnits[unit]
+		if !ok {
+			continue
+		}
+		water = append(water, HydrationEntry{Date: e.Date, AmountMl: e.Amount * conversion.factor})
+	}
+	sort.SliceStable(water, func(i, j int) bool {
+		return water[i].Date < water[j].Date
+	})
+	return water
+}
+
+// applyHydration totals each day's water against goalMl
+func applyHydration(records []DailyNutrition, diary []FoodEntry, goalMl float64) *HydrationSummary {
+	totals := make(map[string]float64)
+	for _, e := range ExtractHydrationEntries(diary) {
+		totals[e.Date] += e.AmountMl
+	}
+
+	summary := &HydrationSummary{GoalMl: goalMl}
+	for i := range records {
+		day := &DailyHydration{AmountMl: totals[records[i].Date]}
+		day.HydrationGoalMet = day.AmountMl >= goalMl
+		if day.HydrationGoalMet {
+			summary.DaysGoalMet++
+		}
+		summary.AverageMl += day.AmountMl
+		records[i].Hydration = day
+	}
+	if len(records) > 0 {
+		summary.AverageMl /= float64(len(records))
+	}
+	return summary
+}
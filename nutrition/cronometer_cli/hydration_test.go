@@ -0,0 +1,57 @@
+package main
+
+import (
+	"math"
+	"testing"
+)
+
+func TestExtractHydrationEntriesUnits(t *testing.T) {
+	tests := []struct {
+		name   string
+		entry  FoodEntry
+		wantMl float64
+		wantOK bool
+	}{
+		{"millilitres", FoodEntry{FoodName: "Water", Amount: 500, Unit: "ml"}, 500, true},
+		{"milliliter spelled out", FoodEntry{FoodName: "Water", Amount: 250, Unit: "milliliter"}, 250, true},
+		{"fluid ounces", FoodEntry{FoodName: "Water", Amount: 16, Unit: "fl oz"}, 16 * 29.573530, true},
+		{"bare ounces read as fluid", FoodEntry{FoodName: "Tap Water", Amount: 8, Unit: "oz"}, 8 * 29.573530, true},
+		{"grams as millilitres", FoodEntry{FoodName: "Water, Bottled", Amount: 330, Unit: "g"}, 330, true},
+		{"cups", FoodEntry{FoodName: "Water", Amount: 2, Unit: "cups"}, 480, true},
+		{"unknown unit skipped", FoodEntry{FoodName: "Water", Amount: 1, Unit: "serving"}, 0, false},
+		{"watermelon is not water", FoodEntry{FoodName: "Watermelon", Amount: 300, Unit: "g"}, 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.entry.Date = "2024-03-01"
+			water := ExtractHydrationEntries([]FoodEntry{tt.entry})
+			if (len(water) == 1) != tt.wantOK {
+				t.Fatalf("entries = %+v, want water %v", water, tt.wantOK)
+			}
+			if tt.wantOK && math.Abs(water[0].AmountMl-tt.wantMl) > 1e-6 {
+				t.Errorf("amount = %g ml, want %g", water[0].AmountMl, tt.wantMl)
+			}
+		})
+	}
+}
+
+func TestApplyHydrationMixedUnits(t *testing.T) {
+	records := []DailyNutrition{{Date: "2024-03-01"}, {Date: "2024-03-02"}}
+	diary := []FoodEntry{
+		{Date: "2024-03-01", FoodName: "Water", Amount: 1000, Unit: "ml"},
+		{Date: "2024-03-01", FoodName: "Water", Amount: 40, Unit: "fl oz"},
+		{Date: "2024-03-02", FoodName: "Water", Amount: 500, Unit: "ml"},
+	}
+	summary := applyHydration(records, diary, 2000)
+
+	wantDay1 := 1000 + 40*29.573530
+	if got := records[0].Hydration.AmountMl; math.Abs(got-wantDay1) > 1e-6 || !records[0].Hydration.HydrationGoalMet {
+		t.Errorf("day 1 = %+v, want %g ml meeting the goal", records[0].Hydration, wantDay1)
+	}
+	if records[1].Hydration.HydrationGoalMet {
+		t.Errorf("day 2 = %+v, want 500 ml short of the goal", records[1].Hydration)
+	}
+	if summary.DaysGoalMet != 1 || math.Abs(summary.AverageMl-(wantDay1+500)/2) > 1e-6 {
+		t.Errorf("summary = %+v, want 1 day met", summary)
+	}
+}
@@ -0,0 +1,104 @@
+package main
+
+import "math"
+
+// inflammatoryThreshold is the DII score beyond which a day counts as
+// anti- or pro-inflammatory rather than neutral
+const inflammatoryThreshold = 1.0
+
+// diiParameter is a Dietary Inflammatory Index parameter: the intake is
+// standardized against the global mean and standard deviation, then weighted
+// by the nutrient's inflammatory effect score (negative is anti-inflammatory)
+type diiParameter struct {
+	effect float64
+	mean   float64
+	sd     float64
+	intake func(d DailyNutrition) float64
+}
+
+// diiParameters are the DII parameters (Shivappa et al. 2014) for the
+// nutrients Cronometer exports
+var diiParameters = []diiParameter{
+	{0.180, 2056, 338, func(d DailyNutrition) float64 { return d.Calories }},
+	{0.097, 272.2, 40.0, func(d DailyNutrition) float64 { return d.Carbs }},
+	{0.021, 79.4, 13.9, func(d DailyNutrition) float64 { return d.Protein }},
+	{0.298, 71.4, 19.4, func(d DailyNutrition) float64 { return d.Fat }},
+	{0.373, 28.6, 8.0, func(d DailyNutrition) float64 { return d.SaturatedFat }},
+	{0.110, 279.4, 51.2, func(d DailyNutrition) float64 { return d.Cholesterol }},
+	{-0.663, 18.8, 4.9, func(d DailyNutrition) float64 { return d.Fiber }},
+	{-0.436, 1.06, 1.06, func(d DailyNutrition) float64 { return d.Omega3 }},
+	{-0.159, 10.8, 7.5, func(d DailyNutrition) float64 { return d.Omega6 }},
+	{-0.424, 118.2, 43.46, func(d DailyNutrition) float64 { return d.VitaminC }},
+	{-0.419, 8.73, 1.49, func(d DailyNutrition) float64 { return d.VitaminE }},
+	{-0.446, 6.26, 2.21, func(d DailyNutrition) float64 { return d.VitaminD / iuPerMcgVitaminD }},
+	{0.106, 5.15, 2.70, func(d DailyNutrition) float64 { return d.B12 }},
+	{-0.190, 273.0, 70.7, func(d DailyNutrition) float64 { return d.Folate }},
+	{-0.484, 310.1, 139.4, func(d DailyNutrition) float64 { return d.Magnesium }},
+	{-0.313, 9.84, 2.19, func(d DailyNutrition) float64 { return d.Zinc }},
+	{-0.191, 67.0, 25.1, func(d DailyNutrition) float64 { return d.Selenium }},
+	{0.032, 13.35, 3.71, func(d DailyNutrition) float64 { return d.Iron }},
+}
+
+// InflammatoryReport is a day's Dietary Inflammatory Index score and how many
+// of the DII parameters it was built from
+type InflammatoryReport struct {
+	Score          float64 `json:"score"`
+	Interpretation string  `json:"interpretation"`
+	ParametersUsed int     `json:"parameters_used"`
+}
+
+// DietaryInflammatoryIndex scores the day's inflammatory potential over the
+// given parameters. Each nutrient's z-score is converted to a centered
+// percentile from -1 to 1 and multiplied by its effect score; the sum is
+// positive for a pro-inflammatory diet and negative for an anti-inflammatory one.
+func DietaryInflammatoryIndex(d DailyNutrition, parameters []diiParameter) float64 {
+	var score float64
+	for _, p := range parameters {
+		z := (p.intake(d) - p.mean) / p.sd
+		percentile := 0.5 * (1 + math.Erf(z/math.Sqrt2))
+		score += (2*percentile - 1) * p.effect
+	}
+	return score
+}
+
+// InflammatoryInterpretation describes a DII score
+func InflammatoryInterpretation(score float64) string {
+	switch {
+	case score <= -inflammatoryThreshold:
+		return "anti-inflammatory"
+	case score >= inflammatoryThreshold:
+		return "pro-inflammatory"
+	default:
+		return "neutral"
+	}
+}
+
+// presentDIIParameters keeps the parameters logged on at least one day, since
+// a nutrient the export has no column for reads as zero every day and would
+// otherwise score as no intake at all
+func presentDIIParameters(records []DailyNutrition) []diiParameter {
+	var present []diiParameter
+	for _, p := range diiParameters {
+		for _, d := range records {
+			if p.intake(d) > 0 {
+				present = append(present, p)
+				break
+			}
+		}
+	}
+	return present
+}
+
+// applyInflammatoryIndex attaches a DII report to each day, scored over the
+// parameters present in the export
+func applyInflammatoryIndex(records []DailyNutrition) {
+	parameters := presentDIIParameters(records)
+	for i := range records {
+		score := DietaryInflammatoryIndex(records[i], parameters)
+		records[i].InflammatoryIndex = &InflammatoryReport{
+			Score:          score,
+			Interpretation: InflammatoryInterpretation(score),
+			ParametersUsed: len(parameters),
+		}
+	}
+}
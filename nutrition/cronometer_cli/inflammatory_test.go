@@ -0,0 +1,55 @@
+package main
+
+import (
+	"math"
+	"testing"
+)
+
+func TestInflammatoryIndexSkipsAbsentColumns(t *testing.T) {
+	// An export with only the macro and fiber columns, every intake at the DII global mean
+	records := []DailyNutrition{
+		{Date: "2024-03-01", Calories: 2056, Carbs: 272.2, Protein: 79.4, Fat: 71.4, Fiber: 18.8},
+		{Date: "2024-03-02", Calories: 2056, Carbs: 272.2, Protein: 79.4, Fat: 71.4, Fiber: 18.8},
+	}
+	applyInflammatoryIndex(records)
+
+	report := records[0].InflammatoryIndex
+	if report.ParametersUsed != 5 {
+		t.Errorf("parameters used = %d, want 5", report.ParametersUsed)
+	}
+	if math.Abs(report.Score) > 1e-9 || report.Interpretation != "neutral" {
+		t.Errorf("report = %+v, want a neutral 0 at the global means", report)
+	}
+	// Scoring the missing columns as zero intake would call this day pro-inflammatory
+	if all := DietaryInflammatoryIndex(records[0], diiParameters); all < inflammatoryThreshold {
+		t.Errorf("score over every parameter = %g, expected the zeros to skew it", all)
+	}
+}
+
+func TestDietaryInflammatoryIndexDirection(t *testing.T) {
+	parameters := diiParameters
+	tests := []struct {
+		name string
+		day  DailyNutrition
+		want string
+	}{
+		{"anti-inflammatory", DailyNutrition{
+			Calories: 1800, Carbs: 220, Protein: 90, Fat: 55, SaturatedFat: 12, Cholesterol: 150, Fiber: 45,
+			Omega3: 3, Omega6: 12, VitaminC: 250, VitaminE: 15, VitaminD: 800, B12: 4, Folate: 600,
+			Magnesium: 550, Zinc: 12, Selenium: 90, Iron: 14,
+		}, "anti-inflammatory"},
+		{"pro-inflammatory", DailyNutrition{
+			Calories: 3200, Carbs: 400, Protein: 90, Fat: 140, SaturatedFat: 55, Cholesterol: 500, Fiber: 8,
+			Omega3: 0.3, Omega6: 5, VitaminC: 20, VitaminE: 4, VitaminD: 40, B12: 8, Folate: 150,
+			Magnesium: 180, Zinc: 7, Selenium: 40, Iron: 18,
+		}, "pro-inflammatory"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			score := DietaryInflammatoryIndex(tt.day, parameters)
+			if got := InflammatoryInterpretation(score); got != tt.want {
+				t.Errorf("score %g is %s, want %s", score, got, tt.want)
+			}
+		})
+	}
+}
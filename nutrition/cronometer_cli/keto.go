@@ -0,0 +1,55 @@
+package main
+
+import "math"
+
+// ketoRatioThreshold is the lowest fat:(protein+carbs) weight ratio treated as
+// ketogenic. Clinical ketogenic diets use 2:1 to 4:1 (Kossoff et al., "Optimal
+// clinical management of children receiving dietary therapies for epilepsy",
+// Epilepsia Open 2018).
+const ketoRatioThreshold = 2.0
+
+// Net carb bounds for the blood ketone estimate, after Volek & Phinney, "The
+// Art and Science of Low Carbohydrate Living" (2011): nutritional ketosis of
+// 0.5-3.0 mmol/L is typical under about 50g of carbs a day, and blood
+// ketones stay near the 0.1 mmol/L fed baseline above that.
+const (
+	ketoCarbLimitG      = 50.0
+	baselineKetonesMmol = 0.1
+	minKetosisMmol      = 0.5
+	maxKetosisMmol      = 3.0
+)
+
+// KetoReport summarizes a day's macros for ketogenic dieting
+type KetoReport struct {
+	NetCarbs                  float64 `json:"net_carbs"`
+	KetoRatio                 float64 `json:"keto_ratio"`
+	IsInKetosis               bool    `json:"is_in_ketosis"`
+	EstimatedBloodKetonesMmol float64 `json:"estimated_blood_ketones_mmol"`
+}
+
+// KetoMetrics computes net carbs, the ketogenic ratio, and a rough blood ketone
+// estimate. The estimate interpolates linearly from 0.5 mmol/L at 50g net carbs
+// to 3.0 mmol/L at none, per the Volek & Phinney ranges above; it is no
+// substitute for a blood meter.
+func KetoMetrics(d DailyNutrition) KetoReport {
+	report := KetoReport{NetCarbs: math.Max(d.Carbs-d.Fiber, 0)}
+	if d.Protein+d.Carbs > 0 {
+		report.KetoRatio = d.Fat / (d.Protein + d.Carbs)
+	}
+	report.IsInKetosis = report.KetoRatio >= ketoRatioThreshold
+
+	report.EstimatedBloodKetonesMmol = baselineKetonesMmol
+	if report.NetCarbs < ketoCarbLimitG {
+		share := 1 - report.NetCarbs/ketoCarbLimitG
+		report.EstimatedBloodKetonesMmol = minKetosisMmol + share*(maxKetosisMmol-minKetosisMmol)
+	}
+	return report
+}
+
+// applyKetoMetrics attaches a keto report to each day
+func applyKetoMetrics(records []DailyNutrition) {
+	for i := range records {
+		report := KetoMetrics(records[i])
+		records[i].KetoReport = &report
+	}
+}
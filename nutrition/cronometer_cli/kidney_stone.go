@@ -0,0 +1,65 @@
+package main
+
+// Kidney stone risk factors: the protein acid load above which urinary
+// calcium rises (about 100g of protein), and the daily fluid intake
+// recommended to prevent stones
+const (
+	highProteinAcidLoadMEq = 50.0
+	stonePreventionFluidMl = 2500.0
+)
+
+// KidneyStoneReport combines a day's acid load and fluid intake into a kidney stone risk
+type KidneyStoneReport struct {
+	ProteinAcidLoad float64 `json:"protein_acid_load_meq"`
+	PRAL            float64 `json:"pral"`
+	FluidMl         float64 `json:"fluid_ml"`
+	KidneyStoneRisk string  `json:"kidney_stone_risk"`
+}
+
+// ProteinAcidLoad estimates the acid load in mEq/day from protein in g,
+// from the sulfate its sulfur amino acids yield (the PRAL protein coefficient)
+func ProteinAcidLoad(protein float64) float64 {
+	return 0.49 * protein
+}
+
+// KidneyStoneRisk counts three risk factors: a protein acid load above
+// 50 mEq, an acid-forming PRAL, and less than 2500ml of fluid. None is
+// "low", one "moderate", and two or more "high".
+func KidneyStoneRisk(proteinAcidLoad, pral, fluidMl float64) string {
+	factors := 0
+	if proteinAcidLoad > highProteinAcidLoadMEq {
+		factors++
+	}
+	if PRALClassification(pral) == "acid-forming" {
+		factors++
+	}
+	if fluidMl < stonePreventionFluidMl {
+		factors++
+	}
+	switch factors {
+	case 0:
+		return "low"
+	case 1:
+		return "moderate"
+	default:
+		return "high"
+	}
+}
+
+// applyKidneyStoneRisk attaches a kidney stone report to each day that has
+// both protein and water from -track-hydration
+func applyKidneyStoneRisk(records []DailyNutrition) {
+	for i := range records {
+		d := &records[i]
+		if d.Protein <= 0 || d.Hydration == nil {
+			continue
+		}
+		report := KidneyStoneReport{
+			ProteinAcidLoad: ProteinAcidLoad(d.Protein),
+			PRAL:            PRALScore(d.Protein, d.Phosphorus, d.Potassium, d.Magnesium, d.Calcium),
+			FluidMl:         d.Hydration.AmountMl,
+		}
+		report.KidneyStoneRisk = KidneyStoneRisk(report.ProteinAcidLoad, report.PRAL, report.FluidMl)
+		d.KidneyStone = &report
+	}
+}
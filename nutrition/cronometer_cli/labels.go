@@ -0,0 +1,50 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// dayLabels collects repeated -label-day date=label flags
+type dayLabels map[string]string
+
+// String implements flag.Value
+func (l dayLabels) String() string {
+	dates := make([]string, 0, len(l))
+	for date := range l {
+		dates = append(dates, date)
+	}
+	sort.Strings(dates)
+
+	pairs := make([]string, len(dates))
+	for i, date := range dates {
+		pairs[i] = date + "=" + l[date]
+	}
+	return strings.Join(pairs, ",")
+}
+
+// Set implements flag.Value, parsing a single date=label pair
+func (l dayLabels) Set(value string) error {
+	date, label, found := strings.Cut(value, "=")
+	date = strings.TrimSpace(date)
+	label = strings.TrimSpace(label)
+	if !found || label == "" {
+		return fmt.Errorf("expected date=label, got %q", value)
+	}
+	if _, err := time.Parse("2006-01-02", date); err != nil {
+		return fmt.Errorf("invalid date %q: expected YYYY-MM-DD", date)
+	}
+	l[date] = label
+	return nil
+}
+
+// applyLabels sets the Label field on records whose date has a label
+func applyLabels(records []DailyNutrition, labels dayLabels) {
+	for i := range records {
+		if label, ok := labels[records[i].Date]; ok {
+			records[i].Label = label
+		}
+	}
+}
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// LeanMassEntry is the lean body mass derived from a day's weight and body fat
+type LeanMassEntry struct {
+	Date       string  `json:"date"`
+	WeightKg   float64 `json:"weight_kg"`
+	BodyFatPct float64 `json:"body_fat_pct"`
+	LeanMassKg float64 `json:"lean_mass_kg"`
+}
+
+// filterMetric returns the biometric entries whose metric matches name
+func filterMetric(entries []BiometricEntry, name string) []BiometricEntry {
+	var matched []BiometricEntry
+	for _, e := range entries {
+		if strings.EqualFold(strings.TrimSpace(e.Metric), name) {
+			matched = append(matched, e)
+		}
+	}
+	return matched
+}
+
+// ComputeLeanMass computes weight × (1 - body fat%/100) for each date with
+// both a weight and a body fat measurement, sorted by date
+func ComputeLeanMass(weights, bodyFats []BiometricEntry) ([]LeanMassEntry, error) {
+	fatByDate := make(map[string]float64)
+	for _, e := range bodyFats {
+		if e.Amount > 0 && e.Amount < 100 {
+			fatByDate[e.Date] = e.Amount
+		}
+	}
+
+	var series []LeanMassEntry
+	for _, w := range weightSeries(weights) {
+		fat, ok := fatByDate[w.Date]
+		if !ok {
+			continue
+		}
+		series = append(series, LeanMassEntry{
+			Date:       w.Date,
+			WeightKg:   w.WeightKg,
+			BodyFatPct: fat,
+			LeanMassKg: w.WeightKg * (1 - fat/100),
+		})
+	}
+	if len(series) == 0 {
+		return nil, fmt.Errorf("no date has both a weight and a body fat measurement")
+	}
+	sort.Slice(series, func(i, j int) bool {
+		return series[i].Date < series[j].Date
+	})
+	return series, nil
+}
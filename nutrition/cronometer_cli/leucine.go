@@ -0,0 +1,64 @@
+package main
+
+import "sort"
+
+// leucineThresholdG is the leucine per meal needed to trigger muscle protein
+// synthesis (Norton & Layman, J Nutr 2006)
+const leucineThresholdG = 2.5
+
+// MealLeucineSummary is one logged meal's leucine
+type MealLeucineSummary struct {
+	Date           string  `json:"date"`
+	Meal           string  `json:"meal"`
+	LeucineG       float64 `json:"leucine_g"`
+	MeetsThreshold bool    `json:"meets_threshold"`
+}
+
+// LeucineReport lists each meal's leucine and the share of meals reaching the threshold
+type LeucineReport struct {
+	Meals           []MealLeucineSummary `json:"meals"`
+	ThresholdG      float64              `json:"threshold_g"`
+	FractionMeeting float64              `json:"fraction_meeting_threshold"`
+}
+
+// LeucinePerMeal totals leucine for each date and meal, sorted by date and meal
+func LeucinePerMeal(diary []FoodEntry) []MealLeucineSummary {
+	type mealKey struct{ date, meal string }
+	totals := make(map[mealKey]float64)
+	for _, e := range diary {
+		totals[mealKey{e.Date, e.Meal}] += e.Leucine
+	}
+
+	meals := make([]MealLeucineSummary, 0, len(totals))
+	for key, grams := range totals {
+		meals = append(meals, MealLeucineSummary{
+			Date:           key.date,
+			Meal:           key.meal,
+			LeucineG:       grams,
+			MeetsThreshold: grams >= leucineThresholdG,
+		})
+	}
+	sort.Slice(meals, func(i, j int) bool {
+		if meals[i].Date != meals[j].Date {
+			return meals[i].Date < meals[j].Date
+		}
+		return meals[i].Meal < meals[j].Meal
+	})
+	return meals
+}
+
+// leucineReport flags meals against the threshold across the date range
+func leucineReport(diary []FoodEntry) *LeucineReport {
+	report := &LeucineReport{Meals: LeucinePerMeal(diary), ThresholdG: leucineThresholdG}
+	if len(report.Meals) == 0 {
+		return report
+	}
+	meeting := 0
+	for _, meal := range report.Meals {
+		if meal.MeetsThreshold {
+			meeting++
+		}
+	}
+	report.FractionMeeting = float64(meeting) / float64(len(report.Meals))
+	return report
+}
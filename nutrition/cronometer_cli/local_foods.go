@@ -0,0 +1,194 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// LocalFood is a home-cooked or otherwise missing food with nutrients per serving
+type LocalFood struct {
+	Name           string             `json:"name"`
+	Serving        string             `json:"serving"`
+	Calories       float64            `json:"calories"`
+	Protein        float64            `json:"protein"`
+	Fat            float64            `json:"fat"`
+	Carbs          float64            `json:"carbs"`
+	Micronutrients map[string]float64 `json:"micronutrients,omitempty"`
+}
+
+// FoodDatabase is the local food database stored as JSON
+type FoodDatabase struct {
+	Foods []LocalFood `json:"foods"`
+}
+
+// configDir returns the CLI's configuration directory (~/.config/cronometer_cli)
+func configDir() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to find home directory: %v", err)
+	}
+	return filepath.Join(home, ".config", "cronometer_cli"), nil
+}
+
+// foodDatabasePath returns the location of foods.json
+func foodDatabasePath() (string, error) {
+	dir, err := configDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "foods.json"), nil
+}
+
+// loadFoodDatabase reads the database at path; a missing file is an empty database
+func loadFoodDatabase(path string) (FoodDatabase, error) {
+	var db FoodDatabase
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return db, nil
+	}
+	if err != nil {
+		return db, fmt.Errorf("failed to read %s: %v", path, err)
+	}
+	if err := json.Unmarshal(data, &db); err != nil {
+		return db, fmt.Errorf("failed to parse %s: %v", path, err)
+	}
+	return db, nil
+}
+
+// saveFoodDatabase writes the database to path, creating its directory if needed
+func saveFoodDatabase(path string, db FoodDatabase) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("failed to create %s: %v", filepath.Dir(path), err)
+	}
+	data, err := json.MarshalIndent(db, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode food database: %v", err)
+	}
+	return os.WriteFile(path, data, 0o644)
+}
+
+// AddLocalFood adds food to the database at path, replacing any food with the same name
+func AddLocalFood(path string, food LocalFood) error {
+	db, err := loadFoodDatabase(path)
+	if err != nil {
+		return err
+	}
+	for i, existing := range db.Foods {
+		if foodKey(existing.Name) == foodKey(food.Name) {
+			db.Foods[i] = food
+			return saveFoodDatabase(path, db)
+		}
+	}
+	db.Foods = append(db.Foods, food)
+	return saveFoodDatabase(path, db)
+}
+
+// LookupLocalFood finds a food by name, ignoring case
+func LookupLocalFood(db FoodDatabase, name string) (LocalFood, bool) {
+	for _, food := range db.Foods {
+		if foodKey(food.Name) == foodKey(name) {
+			return food, true
+		}
+	}
+	return LocalFood{}, false
+}
+
+// parseLocalFood parses the -add-local-food value, e.g.
+// "name=Chili,serving=1 bowl,calories=450,protein=30,fat=20,carbs=35".
+// Any other numeric key is stored as a micronutrient.
+func parseLocalFood(value string) (LocalFood, error) {
+	pairs, err := parseKeyValues(value)
+	if err != nil {
+		return LocalFood{}, err
+	}
+	food := LocalFood{Name: pairs["name"], Serving: pairs["serving"]}
+	if food.Name == "" {
+		return LocalFood{}, fmt.Errorf("name is required")
+	}
+
+	for key, raw := range pairs {
+		if key == "name" || key == "serving" {
+			continue
+		}
+		v, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			return LocalFood{}, fmt.Errorf("invalid number for %s: %q", key, raw)
+		}
+		switch key {
+		case "calories":
+			food.Calories = v
+		case "protein":
+			food.Protein = v
+		case "fat":
+			food.Fat = v
+		case "carbs":
+			food.Carbs = v
+		default:
+			if food.Micronutrients == nil {
+				food.Micronutrients = make(map[string]float64)
+			}
+			food.Micronutrients[key] = v
+		}
+	}
+	return food, nil
+}
+
+// servingScale returns how many local servings a diary entry represents.
+// When the entry uses the same unit as the local serving (e.g. "2 bowl" vs.
+// "1 bowl") the amounts are compared; otherwise the entry counts as one serving.
+func servingScale(entry FoodEntry, food LocalFood) float64 {
+	fields := strings.Fields(food.Serving)
+	if len(fields) < 2 || entry.Amount <= 0 {
+		return 1
+	}
+	amount, err := strconv.ParseFloat(fields[0], 64)
+	if err != nil || amount <= 0 {
+		return 1
+	}
+	if !strings.EqualFold(strings.Join(fields[1:], ""), entry.Unit) {
+		return 1
+	}
+	return entry.Amount / amount
+}
+
+// applyLocalFoods fills in macros for diary entries that have no nutrient
+// values but match a local food, adding them to that day's totals. It
+// returns how many entries were filled.
+func applyLocalFoods(records []DailyNutrition, diary []FoodEntry, db FoodDatabase) int {
+	byDate := make(map[string]*DailyNutrition, len(records))
+	for i := range records {
+		byDate[records[i].Date] = &records[i]
+	}
+
+	filled := 0
+	for i := range diary {
+		e := &diary[i]
+		if e.Calories != 0 || e.Protein != 0 || e.Fat != 0 || e.Carbs != 0 {
+			continue
+		}
+		food, ok := LookupLocalFood(db, e.FoodName)
+		if !ok {
+			continue
+		}
+		scale := servingScale(*e, food)
+		e.Calories = food.Calories * scale
+		e.Protein = food.Protein * scale
+		e.Fat = food.Fat * scale
+		e.Carbs = food.Carbs * scale
+		e.LocalFood = true
+		filled++
+
+		if d, ok := byDate[e.Date]; ok {
+			d.Calories += e.Calories
+			d.Protein += e.Protein
+			d.Fat += e.Fat
+			d.Carbs += e.Carbs
+		}
+	}
+	return filled
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"math"
+	"strings"
+)
+
+// Weights for each LoggingQuality component; they sum to 1
+const (
+	qualityWeightWordCount = 0.35
+	qualityWeightAmounts   = 0.25
+	qualityWeightBranded   = 0.20
+	qualityWeightCustom    = 0.20
+
+	// qualityTargetWords is the average name length that earns full word-count credit
+	qualityTargetWords = 5.0
+)
+
+// genericUnits are serving units that don't convey a measured amount
+var genericUnits = map[string]bool{
+	"":         true,
+	"serving":  true,
+	"servings": true,
+	"each":     true,
+	"item":     true,
+	"portion":  true,
+}
+
+// LoggingQuality estimates how specifically foods were logged, from 0 (vague)
+// to 100 (very specific). It blends the average food name word count, the
+// share of entries with a measured amount, the share of branded foods, and
+// the share of entries that are not custom foods.
+func LoggingQuality(entries []FoodEntry) float64 {
+	if len(entries) == 0 {
+		return 0
+	}
+
+	var words, measured, branded, custom float64
+	for _, e := range entries {
+		words += float64(len(strings.Fields(e.FoodName)))
+		if e.Amount > 0 && !genericUnits[strings.ToLower(strings.TrimSpace(e.Unit))] {
+			measured++
+		}
+		if isBrandedFood(e) {
+			branded++
+		}
+		if strings.Contains(strings.ToLower(e.Category), "custom") {
+			custom++
+		}
+	}
+
+	n := float64(len(entries))
+	wordScore := math.Min(words/n/qualityTargetWords, 1)
+	score := qualityWeightWordCount*wordScore +
+		qualityWeightAmounts*(measured/n) +
+		qualityWeightBranded*(branded/n) +
+		qualityWeightCustom*(1-custom/n)
+	return score * 100
+}
+
+// isBrandedFood reports whether an entry looks like a branded product
+func isBrandedFood(e FoodEntry) bool {
+	return strings.ContainsAny(e.FoodName, "®™") || strings.Contains(strings.ToLower(e.Category), "brand")
+}
+
+// applyLoggingQuality sets each day's logging quality score from its diary entries
+func applyLoggingQuality(records []DailyNutrition, diary []FoodEntry) {
+	byDate := groupEntriesByDate(diary)
+	for i := range records {
+		entries, ok := byDate[records[i].Date]
+		if !ok {
+			continue
+		}
+		score := LoggingQuality(entries)
+		records[i].LoggingQuality = &score
+	}
+}
@@ -0,0 +1,105 @@
+package main
+
+import (
+	"math"
+	"sort"
+	"strings"
+)
+
+// Blue Zone score targets: fiber and protein are full marks at or above the
+// target, and calories at any point in the moderate range
+const (
+	longevityFiberG        = 30.0
+	longevityProteinG      = 50.0
+	moderateCaloriesLow    = 1500.0
+	moderateCaloriesHigh   = 2300.0
+	longevityCalorieMargin = 700.0
+)
+
+// plantKeywords are lowercase name fragments for the legumes and vegetables
+// the Blue Zones diets are built on
+var plantKeywords = []string{
+	"bean", "lentil", "chickpea", "peas", "tofu", "tempeh", "edamame", "hummus",
+	"vegetable", "broccoli", "spinach", "kale", "cabbage", "carrot", "tomato",
+	"squash", "pepper", "onion", "greens", "lettuce", "sweet potato",
+}
+
+// processedKeywords are lowercase name fragments for processed foods
+var processedKeywords = []string{
+	"chips", "soda", "cola", "candy", "cookie", "cake", "donut", "doughnut",
+	"bacon", "sausage", "hot dog", "salami", "pepperoni", "frozen", "instant",
+	"fries", "nugget", "energy drink",
+}
+
+// MonthScore is a month's average longevity score
+type MonthScore struct {
+	Month        string  `json:"month"`
+	AverageScore float64 `json:"average_score"`
+}
+
+// containsAny reports whether name contains any of the keywords
+func containsAny(name string, keywords []string) bool {
+	name = strings.ToLower(name)
+	for _, keyword := range keywords {
+		if strings.Contains(name, keyword) {
+			return true
+		}
+	}
+	return false
+}
+
+// BlueZoneScore scores a day from 0 to 100 on four equally weighted Blue
+// Zones proxies: fiber, half from grams and half from the share of diary
+// entries that are legumes or vegetables; calories within a moderate range;
+// the share of entries that aren't processed or branded; and adequate protein.
+func BlueZoneScore(d DailyNutrition, diary []FoodEntry) float64 {
+	var plants, processed float64
+	for _, e := range diary {
+		category := strings.ToLower(e.Category)
+		if containsAny(e.FoodName, plantKeywords) || strings.Contains(category, "vegetable") || strings.Contains(category, "legume") {
+			plants++
+		}
+		if containsAny(e.FoodName, processedKeywords) || isBrandedFood(e) {
+			processed++
+		}
+	}
+	plantShare, unprocessedShare := 0.0, 1.0
+	if n := float64(len(diary)); n > 0 {
+		plantShare = plants / n
+		unprocessedShare = 1 - processed/n
+	}
+
+	fiber := 0.5*math.Min(d.Fiber/longevityFiberG, 1) + 0.5*plantShare
+
+	outside := math.Max(moderateCaloriesLow-d.Calories, d.Calories-moderateCaloriesHigh)
+	calories := 1 - math.Min(math.Max(outside, 0)/longevityCalorieMargin, 1)
+
+	protein := math.Min(d.Protein/longevityProteinG, 1)
+
+	return 25 * (fiber + calories + unprocessedShare + protein)
+}
+
+// applyLongevityScores scores each day and returns the average score per month
+func applyLongevityScores(records []DailyNutrition, diary []FoodEntry) []MonthScore {
+	byDate := groupEntriesByDate(diary)
+	totals := make(map[string]float64)
+	counts := make(map[string]int)
+	for i := range records {
+		score := BlueZoneScore(records[i], byDate[records[i].Date])
+		records[i].LongevityScore = &score
+		if len(records[i].Date) >= 7 {
+			month := records[i].Date[:7]
+			totals[month] += score
+			counts[month]++
+		}
+	}
+
+	trend := make([]MonthScore, 0, len(totals))
+	for month, total := range totals {
+		trend = append(trend, MonthScore{Month: month, AverageScore: total / float64(counts[month])})
+	}
+	sort.Slice(trend, func(i, j int) bool {
+		return trend[i].Month < trend[j].Month
+	})
+	return trend
+}
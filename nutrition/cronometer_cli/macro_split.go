@@ -0,0 +1,176 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+)
+
+// MacroSplit is a percentage of calories from carbs, protein, and fat
+type MacroSplit struct {
+	Carbs   float64 `json:"carbs"`
+	Protein float64 `json:"protein"`
+	Fat     float64 `json:"fat"`
+}
+
+// DaySplit is one day's actual macro split and its deviation from the target
+type DaySplit struct {
+	Date      string     `json:"date"`
+	Split     MacroSplit `json:"split"`
+	Deviation float64    `json:"deviation"`
+}
+
+// MacroSplitReport compares each day's macro split to the target.
+// MacroSplitDeviation is the mean of the daily deviations.
+type MacroSplitReport struct {
+	Target              MacroSplit `json:"target"`
+	Days                []DaySplit `json:"days"`
+	MacroSplitDeviation float64    `json:"macro_split_deviation"`
+	ClosestDayToTarget  string     `json:"closest_day_to_target,omitempty"`
+}
+
+// parseMacroSplit parses the -compare-macro-ratios value, e.g.
+// "carbs=40,protein=30,fat=30". The percentages must sum to 100.
+func parseMacroSplit(value string) (MacroSplit, error) {
+	pairs, err := parseKeyValues(value)
+	if err != nil {
+		return MacroSplit{}, err
+	}
+	var split MacroSplit
+	for key, raw := range pairs {
+		v, err := strconv.ParseFloat(raw, 64)
+		if err != nil || v < 0 {
+			return MacroSplit{}, fmt.Errorf("invalid percentage for %s: %q", key, raw)
+		}
+		switch key {
+		case "carbs":
+			split.Carbs = v
+		case "protein":
+			split.Protein = v
+		case "fat":
+			split.Fat = v
+		default:
+			return MacroSplit{}, fmt.Errorf("unknown macro %q", key)
+		}
+	}
+	if total := split.Carbs + split.Protein + split.Fat; math.Abs(total-100) > 0.5 {
+		return MacroSplit{}, fmt.Errorf("percentages must sum to 100, got %g", total)
+	}
+	return split, nil
+}
+
+// actualMacroSplit returns the share of a day's macro calories from each macro
+func actualMacroSplit(d DailyNutrition) MacroSplit {
+	total := atwaterCalories(d)
+	if total <= 0 {
+		return MacroSplit{}
+	}
+	return MacroSplit{
+		Carbs:   kcalPerGramCarbs * d.Carbs / total * 100,
+		Protein: kcalPerGramProtein * d.Protein / total * 100,
+		Fat:     kcalPerGramFat * d.Fat / total * 100,
+	}
+}
+
+// MacroSplitDeviation is the sum of squared differences, in percentage
+// points, between an actual and a target split
+func MacroSplitDeviation(actual, target MacroSplit) float64 {
+	dc := actual.Carbs - target.Carbs
+	dp := actual.Protein - target.Protein
+	df := actual.Fat - target.Fat
+	return dc*dc + dp*dp + df*df
+}
+
+// CompareMacroRatios computes each day's split and deviation from target,
+// sorted by date, with the mean deviation and the closest day
+func CompareMacroRatios(records []DailyNutrition, target MacroSplit) MacroSplitReport {
+	report := MacroSplitReport{Target: target, Days: []DaySplit{}}
+	closest := math.Inf(1)
+	for _, d := range records {
+		if atwaterCalories(d) <= 0 {
+			continue
+		}
+		split := actualMacroSplit(d)
+		day := DaySplit{Date: d.Date, Split: split, Deviation: MacroSplitDeviation(split, target)}
+		report.Days = append(report.Days, day)
+		report.MacroSplitDeviation += day.Deviation
+		if day.Deviation < closest || (day.Deviation == closest && day.Date < report.ClosestDayToTarget) {
+			closest = day.Deviation
+			report.ClosestDayToTarget = day.Date
+		}
+	}
+	if len(report.Days) > 0 {
+		report.MacroSplitDeviation /= float64(len(report.Days))
+	}
+	sort.Slice(report.Days, func(i, j int) bool {
+		return report.Days[i].Date < report.Days[j].Date
+	})
+	return report
+}
+
+// driRange is an Acceptable Macronutrient Distribution Range, in percent of calories
+type driRange struct{ low, high float64 }
+
+// Adult AMDRs from the Dietary Reference Intakes
+var (
+	carbDRI    = driRange{45, 65}
+	fatDRI     = driRange{20, 35}
+	proteinDRI = driRange{10, 35}
+)
+
+// contains reports whether pct is within the range, inclusive
+func (r driRange) contains(pct float64) bool {
+	return pct >= r.low && pct <= r.high
+}
+
+// MacroDistReport is a day's share of calories from each macro and whether
+// each falls within its DRI range. DRICompliant is true when all three do.
+type MacroDistReport struct {
+	CarbPct          float64 `json:"carb_pct"`
+	FatPct           float64 `json:"fat_pct"`
+	ProteinPct       float64 `json:"protein_pct"`
+	CarbCompliant    bool    `json:"carb_dri_compliant"`
+	FatCompliant     bool    `json:"fat_dri_compliant"`
+	ProteinCompliant bool    `json:"protein_dri_compliant"`
+	DRICompliant     bool    `json:"dri_compliant"`
+}
+
+// MacroDistributionAdherence compares a day's macro split to the DRI ranges:
+// carbs 45-65%, fat 20-35%, and protein 10-35% of calories
+func MacroDistributionAdherence(d DailyNutrition) MacroDistReport {
+	split := actualMacroSplit(d)
+	report := MacroDistReport{
+		CarbPct:          split.Carbs,
+		FatPct:           split.Fat,
+		ProteinPct:       split.Protein,
+		CarbCompliant:    carbDRI.contains(split.Carbs),
+		FatCompliant:     fatDRI.contains(split.Fat),
+		ProteinCompliant: proteinDRI.contains(split.Protein),
+	}
+	report.DRICompliant = report.CarbCompliant && report.FatCompliant && report.ProteinCompliant
+	return report
+}
+
+// applyMacroDistribution attaches a DRI report to each day with macro
+// calories and returns the fraction of those days within all three ranges
+func applyMacroDistribution(records []DailyNutrition) *float64 {
+	var days, compliant int
+	for i := range records {
+		d := &records[i]
+		if atwaterCalories(*d) <= 0 {
+			continue
+		}
+		report := MacroDistributionAdherence(*d)
+		d.MacroDistribution = &report
+		days++
+		if report.DRICompliant {
+			compliant++
+		}
+	}
+	if days == 0 {
+		return nil
+	}
+	fraction := float64(compliant) / float64(days)
+	return &fraction
+}
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"math"
+	"testing"
+)
+
+func TestMacroSplitDeviation(t *testing.T) {
+	target := MacroSplit{Carbs: 40, Protein: 30, Fat: 30}
+	tests := []struct {
+		name   string
+		actual MacroSplit
+		want   float64
+	}{
+		{"on target", MacroSplit{Carbs: 40, Protein: 30, Fat: 30}, 0},
+		// (50-40)² + (20-30)² + (30-30)²
+		{"carbs for protein", MacroSplit{Carbs: 50, Protein: 20, Fat: 30}, 200},
+		// (30-40)² + (25-30)² + (45-30)²
+		{"high fat", MacroSplit{Carbs: 30, Protein: 25, Fat: 45}, 350},
+		{"symmetric under and over", MacroSplit{Carbs: 30, Protein: 40, Fat: 30}, 200},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := MacroSplitDeviation(tt.actual, target); got != tt.want {
+				t.Errorf("MacroSplitDeviation = %g, want %g", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCompareMacroRatios(t *testing.T) {
+	target := MacroSplit{Carbs: 40, Protein: 30, Fat: 30}
+	records := []DailyNutrition{
+		// 800 kcal carbs, 600 protein, 600 fat: exactly on target
+		{Date: "2024-03-02", Carbs: 200, Protein: 150, Fat: 600.0 / 9},
+		// 1000 kcal carbs, 400 protein, 600 fat: 50/20/30
+		{Date: "2024-03-01", Carbs: 250, Protein: 100, Fat: 600.0 / 9},
+		{Date: "2024-03-03"},
+	}
+	report := CompareMacroRatios(records, target)
+	if len(report.Days) != 2 || report.Days[0].Date != "2024-03-01" {
+		t.Fatalf("days = %+v, want two days sorted, skipping the empty one", report.Days)
+	}
+	if math.Abs(report.Days[0].Deviation-200) > 1e-9 || math.Abs(report.Days[1].Deviation) > 1e-9 {
+		t.Errorf("deviations = %g, %g, want 200 and 0", report.Days[0].Deviation, report.Days[1].Deviation)
+	}
+	if math.Abs(report.MacroSplitDeviation-100) > 1e-9 || report.ClosestDayToTarget != "2024-03-02" {
+		t.Errorf("mean %g closest %s, want 100 and 2024-03-02", report.MacroSplitDeviation, report.ClosestDayToTarget)
+	}
+}
@@ -3,110 +3,173 @@ package main
 import (
 	"context"
 	"encoding/csv"
-	"encoding/json"
+	"errors"
 	"flag"
 	"fmt"
 	"os"
 	"strconv"
 	"strings"
 	"time"
-
-	"github.com/jrmycanady/gocronometer"
 )
 
 // DailyNutrition represents a single day's nutrition data
 type DailyNutrition struct {
-	Date     string  `json:"date"`
-	Calories float64 `json:"calories"`
-	Fat      float64 `json:"fat"`
-	Carbs    float64 `json:"carbs"`
-	Protein  float64 `json:"protein"`
-}
-
-func main() {
-	// Parse command line flags
-	username := flag.String("username", "", "Cronometer username")
-	password := flag.String("password", "", "Cronometer password")
-	startDate := flag.String("start", "", "Start date (YYYY-MM-DD)")
-	endDate := flag.String("end", "", "End date (YYYY-MM-DD)")
-	flag.Parse()
-
-	// Validate required arguments
-	if *username == "" || *password == "" {
-		fmt.Fprintln(os.Stderr, "Error: username and password are required")
-		flag.Usage()
-		os.Exit(1)
-	}
-
-	// Set default dates if not provided
-	var start, end time.Time
-	var err error
-
-	if *startDate == "" {
-		// Default to 30 days ago
-		start = time.Now().AddDate(0, 0, -30)
-	} else {
-		start, err = time.Parse("2006-01-02", *startDate)
-		if err != nil {
-			fmt.Fprintf(os.Stderr, "Error parsing start date: %v\n", err)
-			os.Exit(1)
-		}
-	}
-
-	if *endDate == "" {
-		// Default to today
-		end = time.Now()
-	} else {
-		end, err = time.Parse("2006-01-02", *endDate)
-		if err != nil {
-			fmt.Fprintf(os.Stderr, "Error parsing end date: %v\n", err)
-			os.Exit(1)
-		}
-	}
+	Date          string  `json:"date"`
+	Calories      float64 `json:"calories" unit:"kcal"`
+	Fat           float64 `json:"fat" unit:"g"`
+	Carbs         float64 `json:"carbs" unit:"g"`
+	Protein       float64 `json:"protein" unit:"g"`
+	Fiber         float64 `json:"fiber" unit:"g"`
+	Sodium        float64 `json:"sodium" unit:"mg"`
+	ALA           float64 `json:"ala" unit:"g"`
+	EPA           float64 `json:"epa" unit:"g"`
+	DHA           float64 `json:"dha" unit:"g"`
+	Omega3        float64 `json:"omega_3" unit:"g"`
+	Omega6        float64 `json:"omega_6" unit:"g"`
+	VitaminD      float64 `json:"vitamin_d" unit:"IU"`
+	Magnesium     float64 `json:"magnesium" unit:"mg"`
+	Calcium       float64 `json:"calcium" unit:"mg"`
+	B12           float64 `json:"b12" unit:"µg"`
+	Iron          float64 `json:"iron" unit:"mg"`
+	VitaminC      float64 `json:"vitamin_c" unit:"mg"`
+	VitaminE      float64 `json:"vitamin_e" unit:"mg"`
+	Selenium      float64 `json:"selenium" unit:"µg"`
+	Phosphorus    float64 `json:"phosphorus" unit:"mg"`
+	Leucine       float64 `json:"leucine" unit:"g"`
+	Folate        float64 `json:"folate" unit:"µg"`
+	FolicAcid     float64 `json:"folic_acid" unit:"µg"`
+	Glycine       float64 `json:"glycine" unit:"g"`
+	Arginine      float64 `json:"arginine" unit:"g"`
+	Methionine    float64 `json:"methionine" unit:"g"`
+	Zinc          float64 `json:"zinc" unit:"mg"`
+	Copper        float64 `json:"copper" unit:"mg"`
+	Potassium     float64 `json:"potassium" unit:"mg"`
+	Tryptophan    float64 `json:"tryptophan" unit:"g"`
+	Isoleucine    float64 `json:"isoleucine" unit:"g"`
+	Valine        float64 `json:"valine" unit:"g"`
+	Phenylalanine float64 `json:"phenylalanine" unit:"g"`
+	Tyrosine      float64 `json:"tyrosine" unit:"g"`
+	SaturatedFat  float64 `json:"saturated_fat" unit:"g"`
+	Cholesterol   float64 `json:"cholesterol" unit:"mg"`
+	SolubleFiber  float64 `json:"soluble_fiber" unit:"g"`
+	Lutein        float64 `json:"lutein" unit:"µg"`
+	Zeaxanthin    float64 `json:"zeaxanthin" unit:"µg"`
+	Manganese     float64 `json:"manganese" unit:"mg"`
+	Chromium      float64 `json:"chromium" unit:"µg"`
+	Molybdenum    float64 `json:"molybdenum" unit:"µg"`
+	Iodine        float64 `json:"iodine" unit:"µg"`
+	Choline       float64 `json:"choline" unit:"mg"`
+	VitaminK      float64 `json:"vitamin_k" unit:"µg"`
+	VitaminK1     float64 `json:"vitamin_k1" unit:"µg"`
+	VitaminK2     float64 `json:"vitamin_k2" unit:"µg"`
+	Ornithine     float64 `json:"ornithine" unit:"g"`
+	Histidine     float64 `json:"histidine" unit:"g"`
+	Lysine        float64 `json:"lysine" unit:"g"`
+	Threonine     float64 `json:"threonine" unit:"g"`
+	Cystine       float64 `json:"cystine" unit:"g"`
+	Biotin        float64 `json:"biotin" unit:"µg"`
+	// Cronometer's combined column, used when the separate ones are missing
+	LuteinZeaxanthin float64 `json:"lutein_zeaxanthin" unit:"µg"`
 
-	// Create context
-	ctx := context.Background()
-
-	// Create client and login to Cronometer
-	client := gocronometer.NewClient(nil)
-	err = client.Login(ctx, *username, *password)
-	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error logging in to Cronometer: %v\n", err)
-		os.Exit(1)
-	}
+	Label            string             `json:"label,omitempty"`
+	OriginalCalories *float64           `json:"original_calories,omitempty"`
+	OriginalValues   map[string]float64 `json:"original_values,omitempty"`
+	LoggingQuality   *float64           `json:"logging_quality,omitempty"`
+	LongevityScore   *float64           `json:"longevity_score,omitempty"`
+	Extrapolated     bool               `json:"extrapolated,omitempty"`
+	SodiumCategory   string             `json:"sodium_category,omitempty"`
+	MagnesiumStatus  string             `json:"magnesium_status,omitempty"`
+	B12Status        string             `json:"b12_status,omitempty"`
+	ManganeseStatus  string             `json:"manganese_status,omitempty"`
+	BiotinStatus     string             `json:"biotin_status,omitempty"`
+	RawEggWhite      bool               `json:"raw_egg_white,omitempty"`
+	// Calories logged after -cutoff-hour, set by -night-eating-warning
+	NightEatingCalories *float64 `json:"night_eating_calories,omitempty"`
+	NightEatingWarning  bool     `json:"night_eating_warning,omitempty"`
+	// Thermic effect of food, set by -compute-thermic-effect
+	ThermalEffectKcal   *float64 `json:"thermal_effect_kcal,omitempty"`
+	NetCaloriesAfterTEF *float64 `json:"net_calories_after_tef,omitempty"`
+	// Set by -compute-satiety
+	SatietyFactor  *float64 `json:"satiety_factor,omitempty"`
+	HungerRisk     string   `json:"hunger_risk,omitempty"`
+	OvereatingRisk bool     `json:"at_risk_of_overeating,omitempty"`
+	// Mean RDA adequacy, set by -micronutrient-score
+	MicronutrientScore *float64 `json:"micronutrient_score,omitempty"`
+	// Shannon index of calories across foods, set by -diversity-index
+	DiversityIndex *float64 `json:"diversity_index,omitempty"`
+	// Set by -check-iron-vitamin-c-pairing
+	IronAbsorptionAdvice string `json:"iron_absorption_advice,omitempty"`
+	// Net protein utilization in percent, set by -compute-npu
+	NPU               *float64 `json:"npu,omitempty"`
+	LimitingAminoAcid string   `json:"limiting_amino_acid,omitempty"`
+	// Set by -check-glycemic-load
+	GlycemicLoad         *float64 `json:"glycemic_load,omitempty"`
+	GlycemicLoadCategory string   `json:"glycemic_load_category,omitempty"`
+	// Respiratory exchange ratio, set by -compute-rer
+	RER               *float64 `json:"rer,omitempty"`
+	PrimaryFuelSource string   `json:"primary_fuel_source,omitempty"`
+	// Estimated Food Insulin Index, set by -compute-fii
+	FII *float64 `json:"fii,omitempty"`
+	// Caffeine in mg from the diary, set by -track-caffeine
+	Caffeine         *float64 `json:"caffeine,omitempty"`
+	CaffeineCategory string   `json:"caffeine_category,omitempty"`
+	// UK units (10g of alcohol) from the diary, set by -check-alcohol
+	AlcoholUnits *float64 `json:"alcohol_units,omitempty"`
 
-	// Export daily nutrition data
-	csvData, err := client.ExportDailyNutrition(ctx, start, end)
-	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error exporting nutrition data: %v\n", err)
-		os.Exit(1)
-	}
+	Omega3Report      *Omega3Report           `json:"omega_3_report,omitempty"`
+	VitaminDReport    *VitaminDReport         `json:"vitamin_d_report,omitempty"`
+	FolateReport      *FolateReport           `json:"folate_report,omitempty"`
+	VitaminKReport    *VitaminKReport         `json:"vitamin_k_report,omitempty"`
+	CholineReport     *CholineReport          `json:"choline_report,omitempty"`
+	TryptophanReport  *TryptophanReport       `json:"tryptophan_report,omitempty"`
+	KetoReport        *KetoReport             `json:"keto,omitempty"`
+	HighGI            *HighGIDay              `json:"high_gi,omitempty"`
+	CalciumAbsorption *CalciumAbsorption      `json:"calcium_absorption,omitempty"`
+	IronAbsorption    *IronAbsorptionEstimate `json:"iron_absorption,omitempty"`
+	BoneHealth        *BoneHealthReport       `json:"bone_health,omitempty"`
+	Cardiovascular    *CardiovascularReport   `json:"cardiovascular,omitempty"`
+	ZincCopper        *ZincCopperReport       `json:"zinc_copper,omitempty"`
+	Antioxidants      *AntioxidantReport      `json:"antioxidants,omitempty"`
+	InflammatoryIndex *InflammatoryReport     `json:"inflammatory_index,omitempty"`
+	ProteinQuality    *ProteinQualityReport   `json:"protein_quality,omitempty"`
+	PerKg             *PerKgNutrition         `json:"per_kg,omitempty"`
+	Rolling           *RollingAverage         `json:"rolling_average,omitempty"`
+	CumulativeProtein *float64                `json:"cumulative_protein,omitempty"`
+	GHSupport         *GHSupportReport        `json:"gh_support,omitempty"`
+	MicroTotals       map[string]float64      `json:"rolling_7_day_totals,omitempty"`
+	CreatineSynthesis *float64                `json:"creatine_synthesis_g,omitempty"`
+	DietaryCreatine   *DietaryCreatineReport  `json:"dietary_creatine,omitempty"`
+	Collagen          *CollagenReport         `json:"collagen,omitempty"`
+	PS                *PSReport               `json:"phosphatidylserine,omitempty"`
+	PC                *PCReport               `json:"phosphatidylcholine,omitempty"`
+	AGE               *AGEReport              `json:"advanced_glycation_end_products,omitempty"`
+	PRAL              *PRALReport             `json:"pral,omitempty"`
+	MacroDistribution *MacroDistReport        `json:"macro_distribution,omitempty"`
+	KidneyStone       *KidneyStoneReport      `json:"kidney_stone,omitempty"`
+	LipidSupport      *LipidSupportProfile    `json:"cholesterol_support,omitempty"`
+	EyeHealth         *EyeHealthFlag          `json:"eye_health,omitempty"`
+	TraceMinerals     map[string]TraceMineral `json:"trace_minerals,omitempty"`
+	Hydration         *DailyHydration         `json:"hydration,omitempty"`
+	Electrolytes      *ElectrolyteReport      `json:"electrolytes,omitempty"`
+	EatingWindow      *DailyEatingWindow      `json:"eating_window,omitempty"`
 
-	// Debug: print first few lines of CSV
-	lines := strings.Split(csvData, "\n")
-	fmt.Fprintf(os.Stderr, "DEBUG: CSV has %d lines\n", len(lines))
-	if len(lines) > 0 {
-		fmt.Fprintf(os.Stderr, "DEBUG: Header: %s\n", lines[0])
-	}
-	if len(lines) > 1 {
-		fmt.Fprintf(os.Stderr, "DEBUG: First row: %s\n", lines[1])
-	}
+	Goals map[string]GoalResult `json:"goals,omitempty"`
+}
 
-	// Parse CSV data
-	dailyNutrition, err := parseDailyNutrition(csvData)
+func main() {
+	pipeline, err := NewPipeline(parseFlags())
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error parsing nutrition data: %v\n", err)
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		if errors.Is(err, errMissingCredentials) {
+			flag.Usage()
+		}
 		os.Exit(1)
 	}
 
-	// Output as JSON
-	jsonData, err := json.MarshalIndent(dailyNutrition, "", "  ")
-	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error converting to JSON: %v\n", err)
+	if err := pipeline.Run(context.Background(), os.Stdout); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 		os.Exit(1)
 	}
-
-	fmt.Println(string(jsonData))
 }
 
 // parseDailyNutrition parses the CSV export into DailyNutrition structs
@@ -123,7 +186,7 @@ func parseDailyNutrition(csvData string) ([]DailyNutrition, error) {
 
 	// Find column indexes
 	header := records[0]
-	dateIdx := findColumn(header, "Date")  // Changed from "Day" to "Date"
+	dateIdx := findColumn(header, "Date") // Changed from "Day" to "Date"
 	caloriesIdx := findColumn(header, "Energy (kcal)")
 	fatIdx := findColumn(header, "Fat (g)")
 	carbsIdx := findColumn(header, "Carbs (g)")
@@ -133,6 +196,11 @@ func parseDailyNutrition(csvData string) ([]DailyNutrition, error) {
 		return nil, fmt.Errorf("missing required columns in CSV export")
 	}
 
+	optionalIdx := make([]int, len(optionalNutrients))
+	for i, n := range optionalNutrients {
+		optionalIdx[i] = findColumn(header, n.header)
+	}
+
 	// Parse each record
 	var results []DailyNutrition
 	for _, record := range records[1:] {
@@ -148,19 +216,66 @@ func parseDailyNutrition(csvData string) ([]DailyNutrition, error) {
 
 		// Only include days with actual data
 		if calories > 0 || fat > 0 || carbs > 0 || protein > 0 {
-			results = append(results, DailyNutrition{
+			day := DailyNutrition{
 				Date:     record[dateIdx],
 				Calories: calories,
 				Fat:      fat,
 				Carbs:    carbs,
 				Protein:  protein,
-			})
+			}
+			for i, n := range optionalNutrients {
+				if idx := optionalIdx[i]; idx != -1 && idx < len(record) {
+					*n.field(&day) = parseFloat(record[idx])
+				}
+			}
+			results = append(results, day)
 		}
 	}
 
 	return results, nil
 }
 
+// validateDateLayout checks that layout is a Go time layout that can represent a date
+func validateDateLayout(layout string) error {
+	reference := time.Date(2019, time.November, 23, 0, 0, 0, 0, time.UTC)
+	formatted := reference.Format(layout)
+	if formatted == layout {
+		return fmt.Errorf("%q contains no Go time layout elements (example: \"01/02/2006\")", layout)
+	}
+	parsed, err := time.Parse(layout, formatted)
+	if err != nil {
+		return fmt.Errorf("%q cannot be parsed back: %v", layout, err)
+	}
+	if parsed.Year() != reference.Year() || parsed.YearDay() != reference.YearDay() {
+		return fmt.Errorf("%q must include the year, month, and day", layout)
+	}
+	return nil
+}
+
+// formatDates rewrites each record's YYYY-MM-DD date using the output layout
+func formatDates(records []DailyNutrition, layout string) error {
+	for i := range records {
+		formatted, err := formatDate(records[i].Date, layout)
+		if err != nil {
+			return err
+		}
+		records[i].Date = formatted
+	}
+	return nil
+}
+
+// formatDate converts a single YYYY-MM-DD date to the output layout
+func formatDate(date, layout string) (string, error) {
+	if layout == "2006-01-02" {
+		return date, nil
+	}
+	parsed, err := time.Parse("2006-01-02", date)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse date %q: %v", date, err)
+	}
+	return parsed.Format(layout), nil
+}
+
 // findColumn finds the index of a column by name (case-insensitive)
 func findColumn(header []string, name string) int {
 	nameLower := strings.ToLower(name)
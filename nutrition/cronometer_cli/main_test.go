@@ -0,0 +1,23 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"testing"
+)
+
+// minCoverage is the statement coverage below which the unit test run warns
+const minCoverage = 0.70
+
+// TestMain runs the tests and, when coverage is measured (go test -cover),
+// warns if it falls below minCoverage. Integration tests, which need the
+// integration build tag, are left out of the default run and its coverage.
+func TestMain(m *testing.M) {
+	code := m.Run()
+	if testing.CoverMode() != "" {
+		if coverage := testing.Coverage(); coverage < minCoverage {
+			fmt.Fprintf(os.Stderr, "warning: coverage %.1f%% is below the %.0f%% target\n", coverage*100, minCoverage*100)
+		}
+	}
+	os.Exit(code)
+}
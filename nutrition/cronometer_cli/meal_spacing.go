@@ -0,0 +1,88 @@
+package main
+
+import (
+	"sort"
+	"time"
+)
+
+// mealClusterGap is how close diary entries must be to count as the same meal
+const mealClusterGap = 30 * time.Minute
+
+// MealSpacingDay is a day with at least one gap between meals outside the range
+type MealSpacingDay struct {
+	Date      string    `json:"date"`
+	Meals     int       `json:"meals"`
+	GapsHours []float64 `json:"gaps_hours"`
+	ShortGaps int       `json:"short_gaps"`
+	LongGaps  int       `json:"long_gaps"`
+}
+
+// MealSpacingReport lists the days with poorly spaced meals. Score is the
+// percentage of all gaps within the range; days with one meal have no gaps.
+type MealSpacingReport struct {
+	Days        []MealSpacingDay `json:"days"`
+	GapsChecked int              `json:"gaps_checked"`
+	Score       float64          `json:"meal_spacing_score"`
+}
+
+// InterMealGaps returns the time from each meal's first entry to the next
+// meal's, for one day's entries. Entries within 30 minutes of the previous
+// one are the same meal, and entries logged without a time are skipped.
+func InterMealGaps(entries []FoodEntry) []time.Duration {
+	var times []time.Time
+	for _, e := range entries {
+		if hasClockTime(e.Time) {
+			times = append(times, e.Time)
+		}
+	}
+	sort.Slice(times, func(i, j int) bool {
+		return times[i].Before(times[j])
+	})
+	if len(times) == 0 {
+		return nil
+	}
+	mealStart := times[0]
+
+	var gaps []time.Duration
+	for i := 1; i < len(times); i++ {
+		if times[i].Sub(times[i-1]) <= mealClusterGap {
+			continue
+		}
+		gaps = append(gaps, times[i].Sub(mealStart))
+		mealStart = times[i]
+	}
+	return gaps
+}
+
+// CheckMealSpacing reports the days with a gap shorter than minGap or longer
+// than maxGap, sorted by date, and scores the spacing across all days
+func CheckMealSpacing(diary []FoodEntry, minGap, maxGap time.Duration) MealSpacingReport {
+	report := MealSpacingReport{Days: []MealSpacingDay{}}
+	within := 0
+	for date, entries := range groupEntriesByDate(diary) {
+		gaps := InterMealGaps(entries)
+		day := MealSpacingDay{Date: date, Meals: len(gaps) + 1, GapsHours: []float64{}}
+		for _, gap := range gaps {
+			day.GapsHours = append(day.GapsHours, gap.Hours())
+			switch {
+			case gap < minGap:
+				day.ShortGaps++
+			case gap > maxGap:
+				day.LongGaps++
+			default:
+				within++
+			}
+		}
+		report.GapsChecked += len(gaps)
+		if day.ShortGaps > 0 || day.LongGaps > 0 {
+			report.Days = append(report.Days, day)
+		}
+	}
+	sort.Slice(report.Days, func(i, j int) bool {
+		return report.Days[i].Date < report.Days[j].Date
+	})
+	if report.GapsChecked > 0 {
+		report.Score = float64(within) / float64(report.GapsChecked) * 100
+	}
+	return report
+}
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func at(t *testing.T, value string) time.Time {
+	t.Helper()
+	parsed, err := time.Parse("2006-01-02 15:04", value)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return parsed
+}
+
+func TestInterMealGaps(t *testing.T) {
+	tests := []struct {
+		name  string
+		times []string
+		want  []time.Duration
+	}{
+		{"single entry", []string{"2024-03-01 08:00"}, nil},
+		{"single meal of several entries", []string{"2024-03-01 08:00", "2024-03-01 08:10", "2024-03-01 08:35"}, nil},
+		{"three meals", []string{"2024-03-01 08:00", "2024-03-01 12:00", "2024-03-01 18:30"}, []time.Duration{4 * time.Hour, 6*time.Hour + 30*time.Minute}},
+		{"gap measured from meal start", []string{"2024-03-01 08:00", "2024-03-01 08:20", "2024-03-01 11:00"}, []time.Duration{3 * time.Hour}},
+		{"untimed entries skipped", []string{"2024-03-01 00:00", "2024-03-01 09:00"}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var entries []FoodEntry
+			for _, value := range tt.times {
+				entries = append(entries, FoodEntry{Date: "2024-03-01", Time: at(t, value)})
+			}
+			got := InterMealGaps(entries)
+			if len(got) != len(tt.want) {
+				t.Fatalf("InterMealGaps = %v, want %v", got, tt.want)
+			}
+			for i := range tt.want {
+				if got[i] != tt.want[i] {
+					t.Errorf("gap %d = %v, want %v", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestCheckMealSpacingSingleMealDay(t *testing.T) {
+	diary := []FoodEntry{
+		{Date: "2024-03-01", Time: at(t, "2024-03-01 12:00")},
+		{Date: "2024-03-01", Time: at(t, "2024-03-01 12:15")},
+	}
+	report := CheckMealSpacing(diary, 3*time.Hour, 6*time.Hour)
+	if len(report.Days) != 0 || report.GapsChecked != 0 {
+		t.Errorf("report = %+v, want a one-meal day to have no gaps to flag", report)
+	}
+	if report.Score != 0 {
+		t.Errorf("score = %g, want 0 with no gaps checked", report.Score)
+	}
+}
+
+func TestCheckMealSpacingFlagsShortAndLongGaps(t *testing.T) {
+	diary := []FoodEntry{
+		{Date: "2024-03-01", Time: at(t, "2024-03-01 08:00")},
+		{Date: "2024-03-01", Time: at(t, "2024-03-01 09:30")},
+		{Date: "2024-03-01", Time: at(t, "2024-03-01 19:30")},
+		{Date: "2024-03-02", Time: at(t, "2024-03-02 08:00")},
+		{Date: "2024-03-02", Time: at(t, "2024-03-02 12:00")},
+	}
+	report := CheckMealSpacing(diary, 2*time.Hour, 6*time.Hour)
+	if len(report.Days) != 1 || report.Days[0].Date != "2024-03-01" {
+		t.Fatalf("days = %+v, want only 2024-03-01 flagged", report.Days)
+	}
+	day := report.Days[0]
+	if day.Meals != 3 || day.ShortGaps != 1 || day.LongGaps != 1 {
+		t.Errorf("day = %+v, want 3 meals with one short and one long gap", day)
+	}
+	if report.GapsChecked != 3 || report.Score < 33.3 || report.Score > 33.4 {
+		t.Errorf("checked %d gaps scoring %g, want 3 gaps and 1/3 within range", report.GapsChecked, report.Score)
+	}
+}
@@ -0,0 +1,110 @@
+package main
+
+import (
+	"math/rand"
+	"sort"
+	"strings"
+)
+
+// MealTemplate is a combination of foods logged together as one meal, with
+// its average macros
+type MealTemplate struct {
+	Meal        string   `json:"meal"`
+	Foods       []string `json:"foods"`
+	Occurrences int      `json:"occurrences"`
+	Calories    float64  `json:"calories"`
+	Fat         float64  `json:"fat"`
+	Carbs       float64  `json:"carbs"`
+	Protein     float64  `json:"protein"`
+}
+
+// mealTemplates groups the diary into meals by date and meal group, and
+// counts how often each meal's exact set of foods was logged. When any
+// template repeats, only repeated templates are returned.
+func mealTemplates(entries []FoodEntry) []MealTemplate {
+	type meal struct {
+		name    string
+		foods   map[string]string
+		entries []FoodEntry
+	}
+	meals := make(map[string]*meal)
+	for _, e := range entries {
+		key := e.Date + "|" + e.Meal
+		if meals[key] == nil {
+			meals[key] = &meal{name: e.Meal, foods: make(map[string]string)}
+		}
+		meals[key].foods[foodKey(e.FoodName)] = e.FoodName
+		meals[key].entries = append(meals[key].entries, e)
+	}
+
+	byFoods := make(map[string]*MealTemplate)
+	for _, m := range meals {
+		keys := make([]string, 0, len(m.foods))
+		for key := range m.foods {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		id := m.name + "|" + strings.Join(keys, "|")
+
+		template, ok := byFoods[id]
+		if !ok {
+			template = &MealTemplate{Meal: m.name}
+			for _, key := range keys {
+				template.Foods = append(template.Foods, m.foods[key])
+			}
+			byFoods[id] = template
+		}
+		template.Occurrences++
+		for _, e := range m.entries {
+			template.Calories += e.Calories
+			template.Fat += e.Fat
+			template.Carbs += e.Carbs
+			template.Protein += e.Protein
+		}
+	}
+
+	var templates, repeated []MealTemplate
+	for _, t := range byFoods {
+		n := float64(t.Occurrences)
+		t.Calories, t.Fat, t.Carbs, t.Protein = t.Calories/n, t.Fat/n, t.Carbs/n, t.Protein/n
+		templates = append(templates, *t)
+		if t.Occurrences > 1 {
+			repeated = append(repeated, *t)
+		}
+	}
+	if len(repeated) > 0 {
+		templates = repeated
+	}
+	// Map order is random, so sort to keep a seed's pick stable
+	sort.Slice(templates, func(i, j int) bool {
+		if templates[i].Occurrences != templates[j].Occurrences {
+			return templates[i].Occurrences > templates[j].Occurrences
+		}
+		if templates[i].Meal != templates[j].Meal {
+			return templates[i].Meal < templates[j].Meal
+		}
+		return strings.Join(templates[i].Foods, "|") < strings.Join(templates[j].Foods, "|")
+	})
+	return templates
+}
+
+// SelectRandomMealTemplate picks a template with probability proportional to
+// its occurrences. The same seed and templates always give the same pick.
+func SelectRandomMealTemplate(templates []MealTemplate, seed int64) MealTemplate {
+	total := 0
+	for _, t := range templates {
+		total += t.Occurrences
+	}
+	if total == 0 {
+		return MealTemplate{}
+	}
+
+	spin := rand.New(rand.NewSource(seed)).Intn(total)
+	for _, t := range templates {
+		if spin < t.Occurrences {
+			return t
+		}
+		spin -= t.Occurrences
+	}
+	return templates[len(templates)-1]
+}
@@ -0,0 +1,47 @@
+package main
+
+import (
+	"math"
+	"testing"
+)
+
+func TestSelectRandomMealTemplateDistribution(t *testing.T) {
+	templates := []MealTemplate{
+		{Meal: "Breakfast", Foods: []string{"oatmeal"}, Occurrences: 6},
+		{Meal: "Breakfast", Foods: []string{"eggs", "toast"}, Occurrences: 3},
+		{Meal: "Breakfast", Foods: []string{"yogurt"}, Occurrences: 1},
+	}
+	const draws = 20000
+	const tolerance = 0.015
+
+	counts := make(map[string]int)
+	for seed := int64(0); seed < draws; seed++ {
+		counts[SelectRandomMealTemplate(templates, seed).Foods[0]]++
+	}
+	for _, tmpl := range templates {
+		want := float64(tmpl.Occurrences) / 10
+		got := float64(counts[tmpl.Foods[0]]) / draws
+		if math.Abs(got-want) > tolerance {
+			t.Errorf("%s picked %.3f of the time, want %.2f ± %.3f", tmpl.Foods[0], got, want, tolerance)
+		}
+	}
+}
+
+func TestSelectRandomMealTemplateSeedIsStable(t *testing.T) {
+	templates := []MealTemplate{
+		{Meal: "Lunch", Foods: []string{"salad"}, Occurrences: 2},
+		{Meal: "Lunch", Foods: []string{"soup"}, Occurrences: 2},
+	}
+	first := SelectRandomMealTemplate(templates, 42)
+	for i := 0; i < 5; i++ {
+		if got := SelectRandomMealTemplate(templates, 42); got.Foods[0] != first.Foods[0] {
+			t.Fatalf("seed 42 picked %v then %v", first.Foods, got.Foods)
+		}
+	}
+}
+
+func TestSelectRandomMealTemplateNoOccurrences(t *testing.T) {
+	if got := SelectRandomMealTemplate(nil, 1); got.Meal != "" || got.Foods != nil {
+		t.Errorf("SelectRandomMealTemplate(nil) = %+v, want the zero template", got)
+	}
+}
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"fmt"
+	"math"
+)
+
+// Diary totals within this share (or within 1 unit) of the daily aggregate
+// are treated as rounding
+const mergeTolerance = 0.05
+
+// MergedDay joins a day's aggregate nutrition with its diary entries
+type MergedDay struct {
+	Date           string         `json:"date"`
+	DailyNutrition DailyNutrition `json:"nutrition"`
+	DiaryEntries   []FoodEntry    `json:"diary_entries"`
+}
+
+// MergeDiaryAndNutrition pairs each day with its diary entries by date
+func MergeDiaryAndNutrition(records []DailyNutrition, diary []FoodEntry) []MergedDay {
+	byDate := groupEntriesByDate(diary)
+	merged := make([]MergedDay, len(records))
+	for i, d := range records {
+		entries := byDate[d.Date]
+		if entries == nil {
+			entries = []FoodEntry{}
+		}
+		merged[i] = MergedDay{Date: d.Date, DailyNutrition: d, DiaryEntries: entries}
+	}
+	return merged
+}
+
+// mergeDiscrepancies describes each macro whose diary total differs from the
+// day's aggregate by more than rounding
+func mergeDiscrepancies(day MergedDay) []string {
+	var sums DailyNutrition
+	for _, e := range day.DiaryEntries {
+		sums.Calories += e.Calories
+		sums.Fat += e.Fat
+		sums.Carbs += e.Carbs
+		sums.Protein += e.Protein
+	}
+
+	var warnings []string
+	for _, m := range []struct {
+		name             string
+		diary, nutrition float64
+	}{
+		{"calories", sums.Calories, day.DailyNutrition.Calories},
+		{"fat", sums.Fat, day.DailyNutrition.Fat},
+		{"carbs", sums.Carbs, day.DailyNutrition.Carbs},
+		{"protein", sums.Protein, day.DailyNutrition.Protein},
+	} {
+		diff := math.Abs(m.diary - m.nutrition)
+		if diff > 1 && diff > mergeTolerance*m.nutrition {
+			warnings = append(warnings, fmt.Sprintf("%s: diary %s total %.1f differs from daily %.1f", day.Date, m.name, m.diary, m.nutrition))
+		}
+	}
+	return warnings
+}
+
+// formatMergedDates rewrites the merged days' YYYY-MM-DD dates using the output layout
+func formatMergedDates(merged []MergedDay, layout string) error {
+	for i := range merged {
+		formatted, err := formatDate(merged[i].Date, layout)
+		if err != nil {
+			return err
+		}
+		merged[i].Date = formatted
+		merged[i].DailyNutrition.Date = formatted
+		for j := range merged[i].DiaryEntries {
+			merged[i].DiaryEntries[j].Date = formatted
+		}
+	}
+	return nil
+}
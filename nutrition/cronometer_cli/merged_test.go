@@ -0,0 +1,47 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestMergeDiscrepancies(t *testing.T) {
+	lunch := FoodEntry{Date: "2024-03-01", Calories: 600, Fat: 20, Carbs: 70, Protein: 35}
+	dinner := FoodEntry{Date: "2024-03-01", Calories: 1400, Fat: 50, Carbs: 150, Protein: 90}
+	coffee := FoodEntry{Date: "2024-03-01", Calories: 5, Fat: 0.2, Carbs: 0.4, Protein: 0.3}
+	tests := []struct {
+		name      string
+		diary     []FoodEntry
+		nutrition DailyNutrition
+		want      []string
+	}{
+		{"exact match", []FoodEntry{lunch, dinner}, DailyNutrition{Calories: 2000, Fat: 70, Carbs: 220, Protein: 125}, nil},
+		{"rounding within 5%", []FoodEntry{lunch, dinner}, DailyNutrition{Calories: 2080, Fat: 71, Carbs: 215, Protein: 125.8}, nil},
+		{"within one unit of a small total", []FoodEntry{coffee}, DailyNutrition{Calories: 5.9, Fat: 0.9, Carbs: 1.2, Protein: 1}, nil},
+		{"calories far off", []FoodEntry{lunch, dinner}, DailyNutrition{Calories: 2600, Fat: 70, Carbs: 220, Protein: 125}, []string{"calories"}},
+		{"several macros off", []FoodEntry{lunch, dinner}, DailyNutrition{Calories: 2000, Fat: 40, Carbs: 300, Protein: 125}, []string{"fat", "carbs"}},
+		{"no diary", nil, DailyNutrition{Calories: 1500}, []string{"calories"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.nutrition.Date = "2024-03-01"
+			merged := MergeDiaryAndNutrition([]DailyNutrition{tt.nutrition}, tt.diary)
+			warnings := mergeDiscrepancies(merged[0])
+			if len(warnings) != len(tt.want) {
+				t.Fatalf("warnings = %q, want %d for %v", warnings, len(tt.want), tt.want)
+			}
+			for i, macro := range tt.want {
+				if !strings.Contains(warnings[i], "diary "+macro+" total") {
+					t.Errorf("warning %d = %q, want one for %s", i, warnings[i], macro)
+				}
+			}
+		})
+	}
+}
+
+func TestMergeDiaryAndNutritionDayWithoutDiary(t *testing.T) {
+	merged := MergeDiaryAndNutrition([]DailyNutrition{{Date: "2024-03-01", Calories: 1500}}, nil)
+	if len(merged) != 1 || merged[0].DiaryEntries == nil || len(merged[0].DiaryEntries) != 0 {
+		t.Errorf("merged = %+v, want one day with an empty, non-nil diary", merged)
+	}
+}
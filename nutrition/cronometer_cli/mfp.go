@@ -0,0 +1,156 @@
+package main
+
+import (
+	"encoding/csv"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+)
+
+// mfpDateLayouts are the date formats seen in MyFitnessPal exports
+var mfpDateLayouts = []string{"2006-01-02", "01/02/2006", "1/2/2006"}
+
+// mfpImport holds the -import-mfp settings
+type mfpImport struct {
+	File       string
+	WeightUnit string
+}
+
+// parseMFPImport parses an -import-mfp value such as "file=measurements.csv,weight-unit=kg".
+// Weight defaults to pounds, MyFitnessPal's US default.
+func parseMFPImport(value string) (mfpImport, error) {
+	pairs, err := parseKeyValues(value)
+	if err != nil {
+		return mfpImport{}, err
+	}
+	options := mfpImport{WeightUnit: "lbs"}
+	for key, val := range pairs {
+		switch key {
+		case "file":
+			options.File = val
+		case "weight-unit":
+			if val != "lbs" && val != "kg" {
+				return mfpImport{}, fmt.Errorf("expected weight-unit=lbs or kg, got %q", val)
+			}
+			options.WeightUnit = val
+		default:
+			return mfpImport{}, fmt.Errorf("unknown setting %q", key)
+		}
+	}
+	if options.File == "" {
+		return mfpImport{}, fmt.Errorf("file is required")
+	}
+	return options, nil
+}
+
+// mfpMetric splits an MFP column name such as "Body Fat %" or "Waist (in)"
+// into Cronometer's metric name and unit
+func mfpMetric(column, weightUnit string) (metric, unit string) {
+	column = strings.TrimSpace(column)
+	if open := strings.LastIndex(column, "("); open > 0 && strings.HasSuffix(column, ")") {
+		return strings.TrimSpace(column[:open]), column[open+1 : len(column)-1]
+	}
+	if strings.HasSuffix(column, "%") {
+		return strings.TrimSpace(strings.TrimSuffix(column, "%")), "%"
+	}
+	if strings.EqualFold(column, "weight") {
+		return "Weight", weightUnit
+	}
+	return column, ""
+}
+
+// ParseMyFitnessPalMeasurements parses a MyFitnessPal measurement export with
+// a Date column and one column per measurement. Empty cells are skipped.
+// Weight without a unit in its header is taken to be in pounds.
+func ParseMyFitnessPalMeasurements(csvData string) ([]BiometricEntry, error) {
+	return parseMFPMeasurements(csvData, "lbs")
+}
+
+// parseMFPMeasurements parses an MFP measurement export with the given unit for unlabeled weight
+func parseMFPMeasurements(csvData, weightUnit string) ([]BiometricEntry, error) {
+	records, err := csv.NewReader(strings.NewReader(csvData)).ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse CSV: %v", err)
+	}
+	if len(records) < 2 {
+		return nil, nil
+	}
+
+	header := records[0]
+	dateIdx := findColumn(header, "Date")
+	if dateIdx == -1 {
+		return nil, fmt.Errorf("missing Date column")
+	}
+
+	var entries []BiometricEntry
+	for _, record := range records[1:] {
+		if len(record) <= dateIdx {
+			continue
+		}
+		date, err := parseMFPDate(record[dateIdx])
+		if err != nil {
+			return nil, err
+		}
+		for i, column := range header {
+			if i == dateIdx || i >= len(record) || strings.TrimSpace(record[i]) == "" {
+				continue
+			}
+			metric, unit := mfpMetric(column, weightUnit)
+			entries = append(entries, BiometricEntry{
+				Date:   date,
+				Metric: metric,
+				Unit:   unit,
+				Amount: parseFloat(record[i]),
+			})
+		}
+	}
+	return entries, nil
+}
+
+// parseMFPDate converts an MFP date to YYYY-MM-DD
+func parseMFPDate(value string) (string, error) {
+	value = strings.TrimSpace(value)
+	for _, layout := range mfpDateLayouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t.Format("2006-01-02"), nil
+		}
+	}
+	return "", fmt.Errorf("unrecognized date %q", value)
+}
+
+// loadMFPBiometrics reads an MFP measurement export and keeps the entries within [start, end]
+func loadMFPBiometrics(options mfpImport, start, end time.Time) ([]BiometricEntry, error) {
+	data, err := os.ReadFile(options.File)
+	if err != nil {
+		return nil, err
+	}
+	entries, err := parseMFPMeasurements(string(data), options.WeightUnit)
+	if err != nil {
+		return nil, err
+	}
+
+	first, last := start.Format("2006-01-02"), end.Format("2006-01-02")
+	var kept []BiometricEntry
+	for _, e := range entries {
+		if e.Date >= first && e.Date <= last {
+			kept = append(kept, e)
+		}
+	}
+	return kept, nil
+}
+
+// mergeBiometrics adds imported entries whose date and metric Cronometer did not already record
+func mergeBiometrics(cronometer, imported []BiometricEntry) []BiometricEntry {
+	seen := make(map[string]bool, len(cronometer))
+	for _, e := range cronometer {
+		seen[e.Date+"|"+strings.ToLower(e.Metric)] = true
+	}
+	merged := append([]BiometricEntry{}, cronometer...)
+	for _, e := range imported {
+		if !seen[e.Date+"|"+strings.ToLower(e.Metric)] {
+			merged = append(merged, e)
+		}
+	}
+	return merged
+}
@@ -0,0 +1,128 @@
+package main
+
+import (
+	"math"
+	"sort"
+	"time"
+)
+
+// micronutrientTrendDays is the length of each period in the micronutrient trend
+const micronutrientTrendDays = 30
+
+// micronutrientTrendThreshold is how much a period's average adequacy must
+// change from the period before to count as a trend
+const micronutrientTrendThreshold = 0.05
+
+// micronutrientRDAs are adult RDAs, or AIs where there is no RDA, in the units
+// Cronometer exports. Where men and women differ the value for men is used.
+var micronutrientRDAs = map[string]float64{
+	"vitamin_d":  vitaminDRDAIU,
+	"vitamin_c":  vitaminCRDAMg,
+	"vitamin_e":  vitaminERDAMg,
+	"b12":        b12RDAUg,
+	"folate":     folateRDAUgDFE,
+	"calcium":    highCalciumMg,
+	"magnesium":  magnesiumRDAMg,
+	"iron":       ironRDAMg,
+	"zinc":       11,
+	"copper":     0.9,
+	"selenium":   seleniumRDAUg,
+	"phosphorus": 700,
+	"potassium":  potassiumAIMg,
+}
+
+// MicronutrientPeriod is the average micronutrient score over a 30-day period
+type MicronutrientPeriod struct {
+	Start        string  `json:"start"`
+	End          string  `json:"end"`
+	AverageScore float64 `json:"average_score"`
+	Trend        string  `json:"trend,omitempty"`
+}
+
+// MicronutrientAdequacyMean is the mean of each nutrient's intake as a share
+// of its RDA, capped at 1 so a surplus of one can't hide a shortfall of another
+func MicronutrientAdequacyMean(d DailyNutrition, rdas map[string]float64) float64 {
+	if len(rdas) == 0 {
+		return 0
+	}
+	var total float64
+	for name, rda := range rdas {
+		if field := nutrientField(&d, name); field != nil && rda > 0 {
+			total += math.Min(*field/rda, 1)
+		}
+	}
+	return total / float64(len(rdas))
+}
+
+// presentRDAs keeps the RDAs for nutrients logged on at least one day, since
+// a nutrient the export has no column for reads as zero every day
+func presentRDAs(records []DailyNutrition, rdas map[string]float64) map[string]float64 {
+	present := make(map[string]float64)
+	for name, rda := range rdas {
+		for i := range records {
+			if field := nutrientField(&records[i], name); field != nil && *field > 0 {
+				present[name] = rda
+				break
+			}
+		}
+	}
+	return present
+}
+
+// applyMicronutrientScores scores each day and averages the scores over
+// consecutive 30-day periods from the first day, with a trend arrow against
+// the period before
+func applyMicronutrientScores(records []DailyNutrition) []MicronutrientPeriod {
+	rdas := presentRDAs(records, micronutrientRDAs)
+	order := make([]int, len(records))
+	for i := range order {
+		order[i] = i
+		score := MicronutrientAdequacyMean(records[i], rdas)
+		records[i].MicronutrientScore = &score
+	}
+	sort.Slice(order, func(a, b int) bool {
+		return records[order[a]].Date < records[order[b]].Date
+	})
+
+	var first time.Time
+	var periods []int
+	totals := make(map[int]float64)
+	counts := make(map[int]int)
+	for _, idx := range order {
+		date, err := time.Parse("2006-01-02", records[idx].Date)
+		if err != nil {
+			continue
+		}
+		if first.IsZero() {
+			first = date
+		}
+		period := int(date.Sub(first).Hours()/24) / micronutrientTrendDays
+		if counts[period] == 0 {
+			periods = append(periods, period)
+		}
+		totals[period] += *records[idx].MicronutrientScore
+		counts[period]++
+	}
+
+	trend := make([]MicronutrientPeriod, 0, len(periods))
+	for i, period := range periods {
+		start := first.AddDate(0, 0, period*micronutrientTrendDays)
+		entry := MicronutrientPeriod{
+			Start:        start.Format("2006-01-02"),
+			End:          start.AddDate(0, 0, micronutrientTrendDays-1).Format("2006-01-02"),
+			AverageScore: totals[period] / float64(counts[period]),
+		}
+		if i > 0 {
+			switch change := entry.AverageScore - trend[i-1].AverageScore; {
+			case change >= micronutrientTrendThreshold:
+				entry.Trend = trendImproving
+			case change <= -micronutrientTrendThreshold:
+				entry.Trend = trendWorsening
+			default:
+				entry.Trend = trendStable
+			}
+		}
+		trend = append(trend, entry)
+	}
+	return trend
+}
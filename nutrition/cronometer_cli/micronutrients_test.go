@@ -0,0 +1,43 @@
+package main
+
+import (
+	"math"
+	"testing"
+)
+
+func TestMicronutrientAdequacyMeanCaps(t *testing.T) {
+	rdas := map[string]float64{"vitamin_c": 90, "magnesium": 400}
+	tests := []struct {
+		name string
+		day  DailyNutrition
+		want float64
+	}{
+		{"both at RDA", DailyNutrition{VitaminC: 90, Magnesium: 400}, 1},
+		{"half of each", DailyNutrition{VitaminC: 45, Magnesium: 200}, 0.5},
+		// 10x the vitamin C RDA still counts as 1, so it can't cover the magnesium gap
+		{"surplus capped", DailyNutrition{VitaminC: 900, Magnesium: 0}, 0.5},
+		{"surplus and shortfall", DailyNutrition{VitaminC: 180, Magnesium: 100}, (1 + 0.25) / 2},
+		{"nothing logged", DailyNutrition{}, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := MicronutrientAdequacyMean(tt.day, rdas); math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("MicronutrientAdequacyMean = %g, want %g", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMicronutrientAdequacyMeanNoRDAs(t *testing.T) {
+	if got := MicronutrientAdequacyMean(DailyNutrition{VitaminC: 90}, nil); got != 0 {
+		t.Errorf("MicronutrientAdequacyMean with no RDAs = %g, want 0", got)
+	}
+}
+
+func TestPresentRDAsDropsUnloggedNutrients(t *testing.T) {
+	records := []DailyNutrition{{VitaminC: 60}, {VitaminC: 0}}
+	present := presentRDAs(records, map[string]float64{"vitamin_c": 90, "selenium": 55})
+	if len(present) != 1 || present["vitamin_c"] != 90 {
+		t.Errorf("presentRDAs = %v, want only vitamin_c", present)
+	}
+}
@@ -0,0 +1,433 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// SodiumCategory buckets a day's sodium intake by heart health guidance:
+// "low" (<1500mg), "normal" (1500-2300mg), "high" (2300-3500mg), or
+// "very_high" (>3500mg)
+func SodiumCategory(mg float64) string {
+	switch {
+	case mg < 1500:
+		return "low"
+	case mg <= 2300:
+		return "normal"
+	case mg <= 3500:
+		return "high"
+	default:
+		return "very_high"
+	}
+}
+
+// applySodiumCategories sets each day's sodium category and returns how many days fell in each
+func applySodiumCategories(records []DailyNutrition) map[string]int {
+	distribution := make(map[string]int)
+	for i := range records {
+		category := SodiumCategory(records[i].Sodium)
+		records[i].SodiumCategory = category
+		distribution[category]++
+	}
+	return distribution
+}
+
+// CardiovascularReport is a day's potassium-to-sodium ratio and its risk flag
+type CardiovascularReport struct {
+	KNaRatio float64 `json:"k_na_ratio"`
+	RiskFlag string  `json:"risk_flag"`
+}
+
+// KNaRatio returns potassium divided by sodium, or 0 when no sodium was logged
+func KNaRatio(potassium, sodium float64) float64 {
+	if sodium <= 0 {
+		return 0
+	}
+	return potassium / sodium
+}
+
+// CardiovascularRiskFlag flags a K:Na ratio as "optimal" at 1:1 or more,
+// "moderate" from 0.5 to 1, and "high_risk" below 0.5
+func CardiovascularRiskFlag(ratio float64) string {
+	switch {
+	case ratio >= 1:
+		return "optimal"
+	case ratio >= 0.5:
+		return "moderate"
+	default:
+		return "high_risk"
+	}
+}
+
+// applyCardiovascular sets each day's K:Na ratio and risk flag, skipping days without sodium
+func applyCardiovascular(records []DailyNutrition) {
+	for i := range records {
+		d := &records[i]
+		if d.Sodium <= 0 {
+			continue
+		}
+		ratio := KNaRatio(d.Potassium, d.Sodium)
+		d.Cardiovascular = &CardiovascularReport{KNaRatio: ratio, RiskFlag: CardiovascularRiskFlag(ratio)}
+	}
+}
+
+// magnesiumDeficientMg is the intake below which magnesium is deficient, by sex
+var magnesiumDeficientMg = map[string]float64{"male": 310, "female": 255}
+
+// magnesiumHighMg is the intake above which magnesium is high, by sex: the
+// RDA (420/320mg) plus the 350mg upper limit for supplemental magnesium
+var magnesiumHighMg = map[string]float64{"male": 770, "female": 670}
+
+// MagnesiumSleep correlates daily magnesium intake with a logged sleep metric
+type MagnesiumSleep struct {
+	Metric      string  `json:"metric"`
+	Days        int     `json:"days"`
+	Correlation float64 `json:"correlation"`
+}
+
+// parseMagnesiumSex parses a -check-magnesium value such as "sex=male"
+func parseMagnesiumSex(value string) (string, error) {
+	pairs, err := parseKeyValues(value)
+	if err != nil {
+		return "", err
+	}
+	sex := pairs["sex"]
+	if _, ok := magnesiumDeficientMg[sex]; !ok {
+		return "", fmt.Errorf("expected sex=male or sex=female, got %q", value)
+	}
+	return sex, nil
+}
+
+// MagnesiumAdequacy rates a day's magnesium as "deficient", "adequate", or "high" for the given sex
+func MagnesiumAdequacy(mg float64, sex string) string {
+	switch {
+	case mg < magnesiumDeficientMg[sex]:
+		return "deficient"
+	case mg > magnesiumHighMg[sex]:
+		return "high"
+	default:
+		return "adequate"
+	}
+}
+
+// applyMagnesium sets each day's magnesium status
+func applyMagnesium(records []DailyNutrition, sex string) {
+	for i := range records {
+		records[i].MagnesiumStatus = MagnesiumAdequacy(records[i].Magnesium, sex)
+	}
+}
+
+// correlateMagnesiumSleep correlates magnesium with the first biometric metric
+// mentioning sleep (e.g. a sleep score or hours), matched by date. It returns
+// nil when no sleep metric was logged on at least three days with nutrition.
+func correlateMagnesiumSleep(records []DailyNutrition, biometrics []BiometricEntry) *MagnesiumSleep {
+	var metric string
+	for _, e := range biometrics {
+		if strings.Contains(strings.ToLower(e.Metric), "sleep") {
+			metric = e.Metric
+			break
+		}
+	}
+	if metric == "" {
+		return nil
+	}
+
+	sleepByDate := make(map[string]float64)
+	for _, e := range filterMetric(biometrics, metric) {
+		sleepByDate[e.Date] = e.Amount
+	}
+	var magnesium, sleep []float64
+	for _, d := range records {
+		if value, ok := sleepByDate[d.Date]; ok {
+			magnesium = append(magnesium, d.Magnesium)
+			sleep = append(sleep, value)
+		}
+	}
+	correlation, err := pearson(magnesium, sleep)
+	if err != nil || len(magnesium) < 3 {
+		return nil
+	}
+	return &MagnesiumSleep{Metric: metric, Days: len(magnesium), Correlation: correlation}
+}
+
+// highCalciumMg is the adult calcium RDA; intake at or above it counts as high
+// for the absorption check
+const highCalciumMg = 1000.0
+
+// CalciumAbsorptionRating rates how well the day's vitamin D supports calcium absorption
+type CalciumAbsorptionRating string
+
+const (
+	CalciumOptimal              CalciumAbsorptionRating = "optimal"
+	CalciumImpaired             CalciumAbsorptionRating = "impaired"
+	CalciumInsufficientVitaminD CalciumAbsorptionRating = "insufficient_vitamin_d"
+)
+
+// CalciumAbsorption is a day's calcium absorption rating, with a
+// recommendation when absorption is impaired
+type CalciumAbsorption struct {
+	Rating         CalciumAbsorptionRating `json:"rating"`
+	Recommendation string                  `json:"recommendation,omitempty"`
+}
+
+// CalciumVitaminDRatio rates calcium absorption: optimal when vitamin D meets
+// the RDA, impaired when calcium is high but vitamin D is below it, and
+// insufficient_vitamin_d when both are low
+func CalciumVitaminDRatio(calcium, vitaminD float64) CalciumAbsorptionRating {
+	switch {
+	case vitaminD >= vitaminDRDAIU:
+		return CalciumOptimal
+	case calcium >= highCalciumMg:
+		return CalciumImpaired
+	default:
+		return CalciumInsufficientVitaminD
+	}
+}
+
+// applyCalciumAbsorption sets each day's calcium absorption rating
+func applyCalciumAbsorption(records []DailyNutrition) {
+	for i := range records {
+		d := &records[i]
+		report := &CalciumAbsorption{Rating: CalciumVitaminDRatio(d.Calcium, d.VitaminD)}
+		if report.Rating == CalciumImpaired {
+			report.Recommendation = fmt.Sprintf("Add %.0f IU of vitamin D to absorb the day's %.0f mg of calcium", vitaminDRDAIU-d.VitaminD, d.Calcium)
+		}
+		d.CalciumAbsorption = report
+	}
+}
+
+// Bone health flags for the calcium-to-phosphorus ratio, optimal between 1:1 and 2:1
+const (
+	BoneHealthOptimal        = "optimal"
+	BoneHealthHighPhosphorus = "high_phosphorus"
+	BoneHealthHighCalcium    = "high_calcium"
+)
+
+// BoneHealthReport is a day's calcium-to-phosphorus ratio and its flag
+type BoneHealthReport struct {
+	CaPhRatio float64 `json:"ca_ph_ratio"`
+	Flag      string  `json:"flag"`
+}
+
+// CaPhRatio returns calcium divided by phosphorus, or 0 when no phosphorus was logged
+func CaPhRatio(calcium, phosphorus float64) float64 {
+	if phosphorus <= 0 {
+		return 0
+	}
+	return calcium / phosphorus
+}
+
+// BoneHealthFlag flags a calcium-to-phosphorus ratio as "high_phosphorus"
+// below 1:1, "high_calcium" above 2:1, and "optimal" otherwise
+func BoneHealthFlag(ratio float64) string {
+	switch {
+	case ratio < 1:
+		return BoneHealthHighPhosphorus
+	case ratio > 2:
+		return BoneHealthHighCalcium
+	default:
+		return BoneHealthOptimal
+	}
+}
+
+// applyBoneHealth sets each day's calcium-to-phosphorus ratio and flag,
+// skipping days without phosphorus
+func applyBoneHealth(records []DailyNutrition) {
+	for i := range records {
+		d := &records[i]
+		if d.Phosphorus <= 0 {
+			continue
+		}
+		ratio := CaPhRatio(d.Calcium, d.Phosphorus)
+		d.BoneHealth = &BoneHealthReport{CaPhRatio: ratio, Flag: BoneHealthFlag(ratio)}
+	}
+}
+
+// Zinc competes with copper for absorption; a Zn:Cu ratio between 8:1 and
+// 15:1 is ideal
+const (
+	minZnCuRatio = 8.0
+	maxZnCuRatio = 15.0
+)
+
+// ZincCopperReport is a day's zinc-to-copper ratio and its status, with a
+// warning outside the ideal range
+type ZincCopperReport struct {
+	ZnCuRatio   float64 `json:"zn_cu_ratio"`
+	RatioStatus string  `json:"ratio_status"`
+	Warning     string  `json:"warning,omitempty"`
+}
+
+// ZnCuRatio returns zinc divided by copper, or 0 when no copper was logged
+func ZnCuRatio(zinc, copper float64) float64 {
+	if copper <= 0 {
+		return 0
+	}
+	return zinc / copper
+}
+
+// RatioStatus rates a Zn:Cu ratio as "low_zinc" below 8, "high_zinc" above
+// 15, and "optimal" from 8 to 15 inclusive
+func RatioStatus(ratio float64) string {
+	switch {
+	case ratio < minZnCuRatio:
+		return "low_zinc"
+	case ratio > maxZnCuRatio:
+		return "high_zinc"
+	default:
+		return "optimal"
+	}
+}
+
+// applyZincCopper sets each day's zinc-to-copper ratio, skipping days without copper
+func applyZincCopper(records []DailyNutrition) {
+	for i := range records {
+		d := &records[i]
+		if d.Copper <= 0 {
+			continue
+		}
+		ratio := ZnCuRatio(d.Zinc, d.Copper)
+		report := &ZincCopperReport{ZnCuRatio: ratio, RatioStatus: RatioStatus(ratio)}
+		switch report.RatioStatus {
+		case "high_zinc":
+			report.Warning = fmt.Sprintf("Zn:Cu ratio %.1f is above 15:1; excess zinc may impair copper absorption", ratio)
+		case "low_zinc":
+			report.Warning = fmt.Sprintf("Zn:Cu ratio %.1f is below 8:1", ratio)
+		}
+		d.ZincCopper = report
+	}
+}
+
+// Iron absorption model: about 40% of the iron in meat, poultry, and fish is
+// heme iron, absorbed at ~25% (15-35%); the rest, and all plant iron, is
+// non-heme iron absorbed at ~6% (2-10%)
+const (
+	hemeShareOfMeatIron = 0.4
+	hemeAbsorption      = 0.25
+	nonHemeAbsorption   = 0.06
+)
+
+// meatCategories are lowercase fragments of Cronometer's USDA food
+// categories for meat, poultry, and fish
+var meatCategories = []string{"beef", "pork", "poultry", "finfish", "shellfish", "lamb", "veal", "game", "sausage", "luncheon meat"}
+
+// IronAbsorptionEstimate splits a day's iron into heme and non-heme iron and
+// estimates how much is absorbed
+type IronAbsorptionEstimate struct {
+	HemeIronMg          float64 `json:"heme_iron_mg"`
+	NonHemeIronMg       float64 `json:"non_heme_iron_mg"`
+	EstimatedAbsorbedMg float64 `json:"estimated_absorbed_mg"`
+	AbsorptionRate      float64 `json:"absorption_rate"`
+}
+
+// isMeat reports whether a diary entry is meat, poultry, or fish, by its
+// category or, failing that, its name
+func isMeat(e FoodEntry) bool {
+	return containsAny(e.Category, meatCategories) || containsAny(e.FoodName, meatKeywords)
+}
+
+// IronBioavailability estimates heme iron as 40% of the iron from meat, with
+// meat's share of the day's iron taken as its share of the diary's calories
+func IronBioavailability(totalIronMg float64, diary []FoodEntry) IronAbsorptionEstimate {
+	var calories, meatCalories float64
+	for _, e := range diary {
+		calories += e.Calories
+		if isMeat(e) {
+			meatCalories += e.Calories
+		}
+	}
+	meatShare := 0.0
+	if calories > 0 {
+		meatShare = meatCalories / calories
+	}
+
+	estimate := IronAbsorptionEstimate{HemeIronMg: totalIronMg * meatShare * hemeShareOfMeatIron}
+	estimate.NonHemeIronMg = totalIronMg - estimate.HemeIronMg
+	estimate.EstimatedAbsorbedMg = estimate.HemeIronMg*hemeAbsorption + estimate.NonHemeIronMg*nonHemeAbsorption
+	if totalIronMg > 0 {
+		estimate.AbsorptionRate = estimate.EstimatedAbsorbedMg / totalIronMg
+	}
+	return estimate
+}
+
+// applyIronAbsorption estimates each day's iron absorption from its diary
+func applyIronAbsorption(records []DailyNutrition, diary []FoodEntry) {
+	byDate := groupEntriesByDate(diary)
+	for i := range records {
+		estimate := IronBioavailability(records[i].Iron, byDate[records[i].Date])
+		records[i].IronAbsorption = &estimate
+	}
+}
+
+// traceMineralIntakes are adult RDAs, or AIs where there is no RDA, for men,
+// keyed by nutrient name: manganese (AI) in mg, chromium (AI), molybdenum,
+// and iodine in µg
+var traceMineralIntakes = map[string]float64{
+	"manganese":  2.3,
+	"chromium":   35,
+	"molybdenum": 45,
+	"iodine":     150,
+}
+
+// TraceMineral is a day's intake of one trace mineral against its RDA or AI
+type TraceMineral struct {
+	Intake    float64 `json:"intake"`
+	Reference float64 `json:"reference"`
+	Fraction  float64 `json:"fraction"`
+	Status    string  `json:"status"`
+}
+
+// TraceMineralStatus rates an intake as "adequate" at or above its reference intake, else "low"
+func TraceMineralStatus(intake, reference float64) string {
+	if intake >= reference {
+		return "adequate"
+	}
+	return "low"
+}
+
+// applyTraceMinerals sets each day's manganese status and trace mineral adequacy report
+func applyTraceMinerals(records []DailyNutrition) {
+	for i := range records {
+		d := &records[i]
+		d.TraceMinerals = make(map[string]TraceMineral, len(traceMineralIntakes))
+		for name, reference := range traceMineralIntakes {
+			intake := *nutrientField(d, name)
+			d.TraceMinerals[name] = TraceMineral{
+				Intake:    intake,
+				Reference: reference,
+				Fraction:  intake / reference,
+				Status:    TraceMineralStatus(intake, reference),
+			}
+		}
+		d.ManganeseStatus = d.TraceMinerals["manganese"].Status
+	}
+}
+
+// Iron and vitamin C pairing thresholds: the adult iron RDA for men, and the
+// vitamin C RDA for women, above which a meal's vitamin C helps non-heme iron
+const (
+	ironRDAMg         = 8.0
+	vitaminCPairingMg = 75.0
+)
+
+// IronVitaminCPairingAdvice advises on pairing iron with vitamin C when iron
+// is below its RDA: a suggestion when vitamin C is also below its RDA, or a
+// positive note when it isn't. It returns "" when iron meets the RDA.
+func IronVitaminCPairingAdvice(iron, vitaminC, ironRDA, vitaminCRDA float64) string {
+	switch {
+	case iron >= ironRDA:
+		return ""
+	case vitaminC < vitaminCRDA:
+		return "Consider pairing iron-rich foods with vitamin C sources."
+	default:
+		return "Good vitamin C intake helps absorb the iron you do get; keep pairing it with iron-rich foods."
+	}
+}
+
+// applyIronVitaminCPairing sets each day's iron absorption advice
+func applyIronVitaminCPairing(records []DailyNutrition) {
+	for i := range records {
+		d := &records[i]
+		d.IronAbsorptionAdvice = IronVitaminCPairingAdvice(d.Iron, d.VitaminC, ironRDAMg, vitaminCPairingMg)
+	}
+}
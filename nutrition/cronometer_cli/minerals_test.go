@@ -0,0 +1,44 @@
+package main
+
+import "testing"
+
+func TestZincCopperRatioBoundaries(t *testing.T) {
+	tests := []struct {
+		name         string
+		zinc, copper float64
+		wantRatio    float64
+		wantStatus   string
+		wantWarning  bool
+	}{
+		{"just below 8", 7.9, 1, 7.9, "low_zinc", true},
+		{"exactly 8", 8, 1, 8, "optimal", false},
+		{"inside range", 11, 1, 11, "optimal", false},
+		{"exactly 15", 15, 1, 15, "optimal", false},
+		{"just above 15", 15.1, 1, 15.1, "high_zinc", true},
+		{"scaled exactly 15", 12, 0.8, 15, "optimal", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			records := []DailyNutrition{{Zinc: tt.zinc, Copper: tt.copper}}
+			applyZincCopper(records)
+			report := records[0].ZincCopper
+			if report == nil {
+				t.Fatal("no zinc-copper report")
+			}
+			if report.ZnCuRatio != tt.wantRatio || report.RatioStatus != tt.wantStatus {
+				t.Errorf("ratio %g status %q, want %g %q", report.ZnCuRatio, report.RatioStatus, tt.wantRatio, tt.wantStatus)
+			}
+			if (report.Warning != "") != tt.wantWarning {
+				t.Errorf("warning = %q, want warning %v", report.Warning, tt.wantWarning)
+			}
+		})
+	}
+}
+
+func TestZincCopperSkipsDaysWithoutCopper(t *testing.T) {
+	records := []DailyNutrition{{Zinc: 10}}
+	applyZincCopper(records)
+	if records[0].ZincCopper != nil {
+		t.Errorf("report = %+v, want none without copper", records[0].ZincCopper)
+	}
+}
@@ -0,0 +1,119 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/jrmycanady/gocronometer"
+)
+
+// mockClient serves canned exports. Each daily nutrition export returns the
+// requested range as "start..end", prefixed with "account:" when account is
+// set, so a mockParser can map it to records.
+type mockClient struct {
+	account    string
+	servings   gocronometer.ServingRecords
+	biometrics gocronometer.BiometricRecords
+	err        error
+
+	mu    sync.Mutex
+	calls []string
+}
+
+func (c *mockClient) record(call string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.calls = append(c.calls, call)
+}
+
+func (c *mockClient) ExportDailyNutrition(ctx context.Context, start, end time.Time) (string, error) {
+	c.record("nutrition")
+	if c.err != nil {
+		return "", c.err
+	}
+	export := start.Format("2006-01-02") + ".." + end.Format("2006-01-02")
+	if c.account != "" {
+		export = c.account + ":" + export
+	}
+	return export, nil
+}
+
+func (c *mockClient) ExportServingsParsedWithLocation(ctx context.Context, start, end time.Time, location *time.Location) (gocronometer.ServingRecords, error) {
+	c.record("servings")
+	return c.servings, c.err
+}
+
+func (c *mockClient) ExportBiometricRecordsParsedWithLocation(ctx context.Context, start, end time.Time, location *time.Location) (gocronometer.BiometricRecords, error) {
+	c.record("biometrics")
+	return c.biometrics, c.err
+}
+
+func (c *mockClient) ExportNotes(ctx context.Context, start, end time.Time) (string, error) {
+	c.record("notes")
+	return "", c.err
+}
+
+func (c *mockClient) ExportExercisesParsedWithLocation(ctx context.Context, start, end time.Time, location *time.Location) (gocronometer.ExerciseRecords, error) {
+	c.record("exercises")
+	return nil, c.err
+}
+
+// mockStorage keeps goal sets and local foods in memory
+type mockStorage struct {
+	goalSets map[string]map[string]float64
+	foods    FoodDatabase
+}
+
+func (s *mockStorage) LoadGoals(name string) (map[string]float64, error) {
+	goals, ok := s.goalSets[name]
+	if !ok {
+		return nil, fmt.Errorf("goal set %q not found", name)
+	}
+	return goals, nil
+}
+
+func (s *mockStorage) StoreGoals(name string, goals map[string]float64) error {
+	if s.goalSets == nil {
+		s.goalSets = make(map[string]map[string]float64)
+	}
+	s.goalSets[name] = goals
+	return nil
+}
+
+func (s *mockStorage) LoadFoodDatabase() (FoodDatabase, error) {
+	return s.foods, nil
+}
+
+func (s *mockStorage) AddLocalFood(food LocalFood) error {
+	s.foods.Foods = append(s.foods.Foods, food)
+	return nil
+}
+
+// mockParser returns the records canned for each export, keyed by the
+// "start..end" string mockClient returns
+type mockParser struct {
+	days map[string][]DailyNutrition
+	err  error
+}
+
+func (p mockParser) ParseDailyNutrition(csvData string) ([]DailyNutrition, error) {
+	if p.err != nil {
+		return nil, p.err
+	}
+	// Copy so stages that annotate records don't change the fixture
+	return append([]DailyNutrition(nil), p.days[csvData]...), nil
+}
+
+// mockFormatter keeps the result it was asked to format
+type mockFormatter struct {
+	result *Result
+}
+
+func (f *mockFormatter) Format(w io.Writer, result Result) error {
+	f.result = &result
+	_, err := io.WriteString(w, "formatted")
+	return err
+}
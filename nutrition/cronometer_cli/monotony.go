@@ -0,0 +1,52 @@
+package main
+
+import (
+	"sort"
+	"time"
+)
+
+// MonotonyDay is the dietary variety over the window ending on Date
+type MonotonyDay struct {
+	Date          string  `json:"date"`
+	UniqueFoods   int     `json:"unique_foods"`
+	MonotonyScore float64 `json:"monotony_score"`
+}
+
+// FoodMonotonyScore returns, for each logged date, the number of distinct
+// foods over the windowDays ending that date and a monotony score of
+// 1/UniqueFoods: 1.0 when a single food made up the whole window.
+func FoodMonotonyScore(entries []FoodEntry, windowDays int) []MonotonyDay {
+	foodsByDate := make(map[string]map[string]bool)
+	for _, e := range entries {
+		if foodsByDate[e.Date] == nil {
+			foodsByDate[e.Date] = make(map[string]bool)
+		}
+		foodsByDate[e.Date][foodKey(e.FoodName)] = true
+	}
+
+	dates := make([]string, 0, len(foodsByDate))
+	for date := range foodsByDate {
+		dates = append(dates, date)
+	}
+	sort.Strings(dates)
+
+	days := make([]MonotonyDay, 0, len(dates))
+	for _, date := range dates {
+		end, err := time.Parse("2006-01-02", date)
+		if err != nil {
+			continue
+		}
+		unique := make(map[string]bool)
+		for offset := 0; offset < windowDays; offset++ {
+			for food := range foodsByDate[end.AddDate(0, 0, -offset).Format("2006-01-02")] {
+				unique[food] = true
+			}
+		}
+		day := MonotonyDay{Date: date, UniqueFoods: len(unique)}
+		if day.UniqueFoods > 0 {
+			day.MonotonyScore = 1 / float64(day.UniqueFoods)
+		}
+		days = append(days, day)
+	}
+	return days
+}
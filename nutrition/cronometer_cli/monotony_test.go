@@ -0,0 +1,47 @@
+package main
+
+import "testing"
+
+func TestFoodMonotonyScore(t *testing.T) {
+	tests := []struct {
+		name       string
+		entries    []FoodEntry
+		window     int
+		wantUnique []int
+		wantScore  []float64
+	}{
+		{
+			"single food every day",
+			[]FoodEntry{{Date: "2024-03-01", FoodName: "Rice"}, {Date: "2024-03-02", FoodName: "rice"}, {Date: "2024-03-03", FoodName: "Rice"}},
+			7, []int{1, 1, 1}, []float64{1, 1, 1},
+		},
+		{
+			"single food logged several times in a day",
+			[]FoodEntry{{Date: "2024-03-01", FoodName: "Rice"}, {Date: "2024-03-01", FoodName: "Rice"}},
+			7, []int{1}, []float64{1},
+		},
+		{
+			"foods accumulate over the window",
+			[]FoodEntry{{Date: "2024-03-01", FoodName: "Rice"}, {Date: "2024-03-02", FoodName: "Beans"}, {Date: "2024-03-03", FoodName: "Kale"}, {Date: "2024-03-03", FoodName: "Eggs"}},
+			7, []int{1, 2, 4}, []float64{1, 0.5, 0.25},
+		},
+		{
+			"older days leave the window",
+			[]FoodEntry{{Date: "2024-03-01", FoodName: "Rice"}, {Date: "2024-03-02", FoodName: "Beans"}, {Date: "2024-03-03", FoodName: "Beans"}},
+			2, []int{1, 2, 1}, []float64{1, 0.5, 1},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			days := FoodMonotonyScore(tt.entries, tt.window)
+			if len(days) != len(tt.wantUnique) {
+				t.Fatalf("got %d days, want %d", len(days), len(tt.wantUnique))
+			}
+			for i, d := range days {
+				if d.UniqueFoods != tt.wantUnique[i] || d.MonotonyScore != tt.wantScore[i] {
+					t.Errorf("%s: %d foods scoring %g, want %d scoring %g", d.Date, d.UniqueFoods, d.MonotonyScore, tt.wantUnique[i], tt.wantScore[i])
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,29 @@
+package main
+
+// nightEatingShare is the share of a day's calories after the cutoff that
+// raises a night eating warning
+const nightEatingShare = 0.2
+
+// NightEatingCalories sums calories from timed entries at or after cutoffHour.
+// Entries logged without a time of day are skipped.
+func NightEatingCalories(entries []FoodEntry, cutoffHour int) float64 {
+	var total float64
+	for _, e := range entries {
+		if hasClockTime(e.Time) && e.Time.Hour() >= cutoffHour {
+			total += e.Calories
+		}
+	}
+	return total
+}
+
+// applyNightEating sets each day's night calories and warns when they are
+// more than 20% of the day's total
+func applyNightEating(records []DailyNutrition, diary []FoodEntry, cutoffHour int) {
+	byDate := groupEntriesByDate(diary)
+	for i := range records {
+		d := &records[i]
+		calories := NightEatingCalories(byDate[d.Date], cutoffHour)
+		d.NightEatingCalories = &calories
+		d.NightEatingWarning = d.Calories > 0 && calories > nightEatingShare*d.Calories
+	}
+}
@@ -0,0 +1,41 @@
+package main
+
+import "fmt"
+
+// PerKgNutrition expresses a day's gram values per kilogram of bodyweight
+type PerKgNutrition struct {
+	WeightKg      float64 `json:"weight_kg"`
+	CaloriesPerKg float64 `json:"calories_per_kg"`
+	FatPerKg      float64 `json:"fat_per_kg"`
+	CarbsPerKg    float64 `json:"carbs_per_kg"`
+	ProteinPerKg  float64 `json:"protein_per_kg"`
+	FiberPerKg    float64 `json:"fiber_per_kg"`
+	Omega3PerKg   float64 `json:"omega_3_per_kg"`
+	Omega6PerKg   float64 `json:"omega_6_per_kg"`
+}
+
+// normalizeByWeight adds per-kilogram values to each record using the
+// nearest measured weight, or fallbackKg when no weight was measured
+func normalizeByWeight(records []DailyNutrition, weights []WeightMeasurement, fallbackKg float64) error {
+	for i := range records {
+		d := &records[i]
+		kg, ok := nearestWeight(d.Date, weights)
+		if !ok {
+			kg = fallbackKg
+		}
+		if kg <= 0 {
+			return fmt.Errorf("no weight available for %s: log weight in Cronometer or set -weight-kg", d.Date)
+		}
+		d.PerKg = &PerKgNutrition{
+			WeightKg:      kg,
+			CaloriesPerKg: d.Calories / kg,
+			FatPerKg:      d.Fat / kg,
+			CarbsPerKg:    d.Carbs / kg,
+			ProteinPerKg:  d.Protein / kg,
+			FiberPerKg:    d.Fiber / kg,
+			Omega3PerKg:   totalOmega3(*d) / kg,
+			Omega6PerKg:   d.Omega6 / kg,
+		}
+	}
+	return nil
+}
@@ -0,0 +1,88 @@
+package main
+
+import "math"
+
+// npuDigestibility is the true protein digestibility assumed for a mixed diet
+const npuDigestibility = 0.9
+
+// whoAminoAcidPattern is the WHO/FAO/UNU (2007) adult essential amino acid
+// requirement pattern in mg per g of protein
+var whoAminoAcidPattern = map[string]float64{
+	"histidine":              15,
+	"isoleucine":             30,
+	"leucine":                59,
+	"lysine":                 45,
+	"methionine+cystine":     22,
+	"phenylalanine+tyrosine": 38,
+	"threonine":              23,
+	"tryptophan":             6,
+	"valine":                 39,
+}
+
+// aminoAcidProfile returns the day's essential amino acids in mg per g of
+// protein, leaving out any the export had no data for
+func aminoAcidProfile(d DailyNutrition) map[string]float64 {
+	grams := map[string]float64{
+		"histidine":              d.Histidine,
+		"isoleucine":             d.Isoleucine,
+		"leucine":                d.Leucine,
+		"lysine":                 d.Lysine,
+		"methionine+cystine":     d.Methionine + d.Cystine,
+		"phenylalanine+tyrosine": d.Phenylalanine + d.Tyrosine,
+		"threonine":              d.Threonine,
+		"tryptophan":             d.Tryptophan,
+		"valine":                 d.Valine,
+	}
+	profile := make(map[string]float64)
+	if d.Protein <= 0 {
+		return profile
+	}
+	for name, g := range grams {
+		if g > 0 {
+			profile[name] = g * 1000 / d.Protein
+		}
+	}
+	return profile
+}
+
+// limitingAminoAcid returns the amino acid with the lowest share of its WHO
+// requirement, and that share capped at 1
+func limitingAminoAcid(aminoAcids map[string]float64) (string, float64) {
+	limiting, score := "", math.Inf(1)
+	for name, mgPerG := range aminoAcids {
+		required, ok := whoAminoAcidPattern[name]
+		if !ok {
+			continue
+		}
+		if ratio := mgPerG / required; ratio < score || (ratio == score && name < limiting) {
+			limiting, score = name, ratio
+		}
+	}
+	if limiting == "" {
+		return "", 0
+	}
+	return limiting, math.Min(score, 1)
+}
+
+// NPUEstimate estimates net protein utilization as a percentage from amino
+// acids in mg per g of protein: the limiting amino acid's share of the WHO
+// pattern, capped at 1, times the assumed 90% digestibility
+func NPUEstimate(aminoAcids map[string]float64) float64 {
+	_, score := limitingAminoAcid(aminoAcids)
+	return 100 * score * npuDigestibility
+}
+
+// applyNPU sets each day's NPU estimate and limiting amino acid, skipping
+// days without amino acid data
+func applyNPU(records []DailyNutrition) {
+	for i := range records {
+		d := &records[i]
+		profile := aminoAcidProfile(*d)
+		if len(profile) == 0 {
+			continue
+		}
+		npu := NPUEstimate(profile)
+		d.NPU = &npu
+		d.LimitingAminoAcid, _ = limitingAminoAcid(profile)
+	}
+}
@@ -0,0 +1,93 @@
+package main
+
+import "sort"
+
+// efficiencyListSize is how many foods -nutrient-calorie-efficiency lists at each end
+const efficiencyListSize = 10
+
+// FoodScore compares a food's micronutrients to its calories, both per 100g
+type FoodScore struct {
+	FoodName string `json:"food_name"`
+	// NutrientDensity is the sum of each micronutrient's %RDA per 100g
+	NutrientDensity float64 `json:"nutrient_density"`
+	// CalorieDensity is kcal per 100g
+	CalorieDensity  float64 `json:"calorie_density"`
+	EfficiencyRatio float64 `json:"efficiency_ratio"`
+}
+
+// NutrientEfficiencyReport lists the foods with the highest and lowest
+// nutrient to calorie density ratios, each best first
+type NutrientEfficiencyReport struct {
+	Top    []FoodScore `json:"top"`
+	Bottom []FoodScore `json:"bottom"`
+}
+
+// NutrientVsCalorieDensityScore scores each food in the entries, pooling its
+// weighed entries, sorted by efficiency ratio from highest. Entries without a
+// weight and foods without calories are left out.
+func NutrientVsCalorieDensityScore(entries []FoodEntry, rdas map[string]float64) []FoodScore {
+	type pooled struct {
+		name      string
+		grams     float64
+		calories  float64
+		nutrients map[string]float64
+	}
+	byFood := make(map[string]*pooled)
+	for _, e := range entries {
+		g, ok := entryGrams(e)
+		if !ok {
+			continue
+		}
+		key := foodKey(e.FoodName)
+		p, ok := byFood[key]
+		if !ok {
+			p = &pooled{name: e.FoodName, nutrients: make(map[string]float64)}
+			byFood[key] = p
+		}
+		p.grams += g
+		p.calories += e.Calories
+		for name := range rdas {
+			p.nutrients[name] += entryNutrient(e, name)
+		}
+	}
+
+	scores := make([]FoodScore, 0, len(byFood))
+	for _, p := range byFood {
+		if p.calories <= 0 {
+			continue
+		}
+		per100g := 100 / p.grams
+		var density float64
+		for name, rda := range rdas {
+			density += p.nutrients[name] / rda * 100 * per100g
+		}
+		calorieDensity := p.calories * per100g
+		scores = append(scores, FoodScore{
+			FoodName:        p.name,
+			NutrientDensity: density,
+			CalorieDensity:  calorieDensity,
+			EfficiencyRatio: density / calorieDensity,
+		})
+	}
+	sort.Slice(scores, func(i, j int) bool {
+		if scores[i].EfficiencyRatio != scores[j].EfficiencyRatio {
+			return scores[i].EfficiencyRatio > scores[j].EfficiencyRatio
+		}
+		return scores[i].FoodName < scores[j].FoodName
+	})
+	return scores
+}
+
+// nutrientEfficiencyReport scores the diary's foods against the micronutrient
+// RDAs and keeps the 10 best and 10 worst
+func nutrientEfficiencyReport(diary []FoodEntry) *NutrientEfficiencyReport {
+	scores := NutrientVsCalorieDensityScore(diary, micronutrientRDAs)
+	n := len(scores)
+	if n > efficiencyListSize {
+		n = efficiencyListSize
+	}
+	return &NutrientEfficiencyReport{
+		Top:    scores[:n],
+		Bottom: scores[len(scores)-n:],
+	}
+}
@@ -0,0 +1,80 @@
+package main
+
+// optionalNutrients are parsed from the Cronometer CSV when their column is
+// present; missing columns leave the field at zero
+var optionalNutrients = []struct {
+	name   string
+	header string
+	field  func(d *DailyNutrition) *float64
+}{
+	{"fiber", "Fiber (g)", func(d *DailyNutrition) *float64 { return &d.Fiber }},
+	{"sodium", "Sodium (mg)", func(d *DailyNutrition) *float64 { return &d.Sodium }},
+	{"ala", "ALA (g)", func(d *DailyNutrition) *float64 { return &d.ALA }},
+	{"epa", "EPA (g)", func(d *DailyNutrition) *float64 { return &d.EPA }},
+	{"dha", "DHA (g)", func(d *DailyNutrition) *float64 { return &d.DHA }},
+	{"omega_3", "Omega-3 (g)", func(d *DailyNutrition) *float64 { return &d.Omega3 }},
+	{"omega_6", "Omega-6 (g)", func(d *DailyNutrition) *float64 { return &d.Omega6 }},
+	{"vitamin_d", "Vitamin D (IU)", func(d *DailyNutrition) *float64 { return &d.VitaminD }},
+	{"magnesium", "Magnesium (mg)", func(d *DailyNutrition) *float64 { return &d.Magnesium }},
+	{"calcium", "Calcium (mg)", func(d *DailyNutrition) *float64 { return &d.Calcium }},
+	{"b12", "B12 (Cobalamin) (µg)", func(d *DailyNutrition) *float64 { return &d.B12 }},
+	{"iron", "Iron (mg)", func(d *DailyNutrition) *float64 { return &d.Iron }},
+	{"vitamin_c", "Vitamin C (mg)", func(d *DailyNutrition) *float64 { return &d.VitaminC }},
+	{"vitamin_e", "Vitamin E (mg)", func(d *DailyNutrition) *float64 { return &d.VitaminE }},
+	{"selenium", "Selenium (µg)", func(d *DailyNutrition) *float64 { return &d.Selenium }},
+	{"phosphorus", "Phosphorus (mg)", func(d *DailyNutrition) *float64 { return &d.Phosphorus }},
+	{"leucine", "Leucine (g)", func(d *DailyNutrition) *float64 { return &d.Leucine }},
+	{"folate", "Folate (µg)", func(d *DailyNutrition) *float64 { return &d.Folate }},
+	{"folic_acid", "Folic Acid (µg)", func(d *DailyNutrition) *float64 { return &d.FolicAcid }},
+	{"glycine", "Glycine (g)", func(d *DailyNutrition) *float64 { return &d.Glycine }},
+	{"arginine", "Arginine (g)", func(d *DailyNutrition) *float64 { return &d.Arginine }},
+	{"methionine", "Methionine (g)", func(d *DailyNutrition) *float64 { return &d.Methionine }},
+	{"zinc", "Zinc (mg)", func(d *DailyNutrition) *float64 { return &d.Zinc }},
+	{"copper", "Copper (mg)", func(d *DailyNutrition) *float64 { return &d.Copper }},
+	{"potassium", "Potassium (mg)", func(d *DailyNutrition) *float64 { return &d.Potassium }},
+	{"tryptophan", "Tryptophan (g)", func(d *DailyNutrition) *float64 { return &d.Tryptophan }},
+	{"isoleucine", "Isoleucine (g)", func(d *DailyNutrition) *float64 { return &d.Isoleucine }},
+	{"valine", "Valine (g)", func(d *DailyNutrition) *float64 { return &d.Valine }},
+	{"phenylalanine", "Phenylalanine (g)", func(d *DailyNutrition) *float64 { return &d.Phenylalanine }},
+	{"tyrosine", "Tyrosine (g)", func(d *DailyNutrition) *float64 { return &d.Tyrosine }},
+	{"saturated_fat", "Saturated (g)", func(d *DailyNutrition) *float64 { return &d.SaturatedFat }},
+	{"cholesterol", "Cholesterol (mg)", func(d *DailyNutrition) *float64 { return &d.Cholesterol }},
+	{"soluble_fiber", "Soluble Fiber (g)", func(d *DailyNutrition) *float64 { return &d.SolubleFiber }},
+	{"lutein", "Lutein (µg)", func(d *DailyNutrition) *float64 { return &d.Lutein }},
+	{"zeaxanthin", "Zeaxanthin (µg)", func(d *DailyNutrition) *float64 { return &d.Zeaxanthin }},
+	{"manganese", "Manganese (mg)", func(d *DailyNutrition) *float64 { return &d.Manganese }},
+	{"chromium", "Chromium (µg)", func(d *DailyNutrition) *float64 { return &d.Chromium }},
+	{"molybdenum", "Molybdenum (µg)", func(d *DailyNutrition) *float64 { return &d.Molybdenum }},
+	{"iodine", "Iodine (µg)", func(d *DailyNutrition) *float64 { return &d.Iodine }},
+	{"ornithine", "Ornithine (g)", func(d *DailyNutrition) *float64 { return &d.Ornithine }},
+	{"histidine", "Histidine (g)", func(d *DailyNutrition) *float64 { return &d.Histidine }},
+	{"lysine", "Lysine (g)", func(d *DailyNutrition) *float64 { return &d.Lysine }},
+	{"threonine", "Threonine (g)", func(d *DailyNutrition) *float64 { return &d.Threonine }},
+	{"cystine", "Cystine (g)", func(d *DailyNutrition) *float64 { return &d.Cystine }},
+	{"biotin", "Biotin (µg)", func(d *DailyNutrition) *float64 { return &d.Biotin }},
+	{"vitamin_k", "Vitamin K (µg)", func(d *DailyNutrition) *float64 { return &d.VitaminK }},
+	{"vitamin_k1", "Vitamin K1 (µg)", func(d *DailyNutrition) *float64 { return &d.VitaminK1 }},
+	{"vitamin_k2", "Vitamin K2 (µg)", func(d *DailyNutrition) *float64 { return &d.VitaminK2 }},
+	{"choline", "Choline (mg)", func(d *DailyNutrition) *float64 { return &d.Choline }},
+	{"lutein_zeaxanthin", "Lutein+Zeaxanthin (µg)", func(d *DailyNutrition) *float64 { return &d.LuteinZeaxanthin }},
+}
+
+// nutrientField returns a pointer to the named nutrient in d, or nil if the name is unknown
+func nutrientField(d *DailyNutrition, name string) *float64 {
+	switch name {
+	case "calories":
+		return &d.Calories
+	case "fat":
+		return &d.Fat
+	case "carbs":
+		return &d.Carbs
+	case "protein":
+		return &d.Protein
+	}
+	for _, n := range optionalNutrients {
+		if n.name == name {
+			return n.field(d)
+		}
+	}
+	return nil
+}
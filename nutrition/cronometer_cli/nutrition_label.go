@@ -0,0 +1,249 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"sort"
+)
+
+// fdaDailyValues are the FDA label Daily Values (21 CFR 101.9, 2016 rule)
+// used for the percent DV column
+var fdaDailyValues = map[string]float64{
+	"total_fat":          78,
+	"saturated_fat":      20,
+	"cholesterol":        300,
+	"sodium":             2300,
+	"total_carbohydrate": 275,
+	"dietary_fiber":      28,
+	"added_sugars":       50,
+	"vitamin_d":          20,
+	"calcium":            1300,
+	"iron":               18,
+	"potassium":          4700,
+}
+
+// iuPerMcgVitaminD converts vitamin D from IU, as Cronometer logs it, to the µg on the label
+const iuPerMcgVitaminD = 40.0
+
+// NutritionLabel is an FDA Nutrition Facts label for one serving of a food,
+// with amounts rounded by the FDA rounding rules
+type NutritionLabel struct {
+	FoodName           string             `json:"food_name"`
+	ServingSize        float64            `json:"serving_size"`
+	ServingUnit        string             `json:"serving_unit"`
+	Calories           float64            `json:"calories"`
+	TotalFatG          float64            `json:"total_fat_g"`
+	SaturatedFatG      float64            `json:"saturated_fat_g"`
+	TransFatG          float64            `json:"trans_fat_g"`
+	CholesterolMg      float64            `json:"cholesterol_mg"`
+	SodiumMg           float64            `json:"sodium_mg"`
+	TotalCarbohydrateG float64            `json:"total_carbohydrate_g"`
+	DietaryFiberG      float64            `json:"dietary_fiber_g"`
+	TotalSugarsG       float64            `json:"total_sugars_g"`
+	AddedSugarsG       float64            `json:"added_sugars_g"`
+	ProteinG           float64            `json:"protein_g"`
+	VitaminDMcg        float64            `json:"vitamin_d_mcg"`
+	CalciumMg          float64            `json:"calcium_mg"`
+	IronMg             float64            `json:"iron_mg"`
+	PotassiumMg        float64            `json:"potassium_mg"`
+	PercentDailyValue  map[string]float64 `json:"percent_daily_value"`
+}
+
+// roundTo rounds v to the nearest multiple of step
+func roundTo(v, step float64) float64 {
+	return math.Round(v/step) * step
+}
+
+// fdaCalories rounds calories: under 5 is 0, up to 50 the nearest 5, above that the nearest 10
+func fdaCalories(kcal float64) float64 {
+	switch {
+	case kcal < 5:
+		return 0
+	case kcal <= 50:
+		return roundTo(kcal, 5)
+	default:
+		return roundTo(kcal, 10)
+	}
+}
+
+// fdaFat rounds fats: under 0.5g is 0, under 5g the nearest 0.5g, above that the nearest gram
+func fdaFat(g float64) float64 {
+	switch {
+	case g < 0.5:
+		return 0
+	case g < 5:
+		return roundTo(g, 0.5)
+	default:
+		return math.Round(g)
+	}
+}
+
+// fdaSodium rounds sodium: under 5mg is 0, up to 140mg the nearest 5mg, above that the nearest 10mg
+func fdaSodium(mg float64) float64 {
+	switch {
+	case mg < 5:
+		return 0
+	case mg <= 140:
+		return roundTo(mg, 5)
+	default:
+		return roundTo(mg, 10)
+	}
+}
+
+// fdaCholesterol rounds cholesterol: under 2mg is 0, above that the nearest 5mg
+func fdaCholesterol(mg float64) float64 {
+	if mg < 2 {
+		return 0
+	}
+	return roundTo(mg, 5)
+}
+
+// fdaGrams rounds carbohydrates, fiber, sugars, and protein: under 0.5g is 0,
+// above that the nearest gram
+func fdaGrams(g float64) float64 {
+	if g < 0.5 {
+		return 0
+	}
+	return math.Round(g)
+}
+
+// labelScale returns how many of the entry's amounts make up the serving,
+// converting units where possible and otherwise treating the entry as one serving
+func labelScale(entry FoodEntry, servingSize float64, servingUnit string) float64 {
+	if entry.Amount <= 0 || servingSize <= 0 {
+		return 1
+	}
+	from, to := normalizeUnit(entry.Unit), normalizeUnit(servingUnit)
+	if from == to {
+		return servingSize / entry.Amount
+	}
+	fromConv, fromOK := servingUnits[from]
+	toConv, toOK := servingUnits[to]
+	if !fromOK || !toOK || fromConv.dimension != toConv.dimension {
+		return 1
+	}
+	return servingSize * toConv.factor / (entry.Amount * fromConv.factor)
+}
+
+// GenerateNutritionLabel builds an FDA Nutrition Facts label for a serving
+// of the entry's food, scaling the entry's nutrients to the serving size
+func GenerateNutritionLabel(entry FoodEntry, servingSize float64, servingUnit string) NutritionLabel {
+	s := labelScale(entry, servingSize, servingUnit)
+	label := NutritionLabel{
+		FoodName:           entry.FoodName,
+		ServingSize:        servingSize,
+		ServingUnit:        servingUnit,
+		Calories:           fdaCalories(entry.Calories * s),
+		TotalFatG:          fdaFat(entry.Fat * s),
+		SaturatedFatG:      fdaFat(entry.SaturatedFat * s),
+		TransFatG:          fdaFat(entry.TransFat * s),
+		CholesterolMg:      fdaCholesterol(entry.Cholesterol * s),
+		SodiumMg:           fdaSodium(entry.Sodium * s),
+		TotalCarbohydrateG: fdaGrams(entry.Carbs * s),
+		DietaryFiberG:      fdaGrams(entry.Fiber * s),
+		TotalSugarsG:       fdaGrams(entry.Sugars * s),
+		AddedSugarsG:       fdaGrams(entry.AddedSugars * s),
+		ProteinG:           fdaGrams(entry.Protein * s),
+		VitaminDMcg:        roundTo(entry.VitaminD*s/iuPerMcgVitaminD, 0.1),
+		CalciumMg:          roundTo(entry.Calcium*s, 10),
+		IronMg:             roundTo(entry.Iron*s, 0.1),
+		PotassiumMg:        roundTo(entry.Potassium*s, 10),
+	}
+
+	amounts := map[string]float64{
+		"total_fat":          label.TotalFatG,
+		"saturated_fat":      label.SaturatedFatG,
+		"cholesterol":        label.CholesterolMg,
+		"sodium":             label.SodiumMg,
+		"total_carbohydrate": label.TotalCarbohydrateG,
+		"dietary_fiber":      label.DietaryFiberG,
+		"added_sugars":       label.AddedSugarsG,
+		"vitamin_d":          label.VitaminDMcg,
+		"calcium":            label.CalciumMg,
+		"iron":               label.IronMg,
+		"potassium":          label.PotassiumMg,
+	}
+	label.PercentDailyValue = make(map[string]float64, len(amounts))
+	for name, amount := range amounts {
+		label.PercentDailyValue[name] = math.Round(amount / fdaDailyValues[name] * 100)
+	}
+	return label
+}
+
+// uniqueFoodLabels builds one label per food in the diary, sorted by name.
+// Each food's entries in its most common unit are pooled, and the label's
+// serving is their average amount.
+func uniqueFoodLabels(diary []FoodEntry) []NutritionLabel {
+	byFood := make(map[string][]FoodEntry)
+	for _, e := range diary {
+		key := foodKey(e.FoodName)
+		byFood[key] = append(byFood[key], e)
+	}
+
+	labels := make([]NutritionLabel, 0, len(byFood))
+	for _, entries := range byFood {
+		counts := make(map[string]int)
+		for _, e := range entries {
+			counts[normalizeUnit(e.Unit)]++
+		}
+		unit := ""
+		for u, count := range counts {
+			if count > counts[unit] || (count == counts[unit] && u < unit) {
+				unit = u
+			}
+		}
+
+		pooled := FoodEntry{FoodName: entries[0].FoodName, Unit: unit}
+		n := 0
+		for _, e := range entries {
+			if normalizeUnit(e.Unit) != unit {
+				continue
+			}
+			n++
+			pooled.Amount += e.Amount
+			pooled.Calories += e.Calories
+			pooled.Fat += e.Fat
+			pooled.SaturatedFat += e.SaturatedFat
+			pooled.TransFat += e.TransFat
+			pooled.Cholesterol += e.Cholesterol
+			pooled.Sodium += e.Sodium
+			pooled.Carbs += e.Carbs
+			pooled.Fiber += e.Fiber
+			pooled.Sugars += e.Sugars
+			pooled.AddedSugars += e.AddedSugars
+			pooled.Protein += e.Protein
+			pooled.VitaminD += e.VitaminD
+			pooled.Calcium += e.Calcium
+			pooled.Iron += e.Iron
+			pooled.Potassium += e.Potassium
+		}
+		serving := pooled.Amount / float64(n)
+		if serving <= 0 {
+			// No amounts were logged, so label the average entry as one serving
+			pooled.Amount, serving, unit = float64(n), 1, "serving"
+			pooled.Unit = unit
+		}
+		labels = append(labels, GenerateNutritionLabel(pooled, serving, unit))
+	}
+	sort.Slice(labels, func(i, j int) bool {
+		return labels[i].FoodName < labels[j].FoodName
+	})
+	return labels
+}
+
+// writeNutritionLabels writes the diary's nutrition labels to path as a JSON array
+func writeNutritionLabels(diary []FoodEntry, path string) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := writeJSON(f, uniqueFoodLabels(diary)); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("closing %s: %v", path, err)
+	}
+	return nil
+}
@@ -0,0 +1,138 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// FlattenNutrition converts a record into a one-level object for Power BI.
+// Nested fields are joined to their parent key with underscores, e.g.
+// goals.protein.met becomes "goals_protein_met".
+func FlattenNutrition(d DailyNutrition) map[string]interface{} {
+	flat := make(map[string]interface{})
+
+	data, err := json.Marshal(d)
+	if err != nil {
+		return flat
+	}
+	var nested map[string]interface{}
+	if err := json.Unmarshal(data, &nested); err != nil {
+		return flat
+	}
+
+	flattenInto(flat, "", nested)
+	return flat
+}
+
+// flattenInto copies nested values into flat, prefixing keys with their parents
+func flattenInto(flat map[string]interface{}, prefix string, nested map[string]interface{}) {
+	for key, value := range nested {
+		if prefix != "" {
+			key = prefix + "_" + key
+		}
+		switch v := value.(type) {
+		case map[string]interface{}:
+			flattenInto(flat, key, v)
+		case []interface{}:
+			for i, item := range v {
+				itemKey := fmt.Sprintf("%s_%d", key, i)
+				if m, ok := item.(map[string]interface{}); ok {
+					flattenInto(flat, itemKey, m)
+				} else {
+					flat[itemKey] = item
+				}
+			}
+		default:
+			flat[key] = v
+		}
+	}
+}
+
+// powerBIRows flattens every record for the Power BI JSON connector
+func powerBIRows(records []DailyNutrition) []map[string]interface{} {
+	rows := make([]map[string]interface{}, len(records))
+	for i, d := range records {
+		rows[i] = FlattenNutrition(d)
+	}
+	return rows
+}
+
+// writeJSON writes v as indented JSON
+func writeJSON(w io.Writer, v interface{}) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return fmt.Errorf("converting to JSON: %v", err)
+	}
+	_, err = fmt.Fprintln(w, string(data))
+	return err
+}
+
+// jsonFormatter writes the account comparison, the days with their summary
+// when any summary statistic was requested, or else the bare array of days
+type jsonFormatter struct{}
+
+func (jsonFormatter) Format(w io.Writer, result Result) error {
+	switch {
+	case result.Comparison != nil:
+		return writeJSON(w, result.Comparison)
+	case !result.Summary.isEmpty():
+		return writeJSON(w, Report{Days: result.Days, Summary: result.Summary})
+	}
+	return writeJSON(w, result.Days)
+}
+
+// summaryFormatter writes only the summary object
+type summaryFormatter struct{}
+
+func (summaryFormatter) Format(w io.Writer, result Result) error {
+	return writeJSON(w, result.Summary)
+}
+
+// weeklyFormatter writes one object per ISO week instead of the days
+type weeklyFormatter struct{}
+
+func (weeklyFormatter) Format(w io.Writer, result Result) error {
+	return writeJSON(w, result.Weeks)
+}
+
+// powerBIFormatter writes the days as flat rows for Power BI
+type powerBIFormatter struct{}
+
+func (powerBIFormatter) Format(w io.Writer, result Result) error {
+	if result.Comparison != nil {
+		return writeJSON(w, result.Comparison)
+	}
+	return writeJSON(w, powerBIRows(result.Days))
+}
+
+// elasticsearchFormatter writes the days as an Elasticsearch _bulk payload
+type elasticsearchFormatter struct {
+	Index string
+}
+
+func (f elasticsearchFormatter) Format(w io.Writer, result Result) error {
+	if result.Comparison != nil {
+		return writeJSON(w, result.Comparison)
+	}
+	payload, err := ExportElasticsearch(result.Days, f.Index)
+	if err != nil {
+		return fmt.Errorf("building Elasticsearch payload: %v", err)
+	}
+	_, err = io.WriteString(w, payload)
+	return err
+}
+
+// tableFormatter writes the days as a text table. Comparisons have no table
+// layout and are written as JSON.
+type tableFormatter struct {
+	Color bool
+}
+
+func (f tableFormatter) Format(w io.Writer, result Result) error {
+	if result.Comparison != nil {
+		return writeJSON(w, result.Comparison)
+	}
+	writeTable(w, result.Days, f.Color)
+	return nil
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+)
+
+func TestSummaryOnlyOutputStructure(t *testing.T) {
+	days := []DailyNutrition{
+		{Date: "2024-03-01", Calories: 2000, Protein: 120},
+		{Date: "2024-03-02", Calories: 2200, Protein: 140},
+	}
+	tests := []struct {
+		name        string
+		summaryOnly bool
+		formatter   Formatter
+		wantObject  bool
+	}{
+		{"per-day output", false, jsonFormatter{}, false},
+		{"summary only", true, summaryFormatter{}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := &Pipeline{Config: Config{SummaryOnly: tt.summaryOnly}}
+			result := Result{Days: append([]DailyNutrition(nil), days...)}
+			if err := p.analyze(context.Background(), &result, nil, nil); err != nil {
+				t.Fatal(err)
+			}
+			var out bytes.Buffer
+			if err := tt.formatter.Format(&out, result); err != nil {
+				t.Fatal(err)
+			}
+
+			var decoded interface{}
+			if err := json.Unmarshal(out.Bytes(), &decoded); err != nil {
+				t.Fatalf("invalid JSON: %v\n%s", err, out.String())
+			}
+			switch v := decoded.(type) {
+			case []interface{}:
+				if tt.wantObject || len(v) != len(days) {
+					t.Errorf("got an array of %d days, want object %v", len(v), tt.wantObject)
+				}
+			case map[string]interface{}:
+				if !tt.wantObject {
+					t.Fatalf("got an object, want the per-day array")
+				}
+				if _, ok := v["days"]; ok {
+					t.Error("summary-only output includes the days")
+				}
+				if v["days_logged"] != 2.0 {
+					t.Errorf("days_logged = %v, want 2", v["days_logged"])
+				}
+				averages, _ := v["averages"].(map[string]interface{})
+				if averages["calories"] != 2100.0 {
+					t.Errorf("average calories = %v, want 2100", averages["calories"])
+				}
+			default:
+				t.Fatalf("unexpected JSON %T", decoded)
+			}
+		})
+	}
+}
+
+func TestNewPipelineSummaryOnlyFormatter(t *testing.T) {
+	// -store-goals returns before credentials are checked
+	p, err := NewPipeline(Config{OutputFormat: "json", SummaryOnly: true, StoreGoals: "cut"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := p.Formatter.(summaryFormatter); !ok {
+		t.Errorf("formatter = %T, want summaryFormatter", p.Formatter)
+	}
+}
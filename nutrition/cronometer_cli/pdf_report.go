@@ -0,0 +1,333 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/go-pdf/fpdf"
+)
+
+// PDF report layout in millimetres on A4 portrait
+const (
+	reportMargin      = 20.0
+	reportChartWidth  = 170.0
+	reportChartHeight = 70.0
+	reportPieRadius   = 30.0
+)
+
+// rgb is a PDF draw color
+type rgb struct{ r, g, b int }
+
+// Report chart colors, matching the SVG chart palette
+var (
+	reportCaloriesColor = rgb{31, 119, 180}
+	reportGoalColor     = rgb{214, 39, 40}
+	reportAxisColor     = rgb{120, 120, 120}
+	macroColors         = map[string]rgb{
+		"protein": {44, 160, 44},
+		"carbs":   {31, 119, 180},
+		"fat":     {255, 127, 14},
+	}
+)
+
+// reportMacros are the pie chart wedges in drawing order, with kcal per gram
+var reportMacros = []struct {
+	name     string
+	kcalPerG float64
+}{
+	{"protein", 4},
+	{"carbs", 4},
+	{"fat", 9},
+}
+
+// PersonalBest is the standout day for one nutrient during the month
+type PersonalBest struct {
+	Label string
+	Date  string
+	Value string
+}
+
+// monthRecords returns the records dated in the given month, sorted by date
+func monthRecords(records []DailyNutrition, month time.Month, year int) []DailyNutrition {
+	var days []DailyNutrition
+	for _, d := range records {
+		date, err := time.Parse("2006-01-02", d.Date)
+		if err != nil || date.Month() != month || date.Year() != year {
+			continue
+		}
+		days = append(days, d)
+	}
+	sort.Slice(days, func(i, j int) bool {
+		return days[i].Date < days[j].Date
+	})
+	return days
+}
+
+// svgDocument wraps path data in a basic SVG document, one path per element
+func svgDocument(width, height float64, paths ...string) []byte {
+	var b strings.Builder
+	fmt.Fprintf(&b, `<svg xmlns="http://www.w3.org/2000/svg" width="%g" height="%g">`, width, height)
+	for _, d := range paths {
+		fmt.Fprintf(&b, `<path d="%s"/>`, d)
+	}
+	b.WriteString("</svg>")
+	return []byte(b.String())
+}
+
+// rectPath returns a closed SVG path outlining a rectangle
+func rectPath(x, y, width, height float64) string {
+	return fmt.Sprintf("M %.2f %.2f L %.2f %.2f L %.2f %.2f L %.2f %.2f Z",
+		x, y, x+width, y, x+width, y+height, x, y+height)
+}
+
+// wedgePath returns a closed SVG pie wedge from angle a0 to a1 (radians),
+// approximating the arc with one cubic Bézier per quarter turn
+func wedgePath(cx, cy, r, a0, a1 float64) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "M %.2f %.2f L %.2f %.2f", cx, cy, cx+r*math.Cos(a0), cy+r*math.Sin(a0))
+	segments := int(math.Ceil((a1 - a0) / (math.Pi / 2)))
+	step := (a1 - a0) / float64(segments)
+	k := 4.0 / 3 * math.Tan(step/4)
+	for i := 0; i < segments; i++ {
+		s := a0 + float64(i)*step
+		e := s + step
+		fmt.Fprintf(&b, " C %.2f %.2f %.2f %.2f %.2f %.2f",
+			cx+r*(math.Cos(s)-k*math.Sin(s)), cy+r*(math.Sin(s)+k*math.Cos(s)),
+			cx+r*(math.Cos(e)+k*math.Sin(e)), cy+r*(math.Sin(e)-k*math.Cos(e)),
+			cx+r*math.Cos(e), cy+r*math.Sin(e))
+	}
+	b.WriteString(" Z")
+	return b.String()
+}
+
+// drawSVG parses a basic SVG and strokes its paths at (x, y) in color, one
+// SVG unit per millimetre
+func drawSVG(pdf *fpdf.Fpdf, svg []byte, x, y float64, color rgb) error {
+	sig, err := fpdf.SVGBasicParse(svg)
+	if err != nil {
+		return fmt.Errorf("parsing chart SVG: %v", err)
+	}
+	pdf.SetDrawColor(color.r, color.g, color.b)
+	pdf.SetXY(x, y)
+	pdf.SVGBasicWrite(&sig, 1)
+	return nil
+}
+
+// calorieChart draws a bar per day with the calorie goal, if any, as a line
+func calorieChart(pdf *fpdf.Fpdf, days []DailyNutrition, goal float64, x, y float64) error {
+	top := goal
+	for _, d := range days {
+		top = math.Max(top, d.Calories)
+	}
+	if top <= 0 {
+		top = 1
+	}
+	top *= 1.1
+
+	slot := reportChartWidth / float64(len(days))
+	var bars []string
+	for i, d := range days {
+		height := d.Calories / top * reportChartHeight
+		bars = append(bars, rectPath(float64(i)*slot+slot*0.15, reportChartHeight-height, slot*0.7, height))
+	}
+	axes := fmt.Sprintf("M 0 0 L 0 %[2]g L %[1]g %[2]g", reportChartWidth, reportChartHeight)
+
+	if err := drawSVG(pdf, svgDocument(reportChartWidth, reportChartHeight, axes), x, y, reportAxisColor); err != nil {
+		return err
+	}
+	if err := drawSVG(pdf, svgDocument(reportChartWidth, reportChartHeight, bars...), x, y, reportCaloriesColor); err != nil {
+		return err
+	}
+	if goal > 0 {
+		goalY := reportChartHeight - goal/top*reportChartHeight
+		line := fmt.Sprintf("M 0 %.2f L %g %.2f", goalY, reportChartWidth, goalY)
+		if err := drawSVG(pdf, svgDocument(reportChartWidth, reportChartHeight, line), x, y, reportGoalColor); err != nil {
+			return err
+		}
+	}
+
+	pdf.SetFont("Helvetica", "", 8)
+	pdf.SetXY(x-12, y-2)
+	pdf.CellFormat(10, 4, fmt.Sprintf("%.0f", top), "", 0, "R", false, 0, "")
+	pdf.SetXY(x-12, y+reportChartHeight-2)
+	pdf.CellFormat(10, 4, "0", "", 0, "R", false, 0, "")
+	pdf.SetXY(x, y+reportChartHeight+1)
+	pdf.CellFormat(reportChartWidth/2, 4, days[0].Date, "", 0, "L", false, 0, "")
+	pdf.CellFormat(reportChartWidth/2, 4, days[len(days)-1].Date, "", 0, "R", false, 0, "")
+	return nil
+}
+
+// macroPie draws the share of average calories from each macro, with a legend
+func macroPie(pdf *fpdf.Fpdf, averages map[string]float64, x, y float64) error {
+	kcal := make(map[string]float64, len(reportMacros))
+	var total float64
+	for _, m := range reportMacros {
+		kcal[m.name] = averages[m.name] * m.kcalPerG
+		total += kcal[m.name]
+	}
+	if total <= 0 {
+		return nil
+	}
+
+	size := 2 * reportPieRadius
+	angle := -math.Pi / 2
+	pdf.SetLineWidth(0.8)
+	for i, m := range reportMacros {
+		share := kcal[m.name] / total
+		if share > 0 {
+			wedge := wedgePath(reportPieRadius, reportPieRadius, reportPieRadius, angle, angle+share*2*math.Pi)
+			if err := drawSVG(pdf, svgDocument(size, size, wedge), x, y, macroColors[m.name]); err != nil {
+				return err
+			}
+			angle += share * 2 * math.Pi
+		}
+
+		color := macroColors[m.name]
+		pdf.SetFillColor(color.r, color.g, color.b)
+		legendY := y + 10 + float64(i)*8
+		pdf.Rect(x+size+15, legendY, 4, 4, "F")
+		pdf.SetXY(x+size+21, legendY)
+		pdf.SetFont("Helvetica", "", 11)
+		pdf.CellFormat(60, 4, fmt.Sprintf("%s: %.0f%% (%.0f g/day)", m.name, share*100, averages[m.name]), "", 0, "L", false, 0, "")
+	}
+	pdf.SetLineWidth(0.2)
+	return nil
+}
+
+// personalBests returns the month's highest protein and fiber days, lowest
+// sodium day, and longest streak of meeting every goal
+func personalBests(days []DailyNutrition, goals map[string]float64) []PersonalBest {
+	var bests []PersonalBest
+	best := func(label, unit string, value func(DailyNutrition) float64, lower bool) {
+		index := -1
+		for i, d := range days {
+			v := value(d)
+			if v <= 0 {
+				continue
+			}
+			if index == -1 || (lower && v < value(days[index])) || (!lower && v > value(days[index])) {
+				index = i
+			}
+		}
+		if index != -1 {
+			bests = append(bests, PersonalBest{Label: label, Date: days[index].Date, Value: fmt.Sprintf("%.0f %s", value(days[index]), unit)})
+		}
+	}
+	best("Most protein", "g", func(d DailyNutrition) float64 { return d.Protein }, false)
+	best("Most fiber", "g", func(d DailyNutrition) float64 { return d.Fiber }, false)
+	best("Least sodium", "mg", func(d DailyNutrition) float64 { return d.Sodium }, true)
+	if len(goals) > 0 {
+		if _, longest := GoalAdherenceStreak(days, goals, ""); longest > 0 {
+			bests = append(bests, PersonalBest{Label: "Longest all-goals streak", Value: fmt.Sprintf("%d days", longest)})
+		}
+	}
+	return bests
+}
+
+// tableRow writes one row of bordered cells with the given widths
+func tableRow(pdf *fpdf.Fpdf, widths []float64, cells []string, bold bool) {
+	style := ""
+	if bold {
+		style = "B"
+	}
+	pdf.SetFont("Helvetica", style, 11)
+	for i, cell := range cells {
+		pdf.CellFormat(widths[i], 8, cell, "1", 0, "L", false, 0, "")
+	}
+	pdf.Ln(-1)
+}
+
+// GenerateMonthlyReport writes a PDF report for one month of records: a cover
+// page with summary statistics, a daily calorie chart and macro distribution
+// pie chart drawn from embedded SVG, a goal adherence table, and personal bests
+func GenerateMonthlyReport(records []DailyNutrition, goals map[string]float64, month time.Month, year int, outputPath string) error {
+	days := monthRecords(records, month, year)
+	if len(days) == 0 {
+		return fmt.Errorf("no days logged in %s %d", month, year)
+	}
+	averages := averageNutrients(days)
+
+	pdf := fpdf.New("P", "mm", "A4", "")
+	pdf.SetMargins(reportMargin, reportMargin, reportMargin)
+	pdf.SetTitle(fmt.Sprintf("Nutrition report %s %d", month, year), false)
+
+	// Cover page
+	pdf.AddPage()
+	pdf.SetFont("Helvetica", "B", 28)
+	pdf.Ln(40)
+	pdf.CellFormat(0, 14, "Nutrition Report", "", 1, "C", false, 0, "")
+	pdf.SetFont("Helvetica", "", 18)
+	pdf.CellFormat(0, 10, fmt.Sprintf("%s %d", month, year), "", 1, "C", false, 0, "")
+	pdf.Ln(20)
+	widths := []float64{85, 85}
+	tableRow(pdf, widths, []string{"Statistic", "Daily average"}, true)
+	tableRow(pdf, widths, []string{"Days logged", fmt.Sprintf("%d", len(days))}, false)
+	for _, name := range summaryNutrients {
+		unit := "g"
+		switch name {
+		case "calories":
+			unit = "kcal"
+		case "sodium":
+			unit = "mg"
+		}
+		tableRow(pdf, widths, []string{name, fmt.Sprintf("%.0f %s", averages[name], unit)}, false)
+	}
+
+	// Charts
+	pdf.AddPage()
+	pdf.SetFont("Helvetica", "B", 16)
+	pdf.CellFormat(0, 10, "Daily calories", "", 1, "L", false, 0, "")
+	chartY := pdf.GetY() + 4
+	if err := calorieChart(pdf, days, goals["calories"], reportMargin, chartY); err != nil {
+		return err
+	}
+	pdf.SetXY(reportMargin, chartY+reportChartHeight+15)
+	pdf.SetFont("Helvetica", "B", 16)
+	pdf.CellFormat(0, 10, "Macro distribution (share of calories)", "", 1, "L", false, 0, "")
+	if err := macroPie(pdf, averages, reportMargin, pdf.GetY()+4); err != nil {
+		return err
+	}
+
+	// Goal adherence and personal bests
+	pdf.AddPage()
+	pdf.SetFont("Helvetica", "B", 16)
+	pdf.CellFormat(0, 10, "Goal adherence", "", 1, "L", false, 0, "")
+	if len(goals) == 0 {
+		pdf.SetFont("Helvetica", "", 11)
+		pdf.CellFormat(0, 8, "No goals were set.", "", 1, "L", false, 0, "")
+	} else {
+		widths := []float64{50, 40, 40, 40}
+		tableRow(pdf, widths, []string{"Goal", "Target", "Days met", "Adherence"}, true)
+		for _, name := range goalNames(goals) {
+			met := 0
+			for _, d := range days {
+				if dayMeetsGoal(d, name, goals[name]) {
+					met++
+				}
+			}
+			tableRow(pdf, widths, []string{
+				name,
+				fmt.Sprintf("%g", goals[name]),
+				fmt.Sprintf("%d / %d", met, len(days)),
+				fmt.Sprintf("%.0f%%", float64(met)/float64(len(days))*100),
+			}, false)
+		}
+	}
+
+	pdf.Ln(10)
+	pdf.SetFont("Helvetica", "B", 16)
+	pdf.CellFormat(0, 10, "Personal bests", "", 1, "L", false, 0, "")
+	widths = []float64{70, 50, 50}
+	tableRow(pdf, widths, []string{"Record", "Date", "Value"}, true)
+	for _, best := range personalBests(days, goals) {
+		tableRow(pdf, widths, []string{best.Label, best.Date, best.Value}, false)
+	}
+
+	if err := pdf.OutputFileAndClose(outputPath); err != nil {
+		return fmt.Errorf("writing %s: %v", outputPath, err)
+	}
+	return nil
+}
@@ -0,0 +1,100 @@
+package main
+
+// pcLowMg is the daily phosphatidylcholine estimate below which a day is
+// flagged, an approximate minimum for liver health
+const pcLowMg = 500.0
+
+// pcPerServing is the approximate phosphatidylcholine, in mg, in one serving
+// of common foods, estimated from USDA choline data with most food choline
+// bound as PC. The serving sizes, in grams, are in pcServingGrams. Keys are
+// matched as substrings of lowercased diary food names.
+var pcPerServing = map[string]float64{
+	"egg":            800,
+	"egg white":      0,
+	"eggplant":       0,
+	"liver":          450,
+	"soybean":        200,
+	"edamame":        200,
+	"tofu":           120,
+	"salmon":         200,
+	"shrimp":         150,
+	"chicken":        150,
+	"turkey":         130,
+	"beef":           120,
+	"pork":           110,
+	"wheat germ":     100,
+	"peanut":         60,
+	"milk":           30,
+	"soy milk":       40,
+	"almond milk":    0,
+	"oat milk":       0,
+	"sunflower seed": 50,
+}
+
+// pcServingGrams is the serving size, in grams, for each pcPerServing food
+var pcServingGrams = map[string]float64{
+	"egg":            50,
+	"egg white":      33,
+	"eggplant":       82,
+	"liver":          28,
+	"soybean":        86,
+	"edamame":        78,
+	"tofu":           126,
+	"salmon":         85,
+	"shrimp":         85,
+	"chicken":        140,
+	"turkey":         85,
+	"beef":           85,
+	"pork":           85,
+	"wheat germ":     15,
+	"peanut":         28,
+	"milk":           244,
+	"soy milk":       243,
+	"almond milk":    240,
+	"oat milk":       240,
+	"sunflower seed": 28,
+}
+
+// pcServings returns how many servings of the food an entry is: its weight
+// over the serving size, or its amount when it wasn't logged by weight (e.g.
+// "2 large"), or 1 when no amount was logged
+func pcServings(e FoodEntry, servingGrams float64) float64 {
+	if grams, ok := entryGrams(e); ok && servingGrams > 0 {
+		return grams / servingGrams
+	}
+	if e.Amount > 0 {
+		return e.Amount
+	}
+	return 1
+}
+
+// EstimatePhosphatidylcholine estimates phosphatidylcholine in mg from the
+// entries whose food is in pcTable, given in mg per serving
+func EstimatePhosphatidylcholine(entries []FoodEntry, pcTable map[string]float64) float64 {
+	var total float64
+	for _, e := range entries {
+		mg, ok := tableMatch(e.FoodName, pcTable)
+		if !ok {
+			continue
+		}
+		servingGrams, _ := tableMatch(e.FoodName, pcServingGrams)
+		total += mg * pcServings(e, servingGrams)
+	}
+	return total
+}
+
+// PCReport is a day's estimated phosphatidylcholine intake
+type PCReport struct {
+	EstimateMg float64 `json:"estimate_mg"`
+	Low        bool    `json:"low"`
+}
+
+// applyPhosphatidylcholine estimates each day's phosphatidylcholine from its
+// diary and flags days below 500mg
+func applyPhosphatidylcholine(records []DailyNutrition, diary []FoodEntry) {
+	byDate := groupEntriesByDate(diary)
+	for i := range records {
+		mg := EstimatePhosphatidylcholine(byDate[records[i].Date], pcPerServing)
+		records[i].PC = &PCReport{EstimateMg: mg, Low: mg < pcLowMg}
+	}
+}
@@ -0,0 +1,68 @@
+package main
+
+// psLowMg is the daily phosphatidylserine estimate below which a day is flagged
+const psLowMg = 100.0
+
+// psContent is the approximate phosphatidylserine content, in mg per gram, of
+// common foods (Souci et al., Food Composition and Nutrition Tables). Keys are
+// matched as substrings of lowercased diary food names.
+var psContent = map[string]float64{
+	"mackerel":      4.80,
+	"herring":       3.60,
+	"eel":           3.35,
+	"cod":           2.88,
+	"tuna":          1.94,
+	"sardine":       1.60,
+	"trout":         1.15,
+	"anchovy":       0.90,
+	"chicken":       0.85,
+	"chicken liver": 1.11,
+	"chicken heart": 4.14,
+	"turkey":        0.70,
+	"veal":          0.72,
+	"beef":          0.69,
+	"pork":          0.57,
+	"lamb":          0.50,
+	"white bean":    1.07,
+	"soy":           0.59,
+	"egg":           0.03,
+	"milk":          0.01,
+	"cheese":        0.02,
+	"yogurt":        0.01,
+	// Plant milks, so they don't match "milk"
+	"soy milk":    0.05,
+	"almond milk": 0,
+	"oat milk":    0,
+}
+
+// PSReport is a day's estimated phosphatidylserine intake
+type PSReport struct {
+	EstimateMg float64 `json:"estimate_mg"`
+	Low        bool    `json:"low"`
+}
+
+// EstimatePhosphatidylserine estimates phosphatidylserine in mg from entries
+// whose food is in psTable and whose amount is logged by weight
+func EstimatePhosphatidylserine(entries []FoodEntry, psTable map[string]float64) float64 {
+	var total float64
+	for _, e := range entries {
+		mgPerG, ok := tableMatch(e.FoodName, psTable)
+		if !ok {
+			continue
+		}
+		if grams, ok := entryGrams(e); ok {
+			total += mgPerG * grams
+		}
+	}
+	return total
+}
+
+// applyPhosphatidylserine estimates each day's phosphatidylserine from its
+// diary and flags days below 100mg
+func applyPhosphatidylserine(records []DailyNutrition, diary []FoodEntry) {
+	byDate := groupEntriesByDate(diary)
+	for i := range records {
+		mg := EstimatePhosphatidylserine(byDate[records[i].Date], psContent)
+		records[i].PS = &PSReport{EstimateMg: mg, Low: mg < psLowMg}
+	}
+}
@@ -0,0 +1,94 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+)
+
+// varietyTrendThreshold is how much a week's average plant variety must
+// change from the week before to count as a trend
+const varietyTrendThreshold = 1.0
+
+// phytochemicalKeywords are lowercase name fragments for plant foods, used
+// when -plant-keywords is not given
+var phytochemicalKeywords = append([]string{
+	"apple", "banana", "berry", "berries", "cherry", "grape", "orange", "lemon",
+	"lime", "mango", "pear", "peach", "plum", "melon", "pineapple", "kiwi",
+	"avocado", "mushroom", "garlic", "beet", "cauliflower", "celery", "cucumber",
+	"zucchini", "eggplant", "asparagus", "potato", "corn", "oat", "quinoa",
+	"barley", "brown rice", "buckwheat", "almond", "walnut", "cashew", "pecan",
+	"pistachio", "peanut", "seed", "flax", "chia", "olive", "herb", "basil",
+	"parsley", "cilantro", "ginger", "turmeric", "cinnamon", "cocoa", "tea",
+}, plantKeywords...)
+
+// DayVariety is how many distinct plant foods were logged on one day
+type DayVariety struct {
+	Date  string `json:"date"`
+	Score int    `json:"score"`
+}
+
+// PhytochemicalReport estimates phytochemical exposure from plant food variety
+type PhytochemicalReport struct {
+	Days        []DayVariety `json:"days"`
+	WeeklyTrend []WeekScore  `json:"weekly_trend"`
+}
+
+// loadPlantKeywords reads one keyword per line, skipping blank lines and # comments
+func loadPlantKeywords(path string) ([]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var keywords []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.ToLower(strings.TrimSpace(scanner.Text()))
+		if line != "" && !strings.HasPrefix(line, "#") {
+			keywords = append(keywords, line)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if len(keywords) == 0 {
+		return nil, fmt.Errorf("%s has no keywords", path)
+	}
+	return keywords, nil
+}
+
+// PlantFoodVarietyScore counts the distinct plant foods among the entries,
+// matching food names against plantKeywords
+func PlantFoodVarietyScore(entries []FoodEntry, plantKeywords []string) int {
+	seen := make(map[string]bool)
+	for _, e := range entries {
+		if containsAny(e.FoodName, plantKeywords) {
+			seen[foodKey(e.FoodName)] = true
+		}
+	}
+	return len(seen)
+}
+
+// phytochemicalReport scores each diary day's plant variety, sorted by date,
+// and averages the scores per ISO week
+func phytochemicalReport(diary []FoodEntry, plantKeywords []string) *PhytochemicalReport {
+	report := &PhytochemicalReport{Days: []DayVariety{}}
+	for date, entries := range groupEntriesByDate(diary) {
+		report.Days = append(report.Days, DayVariety{Date: date, Score: PlantFoodVarietyScore(entries, plantKeywords)})
+	}
+	sort.Slice(report.Days, func(i, j int) bool {
+		return report.Days[i].Date < report.Days[j].Date
+	})
+
+	dates := make([]string, len(report.Days))
+	scores := make([]float64, len(report.Days))
+	for i, day := range report.Days {
+		dates[i], scores[i] = day.Date, float64(day.Score)
+	}
+	report.WeeklyTrend = weeklyScoreTrend(dates, scores, varietyTrendThreshold)
+	return report
+}
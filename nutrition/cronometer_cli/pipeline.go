@@ -0,0 +1,1405 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/jrmycanady/gocronometer"
+)
+
+// errMissingCredentials is returned by NewPipeline when a run needs to log in without credentials
+var errMissingCredentials = errors.New("username and password are required")
+
+// Client is the Cronometer API used by the pipeline; *gocronometer.Client satisfies it
+type Client interface {
+	ExportDailyNutrition(ctx context.Context, start, end time.Time) (string, error)
+	ExportServingsParsedWithLocation(ctx context.Context, start, end time.Time, location *time.Location) (gocronometer.ServingRecords, error)
+	ExportBiometricRecordsParsedWithLocation(ctx context.Context, start, end time.Time, location *time.Location) (gocronometer.BiometricRecords, error)
+	ExportNotes(ctx context.Context, start, end time.Time) (string, error)
+	ExportExercisesParsedWithLocation(ctx context.Context, start, end time.Time, location *time.Location) (gocronometer.ExerciseRecords, error)
+}
+
+// Storage persists goal sets and local foods between runs
+type Storage interface {
+	LoadGoals(name string) (map[string]float64, error)
+	StoreGoals(name string, goals map[string]float64) error
+	LoadFoodDatabase() (FoodDatabase, error)
+	AddLocalFood(food LocalFood) error
+}
+
+// Parser turns a daily nutrition export into records
+type Parser interface {
+	ParseDailyNutrition(csvData string) ([]DailyNutrition, error)
+}
+
+// Formatter writes the finished records to the output
+type Formatter interface {
+	Format(w io.Writer, result Result) error
+}
+
+// Result is everything a run produces for the formatter
+type Result struct {
+	Days       []DailyNutrition
+	Summary    Summary
+	Comparison []AccountComparison
+	Weeks      []WeekSummary
+}
+
+// Pipeline fetches, analyzes, and formats nutrition data. Client and Other
+// are logged in by Run when left nil; Other is only used with -diff-account.
+type Pipeline struct {
+	Config    Config
+	Client    Client
+	Other     Client
+	Storage   Storage
+	Parser    Parser
+	Formatter Formatter
+
+	// Values parsed from the config by NewPipeline
+	start          time.Time
+	end            time.Time
+	goals          map[string]float64
+	accounts       []accountCredentials
+	excluded       []string
+	clampBounds    map[string]float64
+	dietBreaks     dietBreakOptions
+	mfp            mfpImport
+	magnesiumSex   string
+	b12Pattern     string
+	protocol       FastingProtocol
+	population     map[string]populationStat
+	mealsRemaining int
+	swap           foodSwap
+	macroTarget    MacroSplit
+	categoryBudget map[string]float64
+	reportMonth    time.Time
+}
+
+// fileStorage keeps goal sets and local foods as JSON files in ~/.config/cronometer_cli
+type fileStorage struct{}
+
+func (fileStorage) LoadGoals(name string) (map[string]float64, error) {
+	path, err := goalSetsPath()
+	if err != nil {
+		return nil, err
+	}
+	return LoadGoals(path, name)
+}
+
+func (fileStorage) StoreGoals(name string, goals map[string]float64) error {
+	path, err := goalSetsPath()
+	if err != nil {
+		return err
+	}
+	return StoreGoals(path, name, goals)
+}
+
+func (fileStorage) LoadFoodDatabase() (FoodDatabase, error) {
+	path, err := foodDatabasePath()
+	if err != nil {
+		return FoodDatabase{}, err
+	}
+	return loadFoodDatabase(path)
+}
+
+func (fileStorage) AddLocalFood(food LocalFood) error {
+	path, err := foodDatabasePath()
+	if err != nil {
+		return err
+	}
+	return AddLocalFood(path, food)
+}
+
+// csvParser parses Cronometer's daily nutrition CSV export
+type csvParser struct{}
+
+func (csvParser) ParseDailyNutrition(csvData string) ([]DailyNutrition, error) {
+	return parseDailyNutrition(csvData)
+}
+
+// NewPipeline validates the config and builds a pipeline with the default
+// file storage, CSV parser, and the formatter for cfg.OutputFormat
+func NewPipeline(cfg Config) (*Pipeline, error) {
+	p := &Pipeline{Config: cfg, Storage: fileStorage{}, Parser: csvParser{}}
+
+	switch cfg.OutputFormat {
+	case "json":
+		p.Formatter = jsonFormatter{}
+		if cfg.SummaryOnly {
+			p.Formatter = summaryFormatter{}
+		}
+		if cfg.WeeklyTrend {
+			p.Formatter = weeklyFormatter{}
+		}
+	case "powerbi":
+		p.Formatter = powerBIFormatter{}
+	case "table":
+		p.Formatter = tableFormatter{Color: cfg.Color && isTerminal(os.Stdout)}
+	case "elasticsearch":
+		if cfg.ESIndex == "" {
+			return nil, fmt.Errorf("-output elasticsearch requires -es-index")
+		}
+		p.Formatter = elasticsearchFormatter{Index: cfg.ESIndex}
+		// Document ids and @timestamp need ISO dates, so -format-date doesn't apply
+		cfg.DateLayout = "2006-01-02"
+		p.Config = cfg
+	default:
+		return nil, fmt.Errorf("unknown -output %q (expected json, powerbi, table, or elasticsearch)", cfg.OutputFormat)
+	}
+
+	// Combine a stored goal set with explicit goal flags
+	p.goals = cfg.Goals
+	if cfg.LoadGoals != "" {
+		loaded, err := p.Storage.LoadGoals(cfg.LoadGoals)
+		if err != nil {
+			return nil, fmt.Errorf("loading goal set: %v", err)
+		}
+		p.goals = mergeGoals(loaded, cfg.ExplicitGoals)
+	}
+
+	// Saving goals, adding a local food, writing the schema, linting saved
+	// outputs, and estimating BMR don't log in
+	if cfg.StoreGoals != "" || cfg.AddLocalFood != "" || cfg.ExportReadme || cfg.BatchValidate != "" || cfg.ComputeBMR {
+		return p, nil
+	}
+
+	// Read the credentials from Vault
+	if cfg.VaultAddr != "" {
+		if cfg.VaultPath == "" {
+			return nil, fmt.Errorf("-vault-addr requires -vault-path")
+		}
+		account, err := fetchVaultCredentials(context.Background(), cfg.VaultAddr, cfg.VaultPath, os.Getenv("VAULT_TOKEN"), cfg.Timeout)
+		switch {
+		case err == nil:
+			cfg.Username, cfg.Password = account.Username, account.Password
+			p.Config = cfg
+		case cfg.VaultFallback:
+			fmt.Fprintf(os.Stderr, "Vault unavailable, using -username and -password: %v\n", err)
+		default:
+			return nil, fmt.Errorf("reading credentials from Vault: %v", err)
+		}
+	}
+
+	if cfg.Username == "" || cfg.Password == "" {
+		return nil, errMissingCredentials
+	}
+	if err := p.validate(); err != nil {
+		return nil, err
+	}
+	if err := p.parseOptions(); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// validate checks option values and combinations
+func (p *Pipeline) validate() error {
+	cfg := p.Config
+	if err := validateDateLayout(cfg.DateLayout); err != nil {
+		return fmt.Errorf("invalid -format-date: %v", err)
+	}
+	if cfg.SummaryOnly && (cfg.OutputFormat != "json" || cfg.DiffAccount != "") {
+		return fmt.Errorf("-summary-only only applies to json output without -diff-account")
+	}
+	if cfg.WeeklyTrend && (cfg.OutputFormat != "json" || cfg.DiffAccount != "" || cfg.SummaryOnly) {
+		return fmt.Errorf("-weekly-trend-summary only applies to json output without -diff-account or -summary-only")
+	}
+	if cfg.ChunkStrategy != "date" && cfg.ChunkStrategy != "type" {
+		return fmt.Errorf("unknown -chunk-strategy %q (expected date or type)", cfg.ChunkStrategy)
+	}
+	if cfg.ChunkStrategy == "type" && cfg.ChunkDays > 0 {
+		return fmt.Errorf("-chunk-days only applies to -chunk-strategy date")
+	}
+
+	requiresGoals := map[string]bool{
+		"-adherence-trend":       cfg.AdherenceTrend,
+		"-goal-adherence-streak": cfg.AdherenceStreak,
+		"-goal-timeline":         cfg.GoalTimeline,
+		"-suggest":               cfg.Suggest,
+		"-advise":                cfg.Advise != "",
+	}
+	for name, requested := range requiresGoals {
+		if requested && len(p.goals) == 0 {
+			return fmt.Errorf("%s requires at least one -goal-* flag", name)
+		}
+	}
+
+	if cfg.CI != 0 {
+		if err := validateConfidence(cfg.CI); err != nil {
+			return fmt.Errorf("invalid -ci: %v", err)
+		}
+		if cfg.Rolling <= 0 {
+			return fmt.Errorf("-ci requires -rolling")
+		}
+	}
+	if cfg.Rebalance && (cfg.WeeklyBudget <= 0 || cfg.DaysLeft < 1) {
+		return fmt.Errorf("-rebalance requires -weekly-budget and -days-left of at least 1")
+	}
+	if cfg.CheckVitaminD && (cfg.Latitude == nil || *cfg.Latitude < -90 || *cfg.Latitude > 90) {
+		return fmt.Errorf("-check-vitamin-d requires -latitude between -90 and 90")
+	}
+	if cfg.SkipToday && cfg.Advise != "" {
+		return fmt.Errorf("-advise plans the rest of today and cannot be combined with -skip-today")
+	}
+	if cfg.GoalTimeline && (cfg.TargetAdherence <= 0 || cfg.TargetAdherence > 1) {
+		return fmt.Errorf("-target-adherence must be greater than 0 and at most 1")
+	}
+	if cfg.DetectStressEating && len(parseTags(cfg.StressTags)) == 0 {
+		return fmt.Errorf("-detect-stress-eating requires at least one -stress-tags word")
+	}
+	if cfg.CheckTryptophan && len(parseTags(cfg.PositiveMoodTags)) == 0 {
+		return fmt.Errorf("-check-tryptophan requires at least one -positive-mood-tags word")
+	}
+	if cfg.WeightChart != "" && (cfg.SmoothBandwidth <= 0 || cfg.SmoothBandwidth > 1) {
+		return fmt.Errorf("-smooth-bandwidth must be greater than 0 and at most 1")
+	}
+	if cfg.PredictNextWeek && (cfg.Alpha <= 0 || cfg.Alpha > 1) {
+		return fmt.Errorf("-alpha must be greater than 0 and at most 1")
+	}
+	if cfg.NightEating && (cfg.CutoffHour < 0 || cfg.CutoffHour > 23) {
+		return fmt.Errorf("-cutoff-hour must be between 0 and 23")
+	}
+	if cfg.MealSpacing && (cfg.MinGapHours < 0 || cfg.MaxGapHours <= cfg.MinGapHours) {
+		return fmt.Errorf("-max-gap-hours must be greater than -min-gap-hours, and both non-negative")
+	}
+	if cfg.ProteinDistribution && cfg.WeightKg <= 0 {
+		return fmt.Errorf("-protein-distribution requires -weight-kg")
+	}
+	if cfg.TargetWeightLbs > 0 && cfg.TDEE <= 0 {
+		return fmt.Errorf("-target-weight-lbs requires -tdee")
+	}
+	if cfg.CompareBMR && (cfg.HeightCm <= 0 || cfg.Age <= 0 || (cfg.Sex != "male" && cfg.Sex != "female")) {
+		return fmt.Errorf("-compare-bmr requires -height-cm, -age, and -sex")
+	}
+	if cfg.VO2Max < 0 {
+		return fmt.Errorf("-vo2max must not be negative")
+	}
+	if cfg.KidneyStoneRisk && !cfg.TrackHydration {
+		return fmt.Errorf("-check-kidney-stone-risk requires -track-hydration")
+	}
+	return nil
+}
+
+// parseOptions parses the dates, accounts, and structured option values
+func (p *Pipeline) parseOptions() error {
+	cfg := p.Config
+	var err error
+
+	if p.excluded, err = parseDateList(cfg.ExcludeDates); err != nil {
+		return fmt.Errorf("invalid -exclude-dates: %v", err)
+	}
+	if cfg.Clamp != "" {
+		if p.clampBounds, err = parseClamp(cfg.Clamp); err != nil {
+			return fmt.Errorf("invalid -clamp: %v", err)
+		}
+	}
+	if cfg.DetectDietBreaks != "" {
+		if p.dietBreaks, err = parseDietBreaks(cfg.DetectDietBreaks); err != nil {
+			return fmt.Errorf("invalid -detect-diet-breaks: %v", err)
+		}
+	}
+	if cfg.CheckMagnesium != "" {
+		if p.magnesiumSex, err = parseMagnesiumSex(cfg.CheckMagnesium); err != nil {
+			return fmt.Errorf("invalid -check-magnesium: %v", err)
+		}
+	}
+	if cfg.CheckCholine {
+		if err := parseCholineSex(cfg.Sex); err != nil {
+			return err
+		}
+	}
+	if cfg.CompareToPopulation {
+		if p.population, err = nhanesGroup(cfg.Age, cfg.Sex); err != nil {
+			return fmt.Errorf("invalid -compare-to-population: %v", err)
+		}
+	}
+	if cfg.FastingProtocol != "" {
+		if p.protocol, err = parseFastingProtocol(cfg.FastingProtocol); err != nil {
+			return fmt.Errorf("invalid -fasting-protocol: %v", err)
+		}
+	}
+	if cfg.CheckB12 != "" {
+		if p.b12Pattern, err = parseB12Pattern(cfg.CheckB12); err != nil {
+			return fmt.Errorf("invalid -check-b12: %v", err)
+		}
+	}
+	if cfg.ImportMFP != "" {
+		if p.mfp, err = parseMFPImport(cfg.ImportMFP); err != nil {
+			return fmt.Errorf("invalid -import-mfp: %v", err)
+		}
+	}
+	if cfg.MacroRatios != "" {
+		if p.macroTarget, err = parseMacroSplit(cfg.MacroRatios); err != nil {
+			return fmt.Errorf("invalid -compare-macro-ratios: %v", err)
+		}
+	}
+	if cfg.CategoryBudget != "" {
+		if p.categoryBudget, err = parseCategoryBudget(cfg.CategoryBudget); err != nil {
+			return fmt.Errorf("invalid -food-category-budget: %v", err)
+		}
+	}
+	if cfg.SimulateSwap != "" {
+		if p.swap, err = parseFoodSwap(cfg.SimulateSwap); err != nil {
+			return fmt.Errorf("invalid -simulate-swap: %v", err)
+		}
+	}
+	if cfg.Advise != "" {
+		if p.mealsRemaining, err = parseAdvise(cfg.Advise); err != nil {
+			return fmt.Errorf("invalid -advise: %v", err)
+		}
+	}
+
+	if cfg.MonthlyReport != "" {
+		if p.reportMonth, err = time.Parse("2006-01", cfg.MonthlyReport); err != nil {
+			return fmt.Errorf("invalid -monthly-pdf-report: expected YYYY-MM, got %q", cfg.MonthlyReport)
+		}
+	}
+
+	// Default to the last 30 days, or the whole report month
+	p.start = time.Now().AddDate(0, 0, -30)
+	if !p.reportMonth.IsZero() {
+		p.start = p.reportMonth
+	}
+	if cfg.Start != "" {
+		if p.start, err = time.Parse("2006-01-02", cfg.Start); err != nil {
+			return fmt.Errorf("parsing start date: %v", err)
+		}
+	}
+	p.end = time.Now()
+	if !p.reportMonth.IsZero() {
+		p.end = p.reportMonth.AddDate(0, 1, -1)
+	}
+	if cfg.End != "" {
+		if p.end, err = time.Parse("2006-01-02", cfg.End); err != nil {
+			return fmt.Errorf("parsing end date: %v", err)
+		}
+	}
+	if p.start.After(p.end) {
+		return fmt.Errorf("start date %s is after end date %s", p.start.Format("2006-01-02"), p.end.Format("2006-01-02"))
+	}
+
+	p.accounts = []accountCredentials{{Username: cfg.Username, Password: cfg.Password}}
+	if cfg.DiffAccount != "" {
+		other, err := parseAccountCredentials(cfg.DiffAccount)
+		if err != nil {
+			return fmt.Errorf("invalid -diff-account: %v", err)
+		}
+		p.accounts = append(p.accounts, other)
+	}
+	return nil
+}
+
+// Run executes the pipeline and writes the formatted result to w
+func (p *Pipeline) Run(ctx context.Context, w io.Writer) error {
+	cfg := p.Config
+
+	// Warn about implausible goals without stopping
+	if cfg.ValidateGoals {
+		for _, warning := range ValidateGoals(p.goals) {
+			fmt.Fprintf(os.Stderr, "Warning: goal %s=%g: %s\n", warning.Goal, warning.Value, warning.Message)
+		}
+	}
+
+	// Save the goals as a named set
+	if cfg.StoreGoals != "" {
+		if err := p.Storage.StoreGoals(cfg.StoreGoals, p.goals); err != nil {
+			return fmt.Errorf("saving goal set: %v", err)
+		}
+		fmt.Fprintf(os.Stderr, "Saved goal set %s\n", cfg.StoreGoals)
+		return nil
+	}
+
+	// Document the output schema
+	if cfg.ExportReadme {
+		if err := os.WriteFile("SCHEMA.md", []byte(GenerateSchema()), 0644); err != nil {
+			return fmt.Errorf("writing SCHEMA.md: %v", err)
+		}
+		fmt.Fprintln(os.Stderr, "Wrote SCHEMA.md")
+		return nil
+	}
+
+	// Lint saved outputs
+	if cfg.BatchValidate != "" {
+		checked, failed, err := BatchValidate(cfg.BatchValidate, os.Stderr)
+		if err != nil {
+			return fmt.Errorf("reading %s: %v", cfg.BatchValidate, err)
+		}
+		if failed > 0 {
+			return fmt.Errorf("%d of %d files failed validation", failed, checked)
+		}
+		fmt.Fprintf(os.Stderr, "All %d files passed validation\n", checked)
+		return nil
+	}
+
+	// Estimate BMR and TDEE
+	if cfg.ComputeBMR {
+		multipliers, err := parseActivityMultipliers(cfg.ActivityMultipliers)
+		if err != nil {
+			return fmt.Errorf("invalid -activity-multipliers: %v", err)
+		}
+		estimate, err := EstimateEnergy(cfg.WeightKg, cfg.HeightCm, float64(cfg.Age), cfg.Sex, multipliers)
+		if err != nil {
+			return fmt.Errorf("invalid -compute-bmr: %v", err)
+		}
+		return writeJSON(w, estimate)
+	}
+
+	// Add a food to the local database
+	if cfg.AddLocalFood != "" {
+		food, err := parseLocalFood(cfg.AddLocalFood)
+		if err != nil {
+			return fmt.Errorf("invalid -add-local-food: %v", err)
+		}
+		if err := p.Storage.AddLocalFood(food); err != nil {
+			return fmt.Errorf("saving local food: %v", err)
+		}
+		fmt.Fprintf(os.Stderr, "Saved %s to the local food database\n", food.Name)
+		return nil
+	}
+
+	if err := p.login(ctx); err != nil {
+		return err
+	}
+
+	// Run a GraphQL query instead of the CSV export
+	if cfg.GraphQL != "" {
+		return p.runGraphQL(ctx, w)
+	}
+
+	days, diary, biometrics, err := p.fetch(ctx)
+	if err != nil {
+		return err
+	}
+
+	result := Result{Days: days}
+	if err := p.analyze(ctx, &result, diary, biometrics); err != nil {
+		return err
+	}
+
+	// Compare against the second account
+	if cfg.DiffAccount != "" {
+		other, err := p.fetchOther(ctx)
+		if err != nil {
+			return err
+		}
+		result.Comparison = compareAccounts(result.Days, other)
+	}
+
+	// Join the diary to each day, warning where their totals disagree
+	var merged []MergedDay
+	if cfg.MergeDiary {
+		merged = MergeDiaryAndNutrition(result.Days, diary)
+		for _, day := range merged {
+			for _, warning := range mergeDiscrepancies(day) {
+				fmt.Fprintf(os.Stderr, "Warning: %s\n", warning)
+			}
+		}
+	}
+
+	// Reformat dates for output
+	if err := formatDates(result.Days, cfg.DateLayout); err != nil {
+		return fmt.Errorf("formatting dates: %v", err)
+	}
+	if err := result.Summary.formatDates(cfg.DateLayout); err != nil {
+		return fmt.Errorf("formatting dates: %v", err)
+	}
+	if err := formatComparisonDates(result.Comparison, cfg.DateLayout); err != nil {
+		return fmt.Errorf("formatting dates: %v", err)
+	}
+	if err := formatWeekDates(result.Weeks, cfg.DateLayout); err != nil {
+		return fmt.Errorf("formatting dates: %v", err)
+	}
+	if err := formatMergedDates(merged, cfg.DateLayout); err != nil {
+		return fmt.Errorf("formatting dates: %v", err)
+	}
+
+	// Write the merged view instead of the days
+	if cfg.MergeDiary {
+		return writeJSON(w, merged)
+	}
+
+	// Compare against a saved output instead of writing the days
+	if cfg.DiffFile != "" {
+		previous, err := loadOutputFile(cfg.DiffFile)
+		if err != nil {
+			return fmt.Errorf("reading -diff-file: %v", err)
+		}
+		return writeJSON(w, DiffOutput(previous, result.Days))
+	}
+
+	return p.Formatter.Format(w, result)
+}
+
+// login logs in to every account whose client was not injected
+func (p *Pipeline) login(ctx context.Context) error {
+	if p.Client != nil && (len(p.accounts) < 2 || p.Other != nil) {
+		return nil
+	}
+	clients, err := loginAccounts(ctx, p.accounts, p.Config.Timeout)
+	if err != nil {
+		return fmt.Errorf("logging in to Cronometer: %v", describeTimeout(err))
+	}
+	if p.Client == nil {
+		p.Client = clients[0]
+	}
+	if len(clients) > 1 && p.Other == nil {
+		p.Other = clients[1]
+	}
+	return nil
+}
+
+// runGraphQL runs the -graphql query file and prints the raw response
+func (p *Pipeline) runGraphQL(ctx context.Context, w io.Writer) error {
+	client, ok := p.Client.(*gocronometer.Client)
+	if !ok {
+		return fmt.Errorf("-graphql requires a logged-in Cronometer client")
+	}
+	query, err := os.ReadFile(p.Config.GraphQL)
+	if err != nil {
+		return fmt.Errorf("reading GraphQL query: %v", err)
+	}
+	data, err := FetchNutritionGraphQL(ctx, client, string(query), map[string]interface{}{})
+	if err != nil {
+		return fmt.Errorf("running GraphQL query: %v", describeTimeout(err))
+	}
+	_, err = fmt.Fprintln(w, string(data))
+	return err
+}
+
+// needsDiary reports whether any requested analysis uses the food diary
+func (p *Pipeline) needsDiary() bool {
+	cfg := p.Config
+	return cfg.LoggingQuality || cfg.Preferences || cfg.TopSources > 0 || cfg.Supplements || cfg.Suggest ||
+		cfg.LocalFood || cfg.FoodFrequency > 0 || cfg.GroupByFood || cfg.DetectFasting ||
+		cfg.MonotonyWindow > 0 || cfg.ProteinDistribution || cfg.DetectHighGI ||
+		cfg.MergeDiary || cfg.RandomMeal || cfg.LongevityScore ||
+		cfg.FoodPairing || cfg.CheckB12 != "" || cfg.ProteinTiming ||
+		cfg.NormalizeServings || cfg.InferPortions || cfg.TrackHydration || p.protocol.timeRestricted() ||
+		cfg.CheckIron || cfg.ProteinQuality || cfg.SimulateSwap != "" || cfg.CheckLeucine ||
+		cfg.CaloricDensity || cfg.NightEating || cfg.MealSpacing || cfg.NutritionFacts != "" ||
+		cfg.TrackEatingWindow || cfg.Phytochemicals || cfg.CheckPS || cfg.CheckPC || cfg.CheckAGE ||
+		cfg.DiversityIndex || cfg.SupplementDepends || cfg.GHSupport || cfg.GlycemicLoad ||
+		cfg.DietaryCreatine || cfg.CategoryBudget != "" || cfg.CheckBiotin || cfg.NutrientEfficiency ||
+		cfg.TrackCaffeine || cfg.CheckAlcohol
+}
+
+// needsBiometrics reports whether any requested analysis uses biometrics
+func (p *Pipeline) needsBiometrics() bool {
+	cfg := p.Config
+	return cfg.NormalizeWeight || cfg.TargetWeightLbs > 0 || cfg.CrossValidateWeights || cfg.ComputeLeanMass || cfg.RecommendGoals ||
+		cfg.CheckMagnesium != "" || cfg.WeightChart != "" || cfg.CompareBMR || cfg.ComputeREE
+}
+
+// fetch exports and parses the daily nutrition, plus the diary and
+// biometrics when an analysis needs them
+func (p *Pipeline) fetch(ctx context.Context) ([]DailyNutrition, []FoodEntry, []BiometricEntry, error) {
+	cfg := p.Config
+	needDiary, needBiometrics := p.needsDiary(), p.needsBiometrics()
+
+	// Export daily nutrition data, either split into date-range chunks or
+	// fetched alongside the diary and biometrics
+	var csvChunks []string
+	var diary []FoodEntry
+	var biometrics []BiometricEntry
+	if cfg.ChunkStrategy == "type" {
+		fetched, err := fetchByType(ctx, p.Client, p.start, p.end, needDiary, needBiometrics, cfg.MaxRetries)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		csvChunks = []string{fetched.Nutrition}
+		diary, biometrics = fetched.Diary, fetched.Biometrics
+	} else {
+		jobs := []FetchJob{{Start: p.start, End: p.end}}
+		if cfg.ChunkDays > 0 {
+			jobs = splitDateRange(p.start, p.end, cfg.ChunkDays)
+		}
+		if len(jobs) == 0 {
+			return nil, nil, nil, fmt.Errorf("no dates to export between %s and %s", p.start.Format("2006-01-02"), p.end.Format("2006-01-02"))
+		}
+		var err error
+		csvChunks, err = fetchChunked(ctx, p.Client.ExportDailyNutrition, jobs, cfg.MaxRetries)
+		if err != nil {
+			return nil, nil, nil, fmt.Errorf("exporting nutrition data: %v", describeTimeout(err))
+		}
+	}
+
+	// Parse CSV data
+	var days []DailyNutrition
+	for _, csvData := range csvChunks {
+		chunk, err := p.Parser.ParseDailyNutrition(csvData)
+		if err != nil {
+			return nil, nil, nil, fmt.Errorf("parsing nutrition data: %v", err)
+		}
+		days = append(days, chunk...)
+	}
+
+	days = p.restrictDates(days)
+
+	if cfg.ChunkStrategy == "date" {
+		var err error
+		if needDiary {
+			if diary, err = fetchDiary(ctx, p.Client, p.start, p.end); err != nil {
+				return nil, nil, nil, fmt.Errorf("exporting food diary: %v", describeTimeout(err))
+			}
+		}
+		if needBiometrics {
+			if biometrics, err = fetchBiometrics(ctx, p.Client, p.start, p.end); err != nil {
+				return nil, nil, nil, fmt.Errorf("exporting biometrics: %v", describeTimeout(err))
+			}
+		}
+	}
+
+	// Merge measurements imported from MyFitnessPal
+	if needBiometrics && cfg.ImportMFP != "" {
+		imported, err := loadMFPBiometrics(p.mfp, p.start, p.end)
+		if err != nil {
+			return nil, nil, nil, fmt.Errorf("importing MyFitnessPal measurements: %v", err)
+		}
+		biometrics = mergeBiometrics(biometrics, imported)
+	}
+	return days, diary, biometrics, nil
+}
+
+// fetchOther exports and parses the -diff-account records, preparing them
+// the same way analyze prepares the main account's
+func (p *Pipeline) fetchOther(ctx context.Context) ([]DailyNutrition, error) {
+	username := p.accounts[1].Username
+	csvData, err := p.Other.ExportDailyNutrition(ctx, p.start, p.end)
+	if err != nil {
+		return nil, fmt.Errorf("exporting nutrition data for %s: %v", username, describeTimeout(err))
+	}
+	days, err := p.Parser.ParseDailyNutrition(csvData)
+	if err != nil {
+		return nil, fmt.Errorf("parsing nutrition data for %s: %v", username, err)
+	}
+	days = p.trimDays(p.restrictDates(days))
+	p.correctDays(days)
+	return days, nil
+}
+
+// restrictDates drops rows Cronometer returned outside the requested range
+// when -strict-dates is set
+func (p *Pipeline) restrictDates(days []DailyNutrition) []DailyNutrition {
+	if !p.Config.StrictDates {
+		return days
+	}
+	days, removed := filterDateRange(days, p.start, p.end)
+	if p.Config.Verbose {
+		fmt.Fprintf(os.Stderr, "Removed %d rows outside %s to %s\n", removed, p.start.Format("2006-01-02"), p.end.Format("2006-01-02"))
+	}
+	return days
+}
+
+// trimDays drops today's partial data with -skip-today and the -exclude-dates
+func (p *Pipeline) trimDays(days []DailyNutrition) []DailyNutrition {
+	if p.Config.SkipToday {
+		days = excludeDates(days, []string{time.Now().Format("2006-01-02")})
+	}
+	if len(p.excluded) > 0 {
+		days = excludeDates(days, p.excluded)
+	}
+	return days
+}
+
+// correctDays caps obvious data errors, extrapolates partially logged days,
+// and recomputes calories from macros, in that order
+func (p *Pipeline) correctDays(days []DailyNutrition) {
+	cfg := p.Config
+	if len(p.clampBounds) > 0 {
+		clampRecords(days, p.clampBounds)
+	}
+	if cfg.Saturate {
+		saturateIncompleteDays(days, cfg.ExpectedMinCalories)
+	}
+	if cfg.Recompute {
+		recomputeCalories(days)
+	}
+}
+
+// analyze runs every requested analysis, annotating result.Days and filling
+// result.Summary
+func (p *Pipeline) analyze(ctx context.Context, result *Result, diary []FoodEntry, biometrics []BiometricEntry) error {
+	cfg := p.Config
+	goals := p.goals
+	summary := &result.Summary
+
+	// Fill diary gaps from the local food database
+	if cfg.LocalFood {
+		db, err := p.Storage.LoadFoodDatabase()
+		if err != nil {
+			return fmt.Errorf("loading local food database: %v", err)
+		}
+		filled := applyLocalFoods(result.Days, diary, db)
+		fmt.Fprintf(os.Stderr, "Filled %d diary entries from the local food database\n", filled)
+	}
+
+	// Drop today's partial data and excluded dates before any analysis
+	result.Days = p.trimDays(result.Days)
+	if cfg.SkipToday {
+		diary = excludeEntryDates(diary, []string{time.Now().Format("2006-01-02")})
+	}
+	if len(p.excluded) > 0 {
+		diary = excludeEntryDates(diary, p.excluded)
+		summary.ExcludedDates = p.excluded
+	}
+
+	days := result.Days
+
+	// Swap generic amounts for standard portions
+	if cfg.InferPortions {
+		inferred := applyInferredPortions(days, diary)
+		fmt.Fprintf(os.Stderr, "Inferred standard portions for %d diary entries\n", inferred)
+	}
+
+	// Log each food in a consistent unit
+	if cfg.NormalizeServings {
+		var changes []NormalizationChange
+		diary, changes = NormalizeServingSizes(diary)
+		for _, change := range changes {
+			if change.Warning != "" {
+				fmt.Fprintf(os.Stderr, "Warning: %s %s: %s\n", change.Date, change.FoodName, change.Warning)
+			}
+		}
+		summary.ServingChanges = changes
+	}
+
+	// Cap data errors, extrapolate partial days, and recompute calories
+	// before any analysis reads them
+	p.correctDays(days)
+	if cfg.Recompute {
+		summary.CalorieDiscrepancy = calorieDiscrepancy(days)
+	}
+
+	// Apply day labels
+	applyLabels(days, cfg.Labels)
+
+	// Score food logging specificity
+	if cfg.LoggingQuality {
+		applyLoggingQuality(days, diary)
+		overall := LoggingQuality(diary)
+		summary.LoggingQuality = &overall
+	}
+
+	// Learn food preferences
+	if cfg.Preferences {
+		model := LearnFoodPreferences(diary, cfg.MinOccurrences)
+		summary.Preferences = &model
+	}
+
+	// Rank foods by calorie contribution
+	if cfg.TopSources > 0 {
+		summary.TopCalorieSources = TopCalorieSources(diary, cfg.TopSources)
+	}
+
+	// Find foods commonly logged together
+	if cfg.FoodPairing {
+		pairs := FoodPairings(diary, cfg.Meal)
+		if len(pairs) > maxFoodPairs {
+			pairs = pairs[:maxFoodPairs]
+		}
+		summary.FoodPairings = pairs
+	}
+
+	// Label each food in the diary
+	if cfg.NutritionFacts != "" {
+		if err := writeNutritionLabels(diary, cfg.NutritionFacts); err != nil {
+			return fmt.Errorf("writing nutrition labels: %v", err)
+		}
+		fmt.Fprintf(os.Stderr, "Wrote %s\n", cfg.NutritionFacts)
+	}
+
+	// Classify foods by calories per gram
+	if cfg.CaloricDensity {
+		summary.CaloricDensity = caloricDensityReport(diary)
+	}
+
+	// Rank foods by micronutrients per calorie
+	if cfg.NutrientEfficiency {
+		summary.NutrientEfficiency = nutrientEfficiencyReport(diary)
+	}
+
+	// Compare each food category's share of calories to its target
+	if cfg.CategoryBudget != "" {
+		summary.CategoryBudget = FoodCategoryBudget(diary, foodCategoryKeywords, p.categoryBudget)
+	}
+
+	// Measure how evenly calories spread across foods
+	if cfg.DiversityIndex {
+		summary.DiversityTrend = applyDiversityIndex(days, diary)
+	}
+
+	// Estimate phytochemical exposure from plant variety
+	if cfg.Phytochemicals {
+		keywords := phytochemicalKeywords
+		if cfg.PlantKeywords != "" {
+			var err error
+			if keywords, err = loadPlantKeywords(cfg.PlantKeywords); err != nil {
+				return fmt.Errorf("loading plant keywords: %v", err)
+			}
+		}
+		summary.Phytochemicals = phytochemicalReport(diary, keywords)
+	}
+
+	// Score dietary variety over a rolling window
+	if cfg.MonotonyWindow > 0 {
+		summary.Monotony = FoodMonotonyScore(diary, cfg.MonotonyWindow)
+	}
+
+	// Score how evenly protein is spread across meals
+	if cfg.ProteinDistribution {
+		report := ProteinDistributionScore(diary, cfg.WeightKg, defaultMealsPerDay)
+		summary.ProteinDistribution = &report
+	}
+
+	// Spin for a frequent meal
+	if cfg.RandomMeal {
+		if templates := mealTemplates(diary); len(templates) > 0 {
+			meal := SelectRandomMealTemplate(templates, cfg.Seed)
+			summary.RandomMeal = &meal
+		}
+	}
+
+	// Model replacing one food with another
+	if cfg.SimulateSwap != "" {
+		db, err := p.Storage.LoadFoodDatabase()
+		if err != nil {
+			return fmt.Errorf("loading local food database: %v", err)
+		}
+		macros, err := swapFoodMacros(p.swap.Add, db, diary)
+		if err != nil {
+			return fmt.Errorf("simulating swap: %v", err)
+		}
+		impact := SimulateFoodSwap(diary, p.swap.Remove, p.swap.Add, macros)
+		summary.FoodSwap = &impact
+	}
+
+	// Check protein after each workout and arginine before it
+	if cfg.ProteinTiming || cfg.GHSupport {
+		activities, err := fetchActivities(ctx, p.Client, p.start, p.end)
+		if err != nil {
+			return fmt.Errorf("exporting exercises: %v", describeTimeout(err))
+		}
+		if cfg.ProteinTiming {
+			summary.PostWorkout = postWorkoutReports(days, activities, diary)
+		}
+		if cfg.GHSupport {
+			applyGHSupport(days, diary, activities)
+		}
+	}
+
+	// Check each meal's leucine against the protein synthesis threshold
+	if cfg.CheckLeucine {
+		summary.Leucine = leucineReport(diary)
+	}
+
+	// Time each day's eating window
+	if cfg.TrackEatingWindow {
+		applyEatingWindows(days, diary)
+	}
+
+	// Flag days with heavy eating after the cutoff
+	if cfg.NightEating {
+		applyNightEating(days, diary, cfg.CutoffHour)
+	}
+
+	// Check the gaps between meals
+	if cfg.MealSpacing {
+		hours := func(h float64) time.Duration { return time.Duration(h * float64(time.Hour)) }
+		report := CheckMealSpacing(diary, hours(cfg.MinGapHours), hours(cfg.MaxGapHours))
+		summary.MealSpacing = &report
+	}
+
+	// Total water from the diary
+	if cfg.TrackHydration {
+		summary.Hydration = applyHydration(days, diary, cfg.GoalWaterMl)
+	}
+
+	// Total caffeine from the diary
+	if cfg.TrackCaffeine {
+		applyCaffeine(days, diary)
+	}
+
+	// Count alcohol units per day and week
+	if cfg.CheckAlcohol {
+		summary.AlcoholWeeks = applyAlcohol(days, diary, cfg.Sex)
+	}
+
+	// Balance electrolytes, against the day's water when it was totalled
+	if cfg.CheckElectrolytes {
+		applyElectrolytes(days)
+	}
+
+	// Estimate net protein utilization from the limiting amino acid
+	if cfg.ComputeNPU {
+		applyNPU(days)
+	}
+
+	// Rate protein quality by DIAAS
+	if cfg.ProteinQuality {
+		applyProteinQuality(days, diary)
+	}
+
+	// Estimate phosphatidylserine from the diary foods
+	if cfg.CheckPS {
+		applyPhosphatidylserine(days, diary)
+	}
+
+	// Estimate phosphatidylcholine from the diary foods
+	if cfg.CheckPC {
+		applyPhosphatidylcholine(days, diary)
+	}
+
+	// Estimate AGEs from cooking methods
+	if cfg.CheckAGE {
+		applyAGEScores(days, diary)
+	}
+
+	// Total the glycemic load of the diary's carbs
+	if cfg.GlycemicLoad {
+		applyGlycemicLoad(days, diary)
+	}
+
+	// Find high glycemic index foods
+	if cfg.DetectHighGI {
+		applyHighGI(days, diary)
+	}
+
+	// Count how often each food is logged per week
+	if cfg.FoodFrequency > 0 {
+		frequencies := FoodLogFrequency(diary, float64(len(days))/7)
+		if len(frequencies) > cfg.FoodFrequency {
+			frequencies = frequencies[:cfg.FoodFrequency]
+		}
+		summary.FoodFrequency = frequencies
+	}
+
+	// Total macros per food
+	if cfg.GroupByFood {
+		summary.FoodAggregates = AggregateByFood(diary)
+	}
+
+	// Detect intermittent fasting days
+	if cfg.DetectFasting {
+		summary.Fasting = &FastingReport{
+			Days:        DetectFastingDays(diary, defaultEatingWindowHours),
+			Consistency: FastingConsistency(diary, defaultEatingWindowHours),
+		}
+	}
+
+	// Check adherence to a structured fasting protocol
+	if cfg.FastingProtocol != "" {
+		report := ProtocolAdherence(p.protocol, days, diary, p.start, p.end)
+		summary.FastingProtocol = &report
+	}
+
+	// Separate supplements from food
+	if cfg.Supplements {
+		report := trackSupplements(diary)
+		summary.Supplements = &report
+	}
+
+	// Find nutrients whose goals only supplements meet
+	if cfg.SupplementDepends {
+		summary.SupplementDepends = SupplementDependencies(days, diary, goals)
+	}
+
+	// Categorize sodium intake
+	if cfg.CheckSodium || cfg.CheckCardio {
+		summary.SodiumCategories = applySodiumCategories(days)
+	}
+
+	// Rate the potassium-to-sodium ratio
+	if cfg.CheckCardio {
+		applyCardiovascular(days)
+	}
+
+	// Check omega-3 intake
+	if cfg.CheckOmega3 {
+		applyOmega3(days)
+	}
+
+	// Check vitamin D against the RDA with seasonal sun exposure
+	if cfg.CheckVitaminD {
+		applyVitaminD(days, *cfg.Latitude)
+	}
+
+	// Check choline against the AI
+	if cfg.CheckCholine {
+		applyCholine(days, cfg.Sex)
+	}
+
+	// Check folate equivalents against the RDA
+	if cfg.CheckFolate {
+		applyFolate(days)
+	}
+
+	// Rate vitamin K1 and K2 separately
+	if cfg.CheckVitaminK {
+		applyVitaminK(days)
+	}
+
+	// Estimate creatine synthesis from its amino acid precursors
+	if cfg.CreatineSynthesis {
+		if skipped := applyCreatineSynthesis(days); skipped > 0 {
+			fmt.Fprintf(os.Stderr, "Warning: %d days are missing glycine, arginine, or methionine and have no creatine estimate\n", skipped)
+		}
+	}
+
+	// Estimate creatine eaten in meat and fish
+	if cfg.DietaryCreatine {
+		applyDietaryCreatine(days, diary)
+	}
+
+	// Rate glycine for collagen synthesis
+	if cfg.CheckGlycine {
+		applyCollagen(days)
+	}
+
+	// Rate calcium absorption against vitamin D
+	if cfg.CheckCalcium {
+		applyCalciumAbsorption(days)
+	}
+
+	// Rate the calcium-to-phosphorus ratio for bone health
+	if cfg.CheckBoneHealth {
+		applyBoneHealth(days)
+	}
+
+	// Compare the macro split to the target
+	if cfg.MacroRatios != "" {
+		report := CompareMacroRatios(days, p.macroTarget)
+		summary.MacroSplit = &report
+	}
+
+	// Check the macro split against the DRI ranges
+	if cfg.MacroDistribution {
+		summary.MacroDRIFraction = applyMacroDistribution(days)
+	}
+
+	// Check zinc against copper
+	if cfg.CheckZincCopper {
+		applyZincCopper(days)
+	}
+
+	// Check B12 for the dietary pattern
+	if cfg.CheckB12 != "" {
+		summary.B12 = applyB12(days, diary, p.b12Pattern)
+	}
+
+	// Estimate iron absorption from the meat in the diary
+	if cfg.CheckIron {
+		applyIronAbsorption(days, diary)
+	}
+
+	// Advise pairing low iron with vitamin C
+	if cfg.IronVitaminC {
+		applyIronVitaminCPairing(days)
+	}
+
+	// Check trace minerals against their RDAs and AIs
+	if cfg.TraceMinerals {
+		applyTraceMinerals(days)
+	}
+
+	// Rate biotin and look for raw egg white
+	if cfg.CheckBiotin {
+		applyBiotin(days, diary)
+	}
+
+	// Score antioxidant intake
+	if cfg.CheckAntioxidants {
+		applyAntioxidants(days)
+	}
+
+	// Estimate the calories burned digesting food
+	if cfg.ThermicEffect {
+		applyThermicEffect(days)
+	}
+
+	// Estimate the diet's acid load on the kidneys
+	if cfg.CheckPRAL {
+		applyPRAL(days)
+	}
+
+	// Combine acid load and water into a kidney stone risk
+	if cfg.KidneyStoneRisk {
+		applyKidneyStoneRisk(days)
+	}
+
+	// Profile soluble fiber and saturated fat for LDL cholesterol
+	if cfg.CheckLipids {
+		applyLipidSupport(days)
+	}
+
+	// Estimate the fuel mix from carbs and fat
+	if cfg.ComputeRER {
+		applyRER(days)
+	}
+
+	// Estimate the insulin response from the macros
+	if cfg.ComputeFII {
+		applyFII(days)
+	}
+
+	// Model how filling each day's food was
+	if cfg.ComputeSatiety {
+		applySatiety(days)
+	}
+
+	// Compare lutein and zeaxanthin with AREDS2
+	if cfg.CheckCarotenoids {
+		applyCarotenoids(days)
+	}
+
+	// Score overall micronutrient adequacy
+	if cfg.Micronutrients {
+		summary.MicronutrientTrend = applyMicronutrientScores(days)
+	}
+
+	// Score the diet's inflammatory potential
+	if cfg.CheckInflammation {
+		applyInflammatoryIndex(days)
+	}
+
+	// Rate magnesium and relate it to sleep
+	if cfg.CheckMagnesium != "" {
+		applyMagnesium(days, p.magnesiumSex)
+		summary.MagnesiumSleep = correlateMagnesiumSleep(days, biometrics)
+	}
+
+	// Add ketogenic diet metrics
+	if cfg.KetoMetrics {
+		applyKetoMetrics(days)
+	}
+
+	// Compare calories on stressed days against the rest
+	if cfg.DetectStressEating {
+		moods, err := fetchMoods(ctx, p.Client, p.start, p.end)
+		if err != nil {
+			return fmt.Errorf("exporting notes: %v", describeTimeout(err))
+		}
+		correlation := StressEatingCorrelation(days, moods, parseTags(cfg.StressTags))
+		summary.StressEating = &correlation
+	}
+
+	// Check tryptophan and correlate it with positive moods
+	if cfg.CheckTryptophan {
+		applyTryptophan(days, cfg.WeightKg)
+		moods, err := fetchMoods(ctx, p.Client, p.start, p.end)
+		if err != nil {
+			return fmt.Errorf("exporting notes: %v", describeTimeout(err))
+		}
+		if len(moods) > 0 {
+			summary.TryptophanMood = correlateTryptophanMood(days, moods, parseTags(cfg.PositiveMoodTags))
+		}
+	}
+
+	// Score days on Blue Zones dietary proxies
+	if cfg.LongevityScore {
+		summary.LongevityTrend = applyLongevityScores(days, diary)
+	}
+
+	// Flag implausible weight changes
+	if cfg.CrossValidateWeights {
+		summary.WeightAnomalies = ValidateWeightTransitions(biometrics, maxDailyWeightChangeLbs)
+	}
+
+	// Derive lean mass from weight and body fat
+	if cfg.ComputeLeanMass {
+		leanMass, err := ComputeLeanMass(filterMetric(biometrics, "weight"), filterMetric(biometrics, "body fat"))
+		if err != nil {
+			return fmt.Errorf("computing lean mass: %v", err)
+		}
+		summary.LeanMass = leanMass
+	}
+
+	// Compare BMR equations
+	if cfg.CompareBMR {
+		comparison, err := compareBMR(biometrics, cfg.WeightKg, cfg.HeightCm, float64(cfg.Age), cfg.Sex, cfg.BodyFatPct)
+		if err != nil {
+			return fmt.Errorf("comparing BMR: %v", err)
+		}
+		summary.BMRComparison = comparison
+	}
+
+	// Estimate resting energy expenditure
+	if cfg.ComputeREE {
+		ree, err := computeREE(days, biometrics, cfg.VO2Max, cfg.WeightKg, cfg.HeightCm, float64(cfg.Age), cfg.Sex)
+		if err != nil {
+			return fmt.Errorf("computing REE: %v", err)
+		}
+		summary.REE = ree
+	}
+
+	// Express intake per kilogram of bodyweight
+	if cfg.NormalizeWeight {
+		if err := normalizeByWeight(days, weightSeries(biometrics), cfg.WeightKg); err != nil {
+			return fmt.Errorf("normalizing by weight: %v", err)
+		}
+	}
+
+	// Compare nutrition across seasons
+	if cfg.SeasonalAnalysis {
+		summary.Seasons = SeasonalAnalysis(days)
+	}
+
+	// Chart calories against smoothed weight
+	if cfg.WeightChart != "" {
+		if err := GenerateDualAxisSVG(days, biometrics, cfg.SmoothBandwidth, cfg.WeightChart); err != nil {
+			return fmt.Errorf("writing weight chart: %v", err)
+		}
+		fmt.Fprintf(os.Stderr, "Wrote %s\n", cfg.WeightChart)
+	}
+
+	// Write the monthly PDF report
+	if !p.reportMonth.IsZero() {
+		path := cfg.ReportPath
+		if path == "" {
+			path = fmt.Sprintf("nutrition-report-%s.pdf", p.reportMonth.Format("2006-01"))
+		}
+		if err := GenerateMonthlyReport(days, goals, p.reportMonth.Month(), p.reportMonth.Year(), path); err != nil {
+			return fmt.Errorf("writing monthly report: %v", err)
+		}
+		fmt.Fprintf(os.Stderr, "Wrote %s\n", path)
+	}
+
+	// Rank average intake against the US population
+	if cfg.CompareToPopulation {
+		summary.Population = populationPercentiles(days, p.population)
+	}
+
+	// Average over a trailing window
+	if cfg.Rolling > 0 {
+		applyRollingAverages(days, cfg.Rolling, cfg.CI)
+	}
+
+	// Total protein since the start of the range
+	if cfg.CumulativeProtein {
+		applyCumulativeProtein(days)
+	}
+
+	// Total the stored micronutrients over each trailing week
+	if cfg.CumulativeMicros {
+		applyMicronutrientTotals(days)
+	}
+
+	// Find diet breaks during a cut
+	if cfg.DetectDietBreaks != "" {
+		summary.DietBreaks = DetectDietBreaks(days, p.dietBreaks.TDEE, p.dietBreaks.DeficitThreshold, p.dietBreaks.BreakThreshold, p.dietBreaks.MinBreakDays)
+	}
+
+	// Forecast next week's intake
+	if cfg.PredictNextWeek {
+		predicted, err := predictNextWeek(days, cfg.Alpha)
+		if err != nil {
+			return fmt.Errorf("predicting next week: %v", err)
+		}
+		summary.PredictedNextWeek = predicted
+	}
+
+	// Project when the target weight is reached
+	if cfg.TargetWeightLbs > 0 {
+		model, err := fitWeightModel(days, weightSeries(biometrics), cfg.TDEE)
+		var projection *WeightProjection
+		if err == nil {
+			projection, err = projectWeight(model, cfg.TargetWeightLbs)
+		}
+		if err != nil {
+			return fmt.Errorf("projecting target weight: %v", err)
+		}
+		summary.WeightProjection = projection
+	}
+
+	// Recommend a calorie goal change from the weight trend
+	if cfg.RecommendGoals {
+		trend, latest, err := weeklyWeightTrend(weightSeries(biometrics))
+		if err == nil && len(days) == 0 {
+			err = fmt.Errorf("no nutrition data")
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Skipping goal recommendation: %v\n", err)
+		} else {
+			calorieGoal, ok := goals["calories"]
+			if !ok {
+				for _, d := range days {
+					calorieGoal += d.Calories
+				}
+				calorieGoal /= float64(len(days))
+			}
+			adjustment := RecommendGoalAdjustment(trend, calorieGoal, latest)
+			summary.GoalAdjustment = &adjustment
+		}
+	}
+
+	// Compare each day against its goals
+	applyGoals(days, goals, cfg.ComputeFiberGoal)
+
+	// Suggest habit stacks for nutrient gaps
+	if cfg.Suggest {
+		model := LearnFoodPreferences(diary, cfg.MinOccurrences)
+		summary.HabitSuggestions = HabitStackingSuggestions(model, nutrientGaps(days, goals))
+	}
+
+	// Track weekly goal adherence
+	if cfg.AdherenceTrend {
+		summary.AdherenceTrend = WeeklyAdherenceTrend(days, goals)
+	}
+
+	// Count streaks of days meeting every goal up to the end of the range,
+	// which -skip-today moves back to yesterday
+	if cfg.AdherenceStreak {
+		end := p.end
+		if cfg.SkipToday && end.Format("2006-01-02") == time.Now().Format("2006-01-02") {
+			end = end.AddDate(0, 0, -1)
+		}
+		current, longest := GoalAdherenceStreak(days, goals, end.Format("2006-01-02"))
+		summary.AdherenceStreak = &AdherenceStreak{Current: current, Longest: longest}
+	}
+
+	// Project the successful days needed to reach the target adherence
+	if cfg.GoalTimeline {
+		summary.GoalTimeline = projectGoalTimelines(days, goals, cfg.TargetAdherence)
+	}
+
+	// Advise on the remaining meals for today
+	if p.mealsRemaining > 0 {
+		today := DailyNutrition{Date: time.Now().Format("2006-01-02")}
+		for _, d := range days {
+			if d.Date == today.Date {
+				today = d
+			}
+		}
+		perMeal := BudgetRemainingMeals(today, goalsAsNutrition(goals), p.mealsRemaining)
+		fmt.Fprintln(os.Stderr, formatMealAdvice(perMeal, goals, p.mealsRemaining))
+	}
+
+	// Rebalance the rest of the week's calories
+	if cfg.Rebalance {
+		week := currentWeek(days, time.Now().Format("2006-01-02"))
+		fmt.Fprintln(os.Stderr, formatRebalance(RebalanceWeek(week, cfg.WeeklyBudget, cfg.DaysLeft)))
+	}
+
+	// Aggregate the days per week
+	if cfg.WeeklyTrend {
+		result.Weeks = WeeklyTrendSummary(days, goals)
+	}
+
+	// Average the period for a summary-only report
+	if cfg.SummaryOnly {
+		summary.DaysLogged = len(days)
+		summary.Averages = averageNutrients(days)
+	}
+
+	// Export to Gyroscope
+	if cfg.GyroscopeKey != "" {
+		if err := ExportToGyroscope(ctx, cfg.GyroscopeKey, days); err != nil {
+			return fmt.Errorf("exporting to Gyroscope: %v", err)
+		}
+		fmt.Fprintf(os.Stderr, "Exported %d days to Gyroscope\n", len(days))
+	}
+	return nil
+}
@@ -0,0 +1,242 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jrmycanady/gocronometer"
+)
+
+func TestParseOptionsDateRange(t *testing.T) {
+	tests := []struct {
+		name    string
+		start   string
+		end     string
+		wantErr bool
+	}{
+		{"ordered", "2024-03-01", "2024-03-31", false},
+		{"same day", "2024-03-01", "2024-03-01", false},
+		{"start after end", "2024-03-31", "2024-03-01", true},
+		{"bad start", "03/01/2024", "2024-03-31", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := &Pipeline{Config: Config{Start: tt.start, End: tt.end}}
+			err := p.parseOptions()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseOptions() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestFetchStage(t *testing.T) {
+	client := &mockClient{
+		servings: gocronometer.ServingRecords{
+			{RecordedTime: date(t, "2024-03-02"), FoodName: "Oatmeal", EnergyKcal: 150},
+		},
+	}
+	parser := mockParser{days: map[string][]DailyNutrition{
+		"2024-03-01..2024-03-07": {{Date: "2024-03-01", Calories: 2000}, {Date: "2024-02-28", Calories: 1800}},
+		"2024-03-08..2024-03-10": {{Date: "2024-03-09", Calories: 2200}},
+	}}
+	p := &Pipeline{
+		Config: Config{ChunkDays: 7, ChunkStrategy: "date", StrictDates: true, TrackCaffeine: true},
+		Client: client,
+		Parser: parser,
+		start:  date(t, "2024-03-01"),
+		end:    date(t, "2024-03-10"),
+	}
+
+	days, diary, biometrics, err := p.fetch(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	// -strict-dates drops the row outside the range
+	if len(days) != 2 || days[0].Date != "2024-03-01" || days[1].Date != "2024-03-09" {
+		t.Errorf("days = %+v, want 2024-03-01 and 2024-03-09", days)
+	}
+	if len(diary) != 1 || diary[0].FoodName != "Oatmeal" {
+		t.Errorf("diary = %+v, want the one serving", diary)
+	}
+	if biometrics != nil {
+		t.Errorf("biometrics = %+v, want none when no analysis needs them", biometrics)
+	}
+	nutritionCalls := 0
+	for _, call := range client.calls {
+		if call == "nutrition" {
+			nutritionCalls++
+		}
+	}
+	if nutritionCalls != 2 {
+		t.Errorf("got %d nutrition exports, want one per chunk", nutritionCalls)
+	}
+}
+
+func TestFetchStageErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		client *mockClient
+		parser mockParser
+		start  string
+		end    string
+		want   string
+	}{
+		{"export fails", &mockClient{err: errors.New("unavailable")}, mockParser{}, "2024-03-01", "2024-03-07", "exporting nutrition data"},
+		{"parse fails", &mockClient{}, mockParser{err: errors.New("bad header")}, "2024-03-01", "2024-03-07", "parsing nutrition data"},
+		{"no chunks", &mockClient{}, mockParser{}, "2024-03-07", "2024-03-01", "no dates to export"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := &Pipeline{
+				Config: Config{ChunkDays: 7, ChunkStrategy: "date"},
+				Client: tt.client,
+				Parser: tt.parser,
+				start:  date(t, tt.start),
+				end:    date(t, tt.end),
+			}
+			_, _, _, err := p.fetch(context.Background())
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("fetch() error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestAnalyzeStageFillsFromLocalFoods(t *testing.T) {
+	storage := &mockStorage{foods: FoodDatabase{Foods: []LocalFood{
+		{Name: "Grandma's Soup", Serving: "1 bowl", Calories: 300, Protein: 20, Fat: 10, Carbs: 30},
+	}}}
+	p := &Pipeline{Config: Config{LocalFood: true}, Storage: storage}
+	result := Result{Days: []DailyNutrition{{Date: "2024-03-01", Calories: 1000}}}
+	diary := []FoodEntry{{Date: "2024-03-01", FoodName: "Grandma's Soup", Amount: 1, Unit: "bowl"}}
+
+	if err := p.analyze(context.Background(), &result, diary, nil); err != nil {
+		t.Fatal(err)
+	}
+	if got := result.Days[0].Calories; got != 1300 {
+		t.Errorf("calories = %g, want 1300 after filling the soup", got)
+	}
+}
+
+func TestAnalyzeStageExcludesDates(t *testing.T) {
+	p := &Pipeline{excluded: []string{"2024-03-02"}}
+	result := Result{Days: []DailyNutrition{{Date: "2024-03-01"}, {Date: "2024-03-02"}, {Date: "2024-03-03"}}}
+
+	if err := p.analyze(context.Background(), &result, nil, nil); err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Days) != 2 || result.Days[1].Date != "2024-03-03" {
+		t.Errorf("days = %+v, want 2024-03-02 removed", result.Days)
+	}
+	if len(result.Summary.ExcludedDates) != 1 {
+		t.Errorf("summary excluded dates = %v, want 2024-03-02", result.Summary.ExcludedDates)
+	}
+}
+
+func TestRunFormatsResult(t *testing.T) {
+	formatter := &mockFormatter{}
+	p := &Pipeline{
+		Config:    Config{ChunkStrategy: "date", DateLayout: "01/02/2006"},
+		Client:    &mockClient{},
+		Parser:    mockParser{days: map[string][]DailyNutrition{"2024-03-01..2024-03-02": {{Date: "2024-03-01", Calories: 2000}}}},
+		Formatter: formatter,
+		start:     date(t, "2024-03-01"),
+		end:       date(t, "2024-03-02"),
+	}
+
+	var out bytes.Buffer
+	if err := p.Run(context.Background(), &out); err != nil {
+		t.Fatal(err)
+	}
+	if out.String() != "formatted" {
+		t.Errorf("output = %q, want the formatter's output", out.String())
+	}
+	if formatter.result == nil || len(formatter.result.Days) != 1 {
+		t.Fatalf("formatter got %+v, want one day", formatter.result)
+	}
+	if got := formatter.result.Days[0].Date; got != "03/01/2024" {
+		t.Errorf("date = %q, want it reformatted with -format-date before formatting", got)
+	}
+}
+
+func TestRunStoresGoalSet(t *testing.T) {
+	storage := &mockStorage{}
+	p := &Pipeline{
+		Config:  Config{StoreGoals: "cut"},
+		Storage: storage,
+		goals:   map[string]float64{"protein": 180},
+	}
+	if err := p.Run(context.Background(), io.Discard); err != nil {
+		t.Fatal(err)
+	}
+	if storage.goalSets["cut"]["protein"] != 180 {
+		t.Errorf("stored goal sets = %v, want cut with protein 180", storage.goalSets)
+	}
+}
+
+func TestAnalyzeRecomputesBeforeDerivedAnalysis(t *testing.T) {
+	p := &Pipeline{Config: Config{Recompute: true, ThermicEffect: true}}
+	// Cronometer reports 2500 kcal, but the macros add up to 2000
+	result := Result{Days: []DailyNutrition{{Date: "2024-03-01", Calories: 2500, Protein: 100, Fat: 80, Carbs: 220}}}
+
+	if err := p.analyze(context.Background(), &result, nil, nil); err != nil {
+		t.Fatal(err)
+	}
+	d := result.Days[0]
+	if d.Calories != 2000 {
+		t.Fatalf("calories = %g, want the Atwater value 2000", d.Calories)
+	}
+	if d.NetCaloriesAfterTEF == nil || *d.NetCaloriesAfterTEF != 2000-*d.ThermalEffectKcal {
+		t.Errorf("net calories after TEF = %v, want it computed from the recomputed calories", d.NetCaloriesAfterTEF)
+	}
+}
+
+func TestSkipToday(t *testing.T) {
+	today := time.Now().Format("2006-01-02")
+	yesterday := time.Now().AddDate(0, 0, -1).Format("2006-01-02")
+	tests := []struct {
+		name        string
+		days        []DailyNutrition
+		wantDates   []string
+		wantCurrent int
+	}{
+		{"today is the only record", []DailyNutrition{{Date: today, Protein: 30}}, nil, 0},
+		// Today's partial 30g misses the protein goal but doesn't end the streak
+		{"today and yesterday", []DailyNutrition{{Date: yesterday, Protein: 120}, {Date: today, Protein: 30}}, []string{yesterday}, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := &Pipeline{
+				Config: Config{SkipToday: true, AdherenceStreak: true, SummaryOnly: true},
+				goals:  map[string]float64{"protein": 100},
+				start:  time.Now().AddDate(0, 0, -1),
+				end:    time.Now(),
+			}
+			result := Result{Days: tt.days}
+			if err := p.analyze(context.Background(), &result, nil, nil); err != nil {
+				t.Fatal(err)
+			}
+			if len(result.Days) != len(tt.wantDates) {
+				t.Fatalf("days = %+v, want %v", result.Days, tt.wantDates)
+			}
+			// The most recent record is now yesterday's
+			for i, want := range tt.wantDates {
+				if result.Days[i].Date != want {
+					t.Errorf("day %d = %s, want %s", i, result.Days[i].Date, want)
+				}
+			}
+			if result.Summary.DaysLogged != len(tt.wantDates) {
+				t.Errorf("days logged = %d, want %d", result.Summary.DaysLogged, len(tt.wantDates))
+			}
+			if streak := result.Summary.AdherenceStreak; streak == nil || streak.Current != tt.wantCurrent {
+				t.Errorf("streak = %+v, want current %d", streak, tt.wantCurrent)
+			}
+		})
+	}
+}
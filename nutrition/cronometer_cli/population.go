@@ -0,0 +1,91 @@
+package main
+
+import (
+	"fmt"
+	"math"
+)
+
+// populationStat is a nutrient's population mean and standard deviation
+type populationStat struct {
+	Mean, SD float64
+}
+
+// populationGroup is an NHANES age/sex group, covering ages MinAge and up to
+// the next group
+type populationGroup struct {
+	Sex    string
+	MinAge int
+	Intake map[string]populationStat
+}
+
+// nhanesIntake is a simplified table of usual daily intake for US adults,
+// rounded from NHANES 2017-2018 (What We Eat in America). Standard
+// deviations are approximate.
+var nhanesIntake = []populationGroup{
+	{"male", 20, map[string]populationStat{
+		"calories": {2580, 900}, "protein": {101, 40}, "carbs": {290, 115},
+		"fat": {102, 45}, "fiber": {18.5, 9}, "sodium": {4100, 1600},
+	}},
+	{"male", 40, map[string]populationStat{
+		"calories": {2500, 880}, "protein": {98, 38}, "carbs": {270, 110},
+		"fat": {99, 44}, "fiber": {19.5, 9.5}, "sodium": {4000, 1550},
+	}},
+	{"male", 60, map[string]populationStat{
+		"calories": {2150, 750}, "protein": {85, 33}, "carbs": {240, 95},
+		"fat": {86, 38}, "fiber": {18.5, 9}, "sodium": {3450, 1350},
+	}},
+	{"female", 20, map[string]populationStat{
+		"calories": {1900, 700}, "protein": {70, 28}, "carbs": {230, 95},
+		"fat": {76, 34}, "fiber": {15, 7.5}, "sodium": {3000, 1200},
+	}},
+	{"female", 40, map[string]populationStat{
+		"calories": {1850, 680}, "protein": {71, 28}, "carbs": {215, 90},
+		"fat": {76, 34}, "fiber": {16, 8}, "sodium": {3000, 1200},
+	}},
+	{"female", 60, map[string]populationStat{
+		"calories": {1650, 600}, "protein": {65, 25}, "carbs": {195, 80},
+		"fat": {68, 30}, "fiber": {16, 8}, "sodium": {2700, 1050},
+	}},
+}
+
+// nhanesGroup returns the intake table for an adult's age and sex
+func nhanesGroup(age int, sex string) (map[string]populationStat, error) {
+	if age < 20 {
+		return nil, fmt.Errorf("population data covers ages 20 and up, got %d", age)
+	}
+	var match map[string]populationStat
+	for _, group := range nhanesIntake {
+		if group.Sex == sex && age >= group.MinAge {
+			match = group.Intake
+		}
+	}
+	if match == nil {
+		return nil, fmt.Errorf("expected -sex male or female, got %q", sex)
+	}
+	return match, nil
+}
+
+// PopulationPercentile returns the percentile (0-100) of value in a normal
+// distribution with the population's mean and standard deviation
+func PopulationPercentile(value, popMean, popSD float64) float64 {
+	if popSD <= 0 {
+		return 50
+	}
+	return 50 * (1 + math.Erf((value-popMean)/(popSD*math.Sqrt2)))
+}
+
+// populationPercentiles ranks the average daily intake of each nutrient in
+// the table against the group
+func populationPercentiles(records []DailyNutrition, group map[string]populationStat) map[string]float64 {
+	averages := averageNutrients(records)
+	if averages == nil {
+		return nil
+	}
+	percentiles := make(map[string]float64, len(group))
+	for name, stat := range group {
+		if average, ok := averages[name]; ok {
+			percentiles[name] = PopulationPercentile(average, stat.Mean, stat.SD)
+		}
+	}
+	return percentiles
+}
@@ -0,0 +1,110 @@
+package main
+
+import "strings"
+
+// defaultServingGrams is the amount Cronometer records when no amount is given
+const defaultServingGrams = 100.0
+
+// standardPortions maps lowercase food name fragments to a typical single
+// portion in grams, from USDA FoodData Central standard portions. The
+// longest matching fragment wins, so "eggplant" is not read as "egg".
+var standardPortions = map[string]float64{
+	"chicken breast": 140,
+	"chicken thigh":  115,
+	"salmon":         154,
+	"steak":          225,
+	"egg":            50,
+	"eggplant":       458,
+	"apple":          182,
+	"banana":         118,
+	"orange":         131,
+	"pear":           178,
+	"avocado":        150,
+	"potato":         213,
+	"sweet potato":   130,
+	"bagel":          105,
+	"tortilla":       49,
+	"bread":          28,
+	"rice":           158,
+	"pasta":          140,
+	"oatmeal":        234,
+	"yogurt":         170,
+	"broccoli":       91,
+	"carrot":         61,
+	"tomato":         123,
+}
+
+// isGenericAmount reports whether an entry was logged without a real amount:
+// no amount, Cronometer's 100g default, or a single unspecified serving
+func isGenericAmount(e FoodEntry) bool {
+	unit := normalizeUnit(e.Unit)
+	switch {
+	case e.Amount <= 0:
+		return true
+	case unit == "g" && e.Amount == defaultServingGrams:
+		return true
+	case (unit == "serving" || unit == "servings") && e.Amount == 1:
+		return true
+	}
+	return false
+}
+
+// InferStandardPortion suggests a standard portion in grams for an entry
+// logged with a generic amount. It returns false when the amount looks
+// deliberate or the food has no standard portion.
+func InferStandardPortion(entry FoodEntry) (float64, string, bool) {
+	if !isGenericAmount(entry) {
+		return 0, "", false
+	}
+	key := foodKey(entry.FoodName)
+	best := ""
+	for food := range standardPortions {
+		if strings.Contains(key, food) && len(food) > len(best) {
+			best = food
+		}
+	}
+	if best == "" {
+		return 0, "", false
+	}
+	return standardPortions[best], "g", true
+}
+
+// applyInferredPortions replaces generic amounts with standard portions and
+// marks the entries as inferred. Entries logged at the 100g default have
+// their macros, and their day's totals, scaled to the new amount; other
+// units have no known weight, so only the amount changes. It returns how
+// many entries were inferred.
+func applyInferredPortions(records []DailyNutrition, diary []FoodEntry) int {
+	byDate := make(map[string]*DailyNutrition, len(records))
+	for i := range records {
+		byDate[records[i].Date] = &records[i]
+	}
+
+	inferred := 0
+	for i := range diary {
+		e := &diary[i]
+		grams, unit, ok := InferStandardPortion(*e)
+		if !ok {
+			continue
+		}
+		if normalizeUnit(e.Unit) == "g" && e.Amount > 0 {
+			scale := grams / e.Amount
+			before := *e
+			e.Calories *= scale
+			e.Protein *= scale
+			e.Fat *= scale
+			e.Carbs *= scale
+			if d, ok := byDate[e.Date]; ok {
+				d.Calories += e.Calories - before.Calories
+				d.Protein += e.Protein - before.Protein
+				d.Fat += e.Fat - before.Fat
+				d.Carbs += e.Carbs - before.Carbs
+			}
+		}
+		e.Amount = grams
+		e.Unit = unit
+		e.Inferred = true
+		inferred++
+	}
+	return inferred
+}
@@ -0,0 +1,39 @@
+package main
+
+// pralNeutralBand is how far a PRAL score can be from zero, in mEq/day, and
+// still count as neutral
+const pralNeutralBand = 5.0
+
+// PRALReport is a day's potential renal acid load
+type PRALReport struct {
+	Score          float64 `json:"score"`
+	Classification string  `json:"classification"`
+}
+
+// PRALScore is the potential renal acid load in mEq/day (Remer & Manz 1995)
+// from protein in g and phosphorus, potassium, magnesium, and calcium in mg.
+// Positive scores are acid-forming and negative ones base-forming.
+func PRALScore(protein, phosphorus, potassium, magnesium, calcium float64) float64 {
+	return 0.49*protein + 0.037*phosphorus - 0.021*potassium - 0.026*magnesium - 0.013*calcium
+}
+
+// PRALClassification classifies a PRAL score as "acid-forming", "neutral", or "base-forming"
+func PRALClassification(score float64) string {
+	switch {
+	case score > pralNeutralBand:
+		return "acid-forming"
+	case score < -pralNeutralBand:
+		return "base-forming"
+	default:
+		return "neutral"
+	}
+}
+
+// applyPRAL attaches a PRAL report to each day
+func applyPRAL(records []DailyNutrition) {
+	for i := range records {
+		d := &records[i]
+		score := PRALScore(d.Protein, d.Phosphorus, d.Potassium, d.Magnesium, d.Calcium)
+		d.PRAL = &PRALReport{Score: score, Classification: PRALClassification(score)}
+	}
+}
@@ -0,0 +1,132 @@
+package main
+
+import "sort"
+
+// topPreferredFoods is how many foods FoodPreferenceModel.TopFoods keeps
+const topPreferredFoods = 10
+
+// FoodPreferenceModel captures the foods a user eats regularly, learned from the diary
+type FoodPreferenceModel struct {
+	TopFoods        []PreferredFood    `json:"top_foods"`
+	MealsByCategory map[string]string  `json:"meals_by_category"`
+	TypicalPortions map[string]Portion `json:"typical_portions"`
+	MealDays        map[string]int     `json:"meal_days"`
+}
+
+// PreferredFood is a regularly eaten food ranked by calorie contribution
+type PreferredFood struct {
+	FoodName      string  `json:"food_name"`
+	Occurrences   int     `json:"occurrences"`
+	TotalCalories float64 `json:"total_calories"`
+}
+
+// Portion is a typical serving of a food
+type Portion struct {
+	Amount float64 `json:"amount"`
+	Unit   string  `json:"unit"`
+}
+
+// LearnFoodPreferences builds a FoodPreferenceModel from diary entries,
+// ignoring foods logged fewer than minOccurrences times. Meals by category
+// holds the meal each food category is most often logged in, and typical
+// portions hold the median amount in each food's most common unit. Meal days
+// counts the distinct days each meal was logged, across every entry.
+func LearnFoodPreferences(entries []FoodEntry, minOccurrences int) FoodPreferenceModel {
+	byFood := make(map[string][]FoodEntry)
+	for _, e := range entries {
+		key := foodKey(e.FoodName)
+		byFood[key] = append(byFood[key], e)
+	}
+
+	model := FoodPreferenceModel{
+		MealsByCategory: make(map[string]string),
+		TypicalPortions: make(map[string]Portion),
+		MealDays:        make(map[string]int),
+	}
+	mealCounts := make(map[string]map[string]int)
+
+	mealSeen := make(map[string]bool)
+	for _, e := range entries {
+		if e.Meal == "" || mealSeen[e.Meal+"|"+e.Date] {
+			continue
+		}
+		mealSeen[e.Meal+"|"+e.Date] = true
+		model.MealDays[e.Meal]++
+	}
+
+	for _, foodEntries := range byFood {
+		if len(foodEntries) < minOccurrences {
+			continue
+		}
+		name := foodEntries[0].FoodName
+
+		food := PreferredFood{FoodName: name, Occurrences: len(foodEntries)}
+		for _, e := range foodEntries {
+			food.TotalCalories += e.Calories
+			if e.Category != "" && e.Meal != "" {
+				if mealCounts[e.Category] == nil {
+					mealCounts[e.Category] = make(map[string]int)
+				}
+				mealCounts[e.Category][e.Meal]++
+			}
+		}
+		model.TopFoods = append(model.TopFoods, food)
+		model.TypicalPortions[name] = typicalPortion(foodEntries)
+	}
+
+	sort.Slice(model.TopFoods, func(i, j int) bool {
+		return model.TopFoods[i].TotalCalories > model.TopFoods[j].TotalCalories
+	})
+	if len(model.TopFoods) > topPreferredFoods {
+		model.TopFoods = model.TopFoods[:topPreferredFoods]
+	}
+
+	for category, counts := range mealCounts {
+		model.MealsByCategory[category] = mostFrequent(counts)
+	}
+	return model
+}
+
+// typicalPortion returns the median amount in the most commonly used unit
+func typicalPortion(entries []FoodEntry) Portion {
+	unitCounts := make(map[string]int)
+	for _, e := range entries {
+		unitCounts[e.Unit]++
+	}
+	unit := mostFrequent(unitCounts)
+
+	var amounts []float64
+	for _, e := range entries {
+		if e.Unit == unit {
+			amounts = append(amounts, e.Amount)
+		}
+	}
+	return Portion{Amount: median(amounts), Unit: unit}
+}
+
+// mostFrequent returns the key with the highest count, breaking ties alphabetically
+func mostFrequent(counts map[string]int) string {
+	best := ""
+	bestCount := -1
+	for key, count := range counts {
+		if count > bestCount || (count == bestCount && key < best) {
+			best = key
+			bestCount = count
+		}
+	}
+	return best
+}
+
+// median returns the median of values, or 0 when empty
+func median(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+	mid := len(sorted) / 2
+	if len(sorted)%2 == 0 {
+		return (sorted[mid-1] + sorted[mid]) / 2
+	}
+	return sorted[mid]
+}
@@ -0,0 +1,44 @@
+package main
+
+import "container/heap"
+
+// PriorityQueue is a heap-backed queue that always dequeues the item that
+// sorts first according to less
+type PriorityQueue[T any] struct {
+	items []T
+	less  func(a, b T) bool
+}
+
+// NewPriorityQueue creates an empty queue ordered by less
+func NewPriorityQueue[T any](less func(a, b T) bool) *PriorityQueue[T] {
+	return &PriorityQueue[T]{less: less}
+}
+
+// Enqueue adds an item to the queue
+func (q *PriorityQueue[T]) Enqueue(item T) {
+	heap.Push(q, item)
+}
+
+// Dequeue removes and returns the highest-priority item; the queue must not be empty
+func (q *PriorityQueue[T]) Dequeue() T {
+	return heap.Pop(q).(T)
+}
+
+// Len implements heap.Interface
+func (q *PriorityQueue[T]) Len() int { return len(q.items) }
+
+// Less implements heap.Interface
+func (q *PriorityQueue[T]) Less(i, j int) bool { return q.less(q.items[i], q.items[j]) }
+
+// Swap implements heap.Interface
+func (q *PriorityQueue[T]) Swap(i, j int) { q.items[i], q.items[j] = q.items[j], q.items[i] }
+
+// Push implements heap.Interface; use Enqueue instead
+func (q *PriorityQueue[T]) Push(x interface{}) { q.items = append(q.items, x.(T)) }
+
+// Pop implements heap.Interface; use Dequeue instead
+func (q *PriorityQueue[T]) Pop() interface{} {
+	last := q.items[len(q.items)-1]
+	q.items = q.items[:len(q.items)-1]
+	return last
+}
@@ -0,0 +1,99 @@
+package main
+
+import (
+	"math"
+	"sort"
+)
+
+// Muscle protein synthesis is maximized at about 0.4g/kg per meal over four
+// meals (Schoenfeld & Aragon, JISSN 2018)
+const (
+	proteinPerMealGPerKg = 0.4
+	defaultMealsPerDay   = 4
+)
+
+// MealProtein is a meal's average protein on the days it was logged
+type MealProtein struct {
+	Meal           string  `json:"meal"`
+	AvgProteinG    float64 `json:"avg_protein_g"`
+	PercentOfTotal float64 `json:"percent_of_total"`
+	MeetsTarget    bool    `json:"meets_target"`
+}
+
+// ProteinDistReport describes how protein is spread across the day's meals
+type ProteinDistReport struct {
+	Meals             []MealProtein `json:"meals"`
+	TargetPerMealG    float64       `json:"target_per_meal_g"`
+	MealsPerDay       int           `json:"meals_per_day"`
+	DistributionScore float64       `json:"distribution_score"`
+}
+
+// ProteinDistributionScore averages protein per diary meal and scores how
+// evenly each day's protein was split across at least mealsPerDay meals.
+// A day's score is 1 minus the coefficient of variation of its meal protein,
+// scaled so 1 is a perfectly even split and 0 is all protein in one meal; the
+// report's score is the mean across days.
+func ProteinDistributionScore(entries []FoodEntry, weightKg float64, mealsPerDay int) ProteinDistReport {
+	report := ProteinDistReport{
+		Meals:          []MealProtein{},
+		TargetPerMealG: proteinPerMealGPerKg * weightKg,
+		MealsPerDay:    mealsPerDay,
+	}
+
+	byDate := make(map[string]map[string]float64)
+	for _, e := range entries {
+		if byDate[e.Date] == nil {
+			byDate[e.Date] = make(map[string]float64)
+		}
+		byDate[e.Date][e.Meal] += e.Protein
+	}
+	if len(byDate) == 0 {
+		return report
+	}
+
+	totals := make(map[string]float64)
+	logged := make(map[string]int)
+	var total, scoreSum float64
+	for _, meals := range byDate {
+		var protein []float64
+		for meal, grams := range meals {
+			totals[meal] += grams
+			logged[meal]++
+			total += grams
+			protein = append(protein, grams)
+		}
+		for len(protein) < mealsPerDay {
+			protein = append(protein, 0)
+		}
+		scoreSum += evenness(protein)
+	}
+	report.DistributionScore = scoreSum / float64(len(byDate))
+
+	for meal, grams := range totals {
+		entry := MealProtein{Meal: meal, AvgProteinG: grams / float64(logged[meal])}
+		if total > 0 {
+			entry.PercentOfTotal = grams / total * 100
+		}
+		entry.MeetsTarget = entry.AvgProteinG >= report.TargetPerMealG
+		report.Meals = append(report.Meals, entry)
+	}
+	sort.Slice(report.Meals, func(i, j int) bool {
+		return report.Meals[i].Meal < report.Meals[j].Meal
+	})
+	return report
+}
+
+// evenness returns 1 minus the coefficient of variation of values divided by
+// its maximum, sqrt(n-1), so the result runs from 0 to 1
+func evenness(values []float64) float64 {
+	avg := mean(values)
+	if len(values) < 2 || avg == 0 {
+		return 0
+	}
+	var squares float64
+	for _, v := range values {
+		squares += (v - avg) * (v - avg)
+	}
+	cv := math.Sqrt(squares/float64(len(values))) / avg
+	return 1 - cv/math.Sqrt(float64(len(values)-1))
+}
@@ -0,0 +1,91 @@
+package main
+
+import "strings"
+
+// diaasScores are approximate adult DIAAS values for common protein foods,
+// from FAO (2013) and the DIAAS studies since (e.g. Mathai et al., British
+// Journal of Nutrition 2017). Keys are matched as substrings of lowercased
+// diary food names.
+var diaasScores = map[string]float64{
+	"milk":        1.14,
+	"whey":        1.09,
+	"egg":         1.13,
+	"cheese":      1.15,
+	"yogurt":      1.08,
+	"beef":        1.11,
+	"pork":        1.14,
+	"chicken":     1.08,
+	"turkey":      1.08,
+	"salmon":      1.00,
+	"tuna":        1.00,
+	"soy":         0.90,
+	"tofu":        0.97,
+	"pea protein": 0.82,
+	"chickpea":    0.83,
+	"lentil":      0.75,
+	"bean":        0.59,
+	"peanut":      0.43,
+	"oat":         0.57,
+	"rice":        0.59,
+	"wheat":       0.48,
+	"bread":       0.48,
+	// Plant milks, so they don't match "milk"
+	"soy milk":    0.90,
+	"oat milk":    0.57,
+	"almond milk": 0.40,
+}
+
+// ProteinQualityReport is the protein-weighted DIAAS of a day's diary foods
+// found in the DIAAS table
+type ProteinQualityReport struct {
+	MatchedProteinG            float64 `json:"matched_protein_g"`
+	WeightedDIAAS              float64 `json:"weighted_diaas"`
+	HighQualityProteinFraction float64 `json:"high_quality_protein_fraction"`
+}
+
+// tableMatch returns a food name's value from a table keyed by name
+// fragments, preferring the longest matching key
+func tableMatch(name string, table map[string]float64) (float64, bool) {
+	key := foodKey(name)
+	best := ""
+	for food := range table {
+		if strings.Contains(key, food) && len(food) > len(best) {
+			best = food
+		}
+	}
+	score, ok := table[best]
+	return score, ok && best != ""
+}
+
+// EstimateProteinQuality weights each matched entry's DIAAS by its protein.
+// HighQualityProteinFraction is the share of matched protein from foods with
+// a DIAAS above 1.0. Entries not in the table are left out.
+func EstimateProteinQuality(entries []FoodEntry, diaasTable map[string]float64) ProteinQualityReport {
+	var report ProteinQualityReport
+	var weighted, highQuality float64
+	for _, e := range entries {
+		score, ok := tableMatch(e.FoodName, diaasTable)
+		if !ok || e.Protein <= 0 {
+			continue
+		}
+		report.MatchedProteinG += e.Protein
+		weighted += score * e.Protein
+		if score > 1.0 {
+			highQuality += e.Protein
+		}
+	}
+	if report.MatchedProteinG > 0 {
+		report.WeightedDIAAS = weighted / report.MatchedProteinG
+		report.HighQualityProteinFraction = highQuality / report.MatchedProteinG
+	}
+	return report
+}
+
+// applyProteinQuality estimates each day's protein quality from its diary
+func applyProteinQuality(records []DailyNutrition, diary []FoodEntry) {
+	byDate := groupEntriesByDate(diary)
+	for i := range records {
+		report := EstimateProteinQuality(byDate[records[i].Date], diaasScores)
+		records[i].ProteinQuality = &report
+	}
+}
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"fmt"
+	"math"
+)
+
+// DailyTarget is the calories per day that keeps the rest of the week on budget
+type DailyTarget struct {
+	Calories  float64 `json:"calories"`
+	Days      int     `json:"days"`
+	Remaining float64 `json:"remaining"`
+}
+
+// RebalanceWeek spreads what is left of the weekly calorie budget evenly over
+// the days left. Remaining is negative once the budget is already exceeded.
+func RebalanceWeek(soFar []DailyNutrition, weeklyBudget float64, daysLeft int) DailyTarget {
+	var eaten float64
+	for _, d := range soFar {
+		eaten += d.Calories
+	}
+	target := DailyTarget{Days: daysLeft, Remaining: weeklyBudget - eaten}
+	if daysLeft > 0 {
+		target.Calories = math.Max(target.Remaining, 0) / float64(daysLeft)
+	}
+	return target
+}
+
+// formatRebalance renders a rebalance target as a sentence
+func formatRebalance(target DailyTarget) string {
+	if target.Remaining < 0 {
+		return fmt.Sprintf("You are %.0f calories over this week's budget with %d days left.", -target.Remaining, target.Days)
+	}
+	return fmt.Sprintf("Eat %.0f calories per day for the next %d days to stay on budget.", target.Calories, target.Days)
+}
+
+// currentWeek returns the records in the same ISO week as today
+func currentWeek(records []DailyNutrition, today string) []DailyNutrition {
+	week, err := isoWeek(today)
+	if err != nil {
+		return nil
+	}
+	var matched []DailyNutrition
+	for _, d := range records {
+		if w, err := isoWeek(d.Date); err == nil && w == week {
+			matched = append(matched, d)
+		}
+	}
+	return matched
+}
@@ -0,0 +1,94 @@
+package main
+
+import "fmt"
+
+// Oxygen uptake conversions: one MET is 3.5 ml O2/kg/min at rest, and each
+// litre of oxygen burned yields about 5 kcal
+const (
+	restingVO2MlPerKgMin = 3.5
+	kcalPerLitreO2       = 5.0
+)
+
+// REEReport is resting energy expenditure and, when weight was logged, the
+// TDEE implied by intake and the weight trend
+type REEReport struct {
+	Method   string  `json:"method"`
+	WeightKg float64 `json:"weight_kg"`
+	VO2Max   float64 `json:"vo2max,omitempty"`
+	// METCapacity is VO2 max in METs, the multiple of REE reachable at peak effort
+	METCapacity float64 `json:"met_capacity,omitempty"`
+	REE         float64 `json:"ree"`
+	// ObservedTDEE is mean daily calories less the weight trend at 3500 kcal per pound
+	ObservedTDEE   *float64 `json:"observed_tdee,omitempty"`
+	ActivityFactor *float64 `json:"activity_factor,omitempty"`
+}
+
+// RestingMETREE estimates REE in kcal/day as one MET of oxygen uptake
+// (3.5 ml/kg/min) over a day, at 5 kcal per litre of oxygen. It returns 0
+// without a positive weight.
+func RestingMETREE(weightKg float64) float64 {
+	if weightKg <= 0 {
+		return 0
+	}
+	litresPerDay := restingVO2MlPerKgMin * weightKg * 24 * 60 / 1000
+	return litresPerDay * kcalPerLitreO2
+}
+
+// REEFromVO2Max estimates REE in kcal/day for a VO2 max in ml O2/kg/min.
+// Resting uptake is one MET (3.5 ml/kg/min) whatever the VO2 max, so REE is
+// RestingMETREE and VO2 max, which must exceed one MET, only sets the MET
+// capacity reported beside it. It returns 0 for a VO2 max at or below rest.
+func REEFromVO2Max(vo2max, weightKg float64) float64 {
+	if vo2max <= restingVO2MlPerKgMin {
+		return 0
+	}
+	return RestingMETREE(weightKg)
+}
+
+// computeREE estimates REE from VO2 max, taken from vo2max or else the latest
+// logged VO2 Max. Without one it uses Mifflin-St Jeor when heightCm, age, and
+// sex are all set, and otherwise weight alone at one MET. It uses the latest
+// logged weight, or weightKg, and compares REE to the TDEE observed from the
+// records and the weight trend when there are weigh-ins.
+func computeREE(records []DailyNutrition, biometrics []BiometricEntry, vo2max, weightKg, heightCm, age float64, sex string) (*REEReport, error) {
+	if logged, ok := latestMetric(biometrics, "weight"); ok {
+		weightKg = logged
+	}
+	if weightKg <= 0 {
+		return nil, fmt.Errorf("no weight logged; set -weight-kg")
+	}
+	if vo2max <= 0 {
+		vo2max, _ = latestMetric(biometrics, "vo2 max")
+	}
+
+	report := &REEReport{WeightKg: weightKg}
+	bmr, bmrErr := MifflinStJeorBMR(weightKg, heightCm, age, sex)
+	switch {
+	case vo2max > 0:
+		report.Method = "vo2max"
+		report.VO2Max = vo2max
+		report.METCapacity = vo2max / restingVO2MlPerKgMin
+		report.REE = REEFromVO2Max(vo2max, weightKg)
+		if report.REE == 0 {
+			return nil, fmt.Errorf("VO2 max of %g ml/kg/min is not above resting uptake", vo2max)
+		}
+	case bmrErr == nil:
+		report.Method = "mifflin_st_jeor"
+		report.REE = bmr
+	default:
+		report.Method = "met_resting"
+		report.REE = RestingMETREE(weightKg)
+	}
+
+	if trend, _, err := weeklyWeightTrend(weightSeries(biometrics)); err == nil && len(records) > 0 {
+		var calories float64
+		for _, d := range records {
+			calories += d.Calories
+		}
+		tdee := calories/float64(len(records)) - trend*kcalPerLb/7
+		factor := tdee / report.REE
+		report.ObservedTDEE = &tdee
+		report.ActivityFactor = &factor
+	}
+	return report, nil
+}
@@ -0,0 +1,136 @@
+package main
+
+import (
+	"math"
+	"testing"
+)
+
+func TestRestingMETREE(t *testing.T) {
+	tests := []struct {
+		weightKg float64
+		want     float64
+	}{
+		// 3.5 ml/kg/min × 70 kg × 1440 min = 352.8 L O2, at 5 kcal/L
+		{70, 1764},
+		{100, 2520},
+		{0, 0},
+	}
+	for _, tt := range tests {
+		if got := RestingMETREE(tt.weightKg); math.Abs(got-tt.want) > 1e-9 {
+			t.Errorf("RestingMETREE(%g) = %g, want %g", tt.weightKg, got, tt.want)
+		}
+	}
+}
+
+func TestREEFromVO2Max(t *testing.T) {
+	tests := []struct {
+		vo2max, weightKg float64
+		want             float64
+	}{
+		{45, 70, 1764},
+		{60, 70, 1764},
+		{3.5, 70, 0},
+		{45, 0, 0},
+	}
+	for _, tt := range tests {
+		if got := REEFromVO2Max(tt.vo2max, tt.weightKg); math.Abs(got-tt.want) > 1e-9 {
+			t.Errorf("REEFromVO2Max(%g, %g) = %g, want %g", tt.vo2max, tt.weightKg, got, tt.want)
+		}
+	}
+}
+
+func TestComputeREEMethod(t *testing.T) {
+	tests := []struct {
+		name       string
+		vo2max     float64
+		heightCm   float64
+		age        float64
+		sex        string
+		wantMethod string
+		wantREE    float64
+		wantErr    bool
+	}{
+		{"weight only", 0, 0, 0, "", "met_resting", 1764, false},
+		{"sex without height", 0, 0, 0, "female", "met_resting", 1764, false},
+		// 10×70 + 6.25×175 - 5×30 + 5
+		{"full body measurements", 0, 175, 30, "male", "mifflin_st_jeor", 1648.75, false},
+		{"height and age without sex", 0, 175, 30, "", "met_resting", 1764, false},
+		{"vo2max over body measurements", 45, 175, 30, "male", "vo2max", 1764, false},
+		{"vo2max at rest", 3, 0, 0, "", "", 0, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			report, err := computeREE(nil, nil, tt.vo2max, 70, tt.heightCm, tt.age, tt.sex)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("computeREE = %+v, want an error", report)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if report.Method != tt.wantMethod || math.Abs(report.REE-tt.wantREE) > 1e-9 {
+				t.Errorf("method %s REE %g, want %s %g", report.Method, report.REE, tt.wantMethod, tt.wantREE)
+			}
+			if report.ObservedTDEE != nil {
+				t.Errorf("observed TDEE = %g, want none without weigh-ins", *report.ObservedTDEE)
+			}
+		})
+	}
+}
+
+func TestComputeREEObservedTDEE(t *testing.T) {
+	// Losing 0.5 kg a week on 2000 kcal a day
+	biometrics := []BiometricEntry{
+		{Date: "2024-03-01", Metric: "Weight", Unit: "kg", Amount: 80},
+		{Date: "2024-03-08", Metric: "Weight", Unit: "kg", Amount: 79.5},
+		{Date: "2024-03-15", Metric: "Weight", Unit: "kg", Amount: 79},
+	}
+	records := []DailyNutrition{{Calories: 1900}, {Calories: 2100}}
+
+	report, err := computeREE(records, biometrics, 0, 0, 0, 0, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.WeightKg != 79 {
+		t.Errorf("weight = %g, want the latest weigh-in of 79", report.WeightKg)
+	}
+	lbsPerWeek := 0.5 / toKg(1, "lb")
+	wantTDEE := 2000 + lbsPerWeek*kcalPerLb/7
+	if report.ObservedTDEE == nil || math.Abs(*report.ObservedTDEE-wantTDEE) > 1 {
+		t.Fatalf("observed TDEE = %v, want about %.0f", report.ObservedTDEE, wantTDEE)
+	}
+	if math.Abs(*report.ActivityFactor-wantTDEE/RestingMETREE(79)) > 0.01 {
+		t.Errorf("activity factor = %g, want %g", *report.ActivityFactor, wantTDEE/RestingMETREE(79))
+	}
+}
+
+func TestComputeREENoWeight(t *testing.T) {
+	if _, err := computeREE(nil, nil, 0, 0, 175, 30, "male"); err == nil {
+		t.Error("computeREE without a weight succeeded, want an error")
+	}
+}
+
+func TestComputeREELoggedVO2Max(t *testing.T) {
+	biometrics := []BiometricEntry{
+		{Date: "2024-03-01", Metric: "VO2 Max", Unit: "ml/kg/min", Amount: 42},
+		{Date: "2024-03-10", Metric: "VO2 Max", Unit: "ml/kg/min", Amount: 49},
+	}
+	report, err := computeREE(nil, biometrics, 0, 70, 0, 0, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Method != "vo2max" || report.VO2Max != 49 || math.Abs(report.METCapacity-14) > 1e-9 {
+		t.Errorf("report = %+v, want the latest VO2 Max of 49 at 14 METs", report)
+	}
+
+	// -vo2max wins over the logged value
+	report, err = computeREE(nil, biometrics, 45, 70, 0, 0, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.VO2Max != 45 {
+		t.Errorf("VO2 max = %g, want the -vo2max value of 45", report.VO2Max)
+	}
+}
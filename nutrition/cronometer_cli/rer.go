@@ -0,0 +1,46 @@
+package main
+
+// RER boundaries for the primary fuel source: near 0.7 the body burns mostly
+// fat and near 1.0 mostly carbohydrate
+const (
+	fatFuelRER  = 0.8
+	carbFuelRER = 0.9
+)
+
+// RERFromMacros estimates the respiratory exchange ratio from the grams of
+// carbs and fat eaten, weighting carbohydrate oxidation's RER of 1.0 against
+// fat's 0.707. It returns 0 when neither was eaten.
+func RERFromMacros(carbs, fat float64) float64 {
+	denominator := carbs*0.746 + fat*0.707*1.0/0.707
+	if denominator <= 0 {
+		return 0
+	}
+	return (carbs*0.746 + fat*0.707) / denominator
+}
+
+// PrimaryFuelSource names the fuel an RER points to: "fat" below 0.8,
+// "carbohydrate" above 0.9, and "mixed" in between
+func PrimaryFuelSource(rer float64) string {
+	switch {
+	case rer < fatFuelRER:
+		return "fat"
+	case rer > carbFuelRER:
+		return "carbohydrate"
+	default:
+		return "mixed"
+	}
+}
+
+// applyRER sets each day's estimated RER and primary fuel, skipping days
+// without carbs or fat
+func applyRER(records []DailyNutrition) {
+	for i := range records {
+		d := &records[i]
+		rer := RERFromMacros(d.Carbs, d.Fat)
+		if rer == 0 {
+			continue
+		}
+		d.RER = &rer
+		d.PrimaryFuelSource = PrimaryFuelSource(rer)
+	}
+}
@@ -0,0 +1,149 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+)
+
+// RollingEstimate is a trailing average with an optional confidence interval
+type RollingEstimate struct {
+	Mean    float64  `json:"mean"`
+	CILower *float64 `json:"ci_lower,omitempty"`
+	CIUpper *float64 `json:"ci_upper,omitempty"`
+}
+
+// RollingAverage holds the trailing averages ending on a day
+type RollingAverage struct {
+	Days       int             `json:"days"`
+	Confidence float64         `json:"confidence,omitempty"`
+	Calories   RollingEstimate `json:"calories"`
+	Fat        RollingEstimate `json:"fat"`
+	Carbs      RollingEstimate `json:"carbs"`
+	Protein    RollingEstimate `json:"protein"`
+	Fiber      RollingEstimate `json:"fiber"`
+}
+
+// ConfidenceInterval returns the t-distribution interval for the mean of
+// values at the given confidence level (e.g. 0.95), using n-1 degrees of freedom
+func ConfidenceInterval(values []float64, confidence float64) (lower, upper float64) {
+	m := mean(values)
+	if len(values) < 2 {
+		return m, m
+	}
+	n := float64(len(values))
+	margin := studentTQuantile(1-(1-confidence)/2, n-1) * sampleStdDev(values) / math.Sqrt(n)
+	return m - margin, m + margin
+}
+
+// validateConfidence validates a -ci value
+func validateConfidence(value float64) error {
+	if value <= 0 || value >= 1 {
+		return fmt.Errorf("confidence must be between 0 and 1, got %v", value)
+	}
+	return nil
+}
+
+// applyRollingAverages adds the average of the trailing window logged days to
+// each day that has a full window. A confidence of 0 leaves out the intervals.
+func applyRollingAverages(records []DailyNutrition, window int, confidence float64) {
+	order := make([]int, len(records))
+	for i := range order {
+		order[i] = i
+	}
+	sort.Slice(order, func(a, b int) bool {
+		return records[order[a]].Date < records[order[b]].Date
+	})
+
+	for pos := window - 1; pos < len(order); pos++ {
+		days := order[pos-window+1 : pos+1]
+		estimate := func(field func(DailyNutrition) float64) RollingEstimate {
+			values := make([]float64, len(days))
+			for i, idx := range days {
+				values[i] = field(records[idx])
+			}
+			e := RollingEstimate{Mean: mean(values)}
+			if confidence > 0 {
+				lower, upper := ConfidenceInterval(values, confidence)
+				e.CILower, e.CIUpper = &lower, &upper
+			}
+			return e
+		}
+
+		records[order[pos]].Rolling = &RollingAverage{
+			Days:       window,
+			Confidence: confidence,
+			Calories:   estimate(func(d DailyNutrition) float64 { return d.Calories }),
+			Fat:        estimate(func(d DailyNutrition) float64 { return d.Fat }),
+			Carbs:      estimate(func(d DailyNutrition) float64 { return d.Carbs }),
+			Protein:    estimate(func(d DailyNutrition) float64 { return d.Protein }),
+			Fiber:      estimate(func(d DailyNutrition) float64 { return d.Fiber }),
+		}
+	}
+}
+
+// applyCumulativeProtein sets each day's running protein total from the first
+// day in the range, in date order
+func applyCumulativeProtein(records []DailyNutrition) {
+	order := make([]int, len(records))
+	for i := range order {
+		order[i] = i
+	}
+	sort.Slice(order, func(a, b int) bool {
+		return records[order[a]].Date < records[order[b]].Date
+	})
+
+	var total float64
+	for _, idx := range order {
+		total += records[idx].Protein
+		cumulative := total
+		records[idx].CumulativeProtein = &cumulative
+	}
+}
+
+// storedMicronutrients are the nutrients the body stores, so -cumulative-micros
+// totals them over a week rather than judging each day alone
+var storedMicronutrients = []string{"vitamin_k", "b12", "vitamin_d"}
+
+// microTotalDays is the window for -cumulative-micros
+const microTotalDays = 7
+
+// RollingMicronutrients returns, for each record, the sum of the named
+// nutrient over the window days ending on its date. Days missing from records
+// count as zero, and records with unparseable dates get 0.
+func RollingMicronutrients(records []DailyNutrition, field string, window int) []float64 {
+	dates := make([]time.Time, len(records))
+	for i := range records {
+		dates[i], _ = time.Parse("2006-01-02", records[i].Date)
+	}
+
+	totals := make([]float64, len(records))
+	for i := range records {
+		if dates[i].IsZero() {
+			continue
+		}
+		first := dates[i].AddDate(0, 0, -(window - 1))
+		for j := range records {
+			if dates[j].IsZero() || dates[j].Before(first) || dates[j].After(dates[i]) {
+				continue
+			}
+			if value := nutrientField(&records[j], field); value != nil {
+				totals[i] += *value
+			}
+		}
+	}
+	return totals
+}
+
+// applyMicronutrientTotals adds the 7-day totals of the stored micronutrients to each day
+func applyMicronutrientTotals(records []DailyNutrition) {
+	for i := range records {
+		records[i].MicroTotals = make(map[string]float64, len(storedMicronutrients))
+	}
+	for _, name := range storedMicronutrients {
+		for i, total := range RollingMicronutrients(records, name, microTotalDays) {
+			records[i].MicroTotals[name] = total
+		}
+	}
+}
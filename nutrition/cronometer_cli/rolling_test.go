@@ -0,0 +1,80 @@
+package main
+
+import (
+	"math"
+	"testing"
+)
+
+func TestStudentTQuantile(t *testing.T) {
+	// Two-sided 95% critical values from standard t tables
+	tests := []struct {
+		df   float64
+		want float64
+	}{
+		{1, 12.706},
+		{4, 2.776},
+		{29, 2.045},
+	}
+	for _, tt := range tests {
+		if got := studentTQuantile(0.975, tt.df); math.Abs(got-tt.want) > 0.001 {
+			t.Errorf("studentTQuantile(0.975, %g) = %.4f, want %.3f", tt.df, got, tt.want)
+		}
+	}
+}
+
+func TestConfidenceInterval(t *testing.T) {
+	tests := []struct {
+		name       string
+		values     []float64
+		wantLower  float64
+		wantUpper  float64
+		confidence float64
+	}{
+		// Mean 2, sd √2, n 2: the margin is t(1) · √2 / √2
+		{"two values", []float64{1, 3}, 2 - 12.706, 2 + 12.706, 0.95},
+		// Mean 3, sd √2.5, n 5: the margin is t(4) · √2.5 / √5
+		{"five values", []float64{1, 2, 3, 4, 5}, 3 - 2.776*math.Sqrt(0.5), 3 + 2.776*math.Sqrt(0.5), 0.95},
+		{"one value", []float64{7}, 7, 7, 0.95},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lower, upper := ConfidenceInterval(tt.values, tt.confidence)
+			if math.Abs(lower-tt.wantLower) > 0.01 || math.Abs(upper-tt.wantUpper) > 0.01 {
+				t.Errorf("ConfidenceInterval = [%.3f, %.3f], want [%.3f, %.3f]", lower, upper, tt.wantLower, tt.wantUpper)
+			}
+		})
+	}
+}
+
+func TestApplyCumulativeProtein(t *testing.T) {
+	// Out of date order, so the running total must follow the dates
+	records := []DailyNutrition{
+		{Date: "2024-03-03", Protein: 120},
+		{Date: "2024-03-01", Protein: 150},
+		{Date: "2024-03-04", Protein: 0},
+		{Date: "2024-03-02", Protein: 90},
+	}
+	applyCumulativeProtein(records)
+
+	byDate := make(map[string]float64)
+	var sum float64
+	for _, d := range records {
+		byDate[d.Date] = *d.CumulativeProtein
+		sum += d.Protein
+	}
+	previous := 0.0
+	for _, date := range []string{"2024-03-01", "2024-03-02", "2024-03-03", "2024-03-04"} {
+		if byDate[date] < previous {
+			t.Errorf("%s: cumulative protein %g dropped below %g", date, byDate[date], previous)
+		}
+		previous = byDate[date]
+	}
+	// The last day's total is the number of days times the average protein
+	avgProtein := sum / float64(len(records))
+	if want := float64(len(records)) * avgProtein; math.Abs(previous-want) > 1e-9 {
+		t.Errorf("final cumulative protein = %g, want %g", previous, want)
+	}
+	if byDate["2024-03-01"] != 150 || byDate["2024-03-02"] != 240 {
+		t.Errorf("running totals = %v, want 150 then 240", byDate)
+	}
+}
@@ -0,0 +1,60 @@
+package main
+
+import "math"
+
+// Satiety parameters. Protein is the most satiating macro (Weigle et al. 2005)
+// and fiber adds bulk; the fiber term is scaled to the 14g per 1000 kcal
+// Dietary Guidelines target.
+const (
+	satietyBase           = 1.0
+	maxProteinCarbRatio   = 2.0
+	fiberTargetPer1000    = 14.0
+	maxFiberTerm          = 2.0
+	lowHungerSatiety      = 3.0
+	moderateHungerSatiety = 2.0
+	// Days under both of these are at risk of overeating later
+	overeatingCalories = 1500.0
+	overeatingSatiety  = 2.0
+)
+
+// SatietyFactor models how filling a day's food is, from 1 to 5: a base of 1,
+// plus the protein-to-carb gram ratio capped at 2, plus fiber per 1000 kcal
+// as a share of the 14g target, capped at 2
+func SatietyFactor(protein, carbs, fiber, calories float64) float64 {
+	var ratio float64
+	switch {
+	case carbs > 0:
+		ratio = math.Min(protein/carbs, maxProteinCarbRatio)
+	case protein > 0:
+		ratio = maxProteinCarbRatio
+	}
+	var fiberTerm float64
+	if calories > 0 {
+		fiberTerm = math.Min(fiber/calories*1000/fiberTargetPer1000, maxFiberTerm)
+	}
+	return satietyBase + ratio + fiberTerm
+}
+
+// HungerRisk rates a satiety factor as "low" (3 or more), "moderate" (2 or more), or "high" hunger risk
+func HungerRisk(satiety float64) string {
+	switch {
+	case satiety >= lowHungerSatiety:
+		return "low"
+	case satiety >= moderateHungerSatiety:
+		return "moderate"
+	default:
+		return "high"
+	}
+}
+
+// applySatiety sets each day's satiety factor and hunger risk, and flags days
+// with both few calories and little satiety as at risk of overeating
+func applySatiety(records []DailyNutrition) {
+	for i := range records {
+		d := &records[i]
+		satiety := SatietyFactor(d.Protein, d.Carbs, d.Fiber, d.Calories)
+		d.SatietyFactor = &satiety
+		d.HungerRisk = HungerRisk(satiety)
+		d.OvereatingRisk = d.Calories < overeatingCalories && satiety < overeatingSatiety
+	}
+}
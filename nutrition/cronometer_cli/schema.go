@@ -0,0 +1,114 @@
+package main
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// schemaRoots are the output types documented by -export-readme; nested
+// struct types are documented after the root that first uses them
+var schemaRoots = []interface{}{DailyNutrition{}, FoodEntry{}, BiometricEntry{}, Summary{}}
+
+// suffixUnits infers a field's unit from its JSON name when it has no unit tag.
+// Longer suffixes come first so they win over shorter ones.
+var suffixUnits = []struct {
+	suffix string
+	unit   string
+}{
+	{"_lbs_per_week", "lbs/week"},
+	{"_per_kg", "per kg"},
+	{"_kg", "kg"},
+	{"_lbs", "lbs"},
+	{"_mg", "mg"},
+	{"_iu", "IU"},
+	{"_pct", "%"},
+	{"calories", "kcal"},
+}
+
+// fieldUnit returns the field's unit tag, or for numbers a unit inferred from its JSON name
+func fieldUnit(field reflect.StructField, name string) string {
+	if unit := field.Tag.Get("unit"); unit != "" {
+		return unit
+	}
+	t := field.Type
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Float64 && t.Kind() != reflect.Int {
+		return ""
+	}
+	for _, s := range suffixUnits {
+		if strings.HasSuffix(name, s.suffix) {
+			return s.unit
+		}
+	}
+	return ""
+}
+
+// schemaType describes t as a JSON type, queuing struct types for their own section
+func schemaType(t reflect.Type, queue func(reflect.Type)) string {
+	if t == reflect.TypeOf(time.Time{}) {
+		return "string (RFC 3339 time)"
+	}
+	switch t.Kind() {
+	case reflect.Ptr:
+		return schemaType(t.Elem(), queue)
+	case reflect.Bool:
+		return "boolean"
+	case reflect.Int, reflect.Int64, reflect.Float64:
+		return "number"
+	case reflect.String:
+		return "string"
+	case reflect.Slice:
+		return "array of " + schemaType(t.Elem(), queue)
+	case reflect.Map:
+		return "object of " + schemaType(t.Elem(), queue)
+	case reflect.Struct:
+		queue(t)
+		return fmt.Sprintf("[%s](#%s)", t.Name(), strings.ToLower(t.Name()))
+	}
+	return t.Kind().String()
+}
+
+// GenerateSchema renders a Markdown description of every field in the output types
+func GenerateSchema() string {
+	var b strings.Builder
+	b.WriteString("# Output Schema\n\n")
+	b.WriteString("Generated by `cronometer_export -export-readme`. Optional fields are omitted from the JSON when empty.\n")
+
+	var pending []reflect.Type
+	seen := make(map[reflect.Type]bool)
+	queue := func(t reflect.Type) {
+		if !seen[t] {
+			seen[t] = true
+			pending = append(pending, t)
+		}
+	}
+	for _, root := range schemaRoots {
+		queue(reflect.TypeOf(root))
+	}
+
+	for len(pending) > 0 {
+		t := pending[0]
+		pending = pending[1:]
+
+		fmt.Fprintf(&b, "\n## %s\n\n", t.Name())
+		b.WriteString("| Field | Type | Unit | Presence |\n")
+		b.WriteString("|-------|------|------|----------|\n")
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			name, options, _ := strings.Cut(field.Tag.Get("json"), ",")
+			if name == "-" || name == "" {
+				continue
+			}
+			presence := "always"
+			if strings.Contains(options, "omitempty") {
+				presence = "optional"
+			}
+			fmt.Fprintf(&b, "| `%s` | %s | %s | %s |\n", name, schemaType(field.Type, queue), fieldUnit(field, name), presence)
+		}
+	}
+	return b.String()
+}
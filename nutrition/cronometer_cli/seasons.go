@@ -0,0 +1,53 @@
+package main
+
+import "time"
+
+// seasonForMonth maps each month to its meteorological season
+var seasonForMonth = map[time.Month]string{
+	time.March: "spring", time.April: "spring", time.May: "spring",
+	time.June: "summer", time.July: "summer", time.August: "summer",
+	time.September: "fall", time.October: "fall", time.November: "fall",
+	time.December: "winter", time.January: "winter", time.February: "winter",
+}
+
+// nutrientNames lists every nutrient known to nutrientField
+func nutrientNames() []string {
+	names := []string{"calories", "fat", "carbs", "protein"}
+	for _, n := range optionalNutrients {
+		names = append(names, n.name)
+	}
+	return names
+}
+
+// SeasonalAnalysis averages every nutrient per meteorological season, pooling
+// all years in the range. The returned records have no date.
+func SeasonalAnalysis(records []DailyNutrition) map[string]DailyNutrition {
+	totals := make(map[string]*DailyNutrition)
+	counts := make(map[string]int)
+	names := nutrientNames()
+	for i := range records {
+		date, err := time.Parse("2006-01-02", records[i].Date)
+		if err != nil {
+			continue
+		}
+		season := seasonForMonth[date.Month()]
+		total, ok := totals[season]
+		if !ok {
+			total = &DailyNutrition{}
+			totals[season] = total
+		}
+		for _, name := range names {
+			*nutrientField(total, name) += *nutrientField(&records[i], name)
+		}
+		counts[season]++
+	}
+
+	seasons := make(map[string]DailyNutrition, len(totals))
+	for season, total := range totals {
+		for _, name := range names {
+			*nutrientField(total, name) /= float64(counts[season])
+		}
+		seasons[season] = *total
+	}
+	return seasons
+}
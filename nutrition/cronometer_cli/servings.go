@@ -0,0 +1,113 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// unitConversion converts a unit to grams (mass) or millilitres (volume)
+type unitConversion struct {
+	dimension string
+	factor    float64
+}
+
+// servingUnits maps normalized unit names to their conversion
+var servingUnits = map[string]unitConversion{
+	"mg":    {"mass", 0.001},
+	"g":     {"mass", 1},
+	"kg":    {"mass", 1000},
+	"oz":    {"mass", 28.349523},
+	"lb":    {"mass", 453.59237},
+	"ml":    {"volume", 1},
+	"l":     {"volume", 1000},
+	"tsp":   {"volume", 4.928922},
+	"tbsp":  {"volume", 14.786765},
+	"fl oz": {"volume", 29.573530},
+	"cup":   {"volume", 240},
+}
+
+// unitAliases maps other spellings to the names in servingUnits
+var unitAliases = map[string]string{
+	"gram": "g", "grams": "g", "kilogram": "kg", "ounce": "oz", "ounces": "oz",
+	"pound": "lb", "lbs": "lb", "milliliter": "ml", "millilitre": "ml",
+	"liter": "l", "litre": "l", "teaspoon": "tsp", "tablespoon": "tbsp",
+	"fluid ounce": "fl oz", "fl. oz": "fl oz", "floz": "fl oz", "cups": "cup",
+}
+
+// NormalizationChange records one diary entry converted to its food's usual unit
+type NormalizationChange struct {
+	Date       string  `json:"date"`
+	FoodName   string  `json:"food_name"`
+	FromAmount float64 `json:"from_amount"`
+	FromUnit   string  `json:"from_unit"`
+	ToAmount   float64 `json:"to_amount"`
+	ToUnit     string  `json:"to_unit"`
+	Warning    string  `json:"warning,omitempty"`
+}
+
+// normalizeUnit lowercases a unit and resolves known aliases
+func normalizeUnit(unit string) string {
+	unit = strings.ToLower(strings.TrimSpace(unit))
+	if alias, ok := unitAliases[unit]; ok {
+		return alias
+	}
+	return unit
+}
+
+// NormalizeServingSizes converts each food's entries to the unit it is most
+// often logged in, where a conversion is known. Converting between mass and
+// volume assumes the density of water (1 g/ml) and the change carries a
+// warning. Entries in units without a conversion, such as "serving", are left
+// as they are.
+func NormalizeServingSizes(entries []FoodEntry) ([]FoodEntry, []NormalizationChange) {
+	unitCounts := make(map[string]map[string]int)
+	var order []string
+	for _, e := range entries {
+		key := foodKey(e.FoodName)
+		if unitCounts[key] == nil {
+			unitCounts[key] = make(map[string]int)
+			order = append(order, key)
+		}
+		unitCounts[key][normalizeUnit(e.Unit)]++
+	}
+	usual := make(map[string]string, len(unitCounts))
+	for _, key := range order {
+		best := ""
+		for unit, count := range unitCounts[key] {
+			if _, ok := servingUnits[unit]; !ok {
+				continue
+			}
+			if count > unitCounts[key][best] || (count == unitCounts[key][best] && unit < best) {
+				best = unit
+			}
+		}
+		usual[key] = best
+	}
+
+	normalized := make([]FoodEntry, len(entries))
+	changes := []NormalizationChange{}
+	for i, e := range entries {
+		normalized[i] = e
+		from, to := normalizeUnit(e.Unit), usual[foodKey(e.FoodName)]
+		fromConv, fromOK := servingUnits[from]
+		toConv, toOK := servingUnits[to]
+		if !fromOK || !toOK || from == to {
+			continue
+		}
+
+		change := NormalizationChange{
+			Date:       e.Date,
+			FoodName:   e.FoodName,
+			FromAmount: e.Amount,
+			FromUnit:   e.Unit,
+			ToAmount:   e.Amount * fromConv.factor / toConv.factor,
+			ToUnit:     to,
+		}
+		if fromConv.dimension != toConv.dimension {
+			change.Warning = fmt.Sprintf("converting %s to %s assumes a density of 1 g/ml", from, to)
+		}
+		normalized[i].Amount, normalized[i].Unit = change.ToAmount, change.ToUnit
+		changes = append(changes, change)
+	}
+	return normalized, changes
+}
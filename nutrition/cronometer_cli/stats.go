@@ -0,0 +1,253 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+)
+
+// LinearModel is an ordinary least squares fit of y = Intercept + Slope*x.
+// When x grows over time, Origin, OriginX and XPerDay describe how, so the
+// model can be projected forward to a date.
+type LinearModel struct {
+	Intercept float64 `json:"intercept"`
+	Slope     float64 `json:"slope"`
+	SlopeSE   float64 `json:"slope_se"`
+	RSquared  float64 `json:"r_squared"`
+	N         int     `json:"n"`
+	MeanX     float64 `json:"mean_x"`
+
+	Origin  time.Time `json:"-"`
+	OriginX float64   `json:"-"`
+	XPerDay float64   `json:"-"`
+}
+
+// fitOLS fits a simple linear regression; it needs at least three points
+// with some spread in x
+func fitOLS(x, y []float64) (LinearModel, error) {
+	n := len(x)
+	if n != len(y) || n < 3 {
+		return LinearModel{}, fmt.Errorf("need at least 3 paired points, got %d", n)
+	}
+
+	meanX, meanY := mean(x), mean(y)
+	var sxx, sxy, syy float64
+	for i := range x {
+		dx, dy := x[i]-meanX, y[i]-meanY
+		sxx += dx * dx
+		sxy += dx * dy
+		syy += dy * dy
+	}
+	if sxx == 0 {
+		return LinearModel{}, fmt.Errorf("x values have no variance")
+	}
+
+	model := LinearModel{Slope: sxy / sxx, N: n, MeanX: meanX}
+	model.Intercept = meanY - model.Slope*meanX
+
+	var sse float64
+	for i := range x {
+		residual := y[i] - (model.Intercept + model.Slope*x[i])
+		sse += residual * residual
+	}
+	model.SlopeSE = math.Sqrt(sse / float64(n-2) / sxx)
+	if syy > 0 {
+		model.RSquared = 1 - sse/syy
+	}
+	return model, nil
+}
+
+// pearson returns the Pearson correlation coefficient of x and y
+func pearson(x, y []float64) (float64, error) {
+	if len(x) != len(y) || len(x) < 2 {
+		return 0, fmt.Errorf("need at least 2 paired values, got %d", len(x))
+	}
+	meanX, meanY := mean(x), mean(y)
+	var sxy, sxx, syy float64
+	for i := range x {
+		dx, dy := x[i]-meanX, y[i]-meanY
+		sxy += dx * dy
+		sxx += dx * dx
+		syy += dy * dy
+	}
+	if sxx == 0 || syy == 0 {
+		return 0, fmt.Errorf("values have no variance")
+	}
+	return sxy / math.Sqrt(sxx*syy), nil
+}
+
+// mean returns the arithmetic mean, or 0 for no values
+func mean(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	var sum float64
+	for _, v := range values {
+		sum += v
+	}
+	return sum / float64(len(values))
+}
+
+// sampleStdDev returns the sample standard deviation (n-1 denominator)
+func sampleStdDev(values []float64) float64 {
+	if len(values) < 2 {
+		return 0
+	}
+	m := mean(values)
+	var ss float64
+	for _, v := range values {
+		ss += (v - m) * (v - m)
+	}
+	return math.Sqrt(ss / float64(len(values)-1))
+}
+
+// studentTCDF returns P(T <= t) for a Student's t distribution with df degrees of freedom
+func studentTCDF(t, df float64) float64 {
+	x := df / (df + t*t)
+	tail := 0.5 * regularizedIncompleteBeta(x, df/2, 0.5)
+	if t > 0 {
+		return 1 - tail
+	}
+	return tail
+}
+
+// studentTQuantile returns t such that P(T <= t) = p, found by bisection
+func studentTQuantile(p, df float64) float64 {
+	if p == 0.5 {
+		return 0
+	}
+	if p < 0.5 {
+		return -studentTQuantile(1-p, df)
+	}
+	lo, hi := 0.0, 1.0
+	for studentTCDF(hi, df) < p {
+		hi *= 2
+	}
+	for i := 0; i < 100; i++ {
+		mid := (lo + hi) / 2
+		if studentTCDF(mid, df) < p {
+			lo = mid
+		} else {
+			hi = mid
+		}
+	}
+	return (lo + hi) / 2
+}
+
+// regularizedIncompleteBeta computes I_x(a, b) using the continued fraction
+// expansion from Numerical Recipes (betacf)
+func regularizedIncompleteBeta(x, a, b float64) float64 {
+	if x <= 0 {
+		return 0
+	}
+	if x >= 1 {
+		return 1
+	}
+	lgab, _ := math.Lgamma(a + b)
+	lga, _ := math.Lgamma(a)
+	lgb, _ := math.Lgamma(b)
+	front := math.Exp(lgab - lga - lgb + a*math.Log(x) + b*math.Log(1-x))
+
+	// The continued fraction converges quickly only below this point; use symmetry above it
+	if x > (a+1)/(a+b+2) {
+		return 1 - regularizedIncompleteBeta(1-x, b, a)
+	}
+
+	const epsilon = 1e-14
+	const tiny = 1e-300
+	c, d := 1.0, 1-(a+b)*x/(a+1)
+	if math.Abs(d) < tiny {
+		d = tiny
+	}
+	d = 1 / d
+	h := d
+	for m := 1; m <= 300; m++ {
+		fm := float64(m)
+		numerator := fm * (b - fm) * x / ((a + 2*fm - 1) * (a + 2*fm))
+		d = 1 + numerator*d
+		if math.Abs(d) < tiny {
+			d = tiny
+		}
+		c = 1 + numerator/c
+		if math.Abs(c) < tiny {
+			c = tiny
+		}
+		d = 1 / d
+		h *= d * c
+
+		numerator = -(a + fm) * (a + b + fm) * x / ((a + 2*fm) * (a + 2*fm + 1))
+		d = 1 + numerator*d
+		if math.Abs(d) < tiny {
+			d = tiny
+		}
+		c = 1 + numerator/c
+		if math.Abs(c) < tiny {
+			c = tiny
+		}
+		d = 1 / d
+		delta := d * c
+		h *= delta
+		if math.Abs(delta-1) < epsilon {
+			break
+		}
+	}
+	return front * h / a
+}
+
+// loess smooths y over x with locally weighted linear regression. Each fit
+// uses the nearest bandwidth share of the points (at least two), weighted by
+// the tricube of their distance. x must be sorted.
+func loess(x, y []float64, bandwidth float64) []float64 {
+	n := len(x)
+	smoothed := make([]float64, n)
+	if n < 3 {
+		copy(smoothed, y)
+		return smoothed
+	}
+	span := int(math.Ceil(bandwidth * float64(n)))
+	if span < 2 {
+		span = 2
+	}
+	if span > n {
+		span = n
+	}
+
+	distances := make([]float64, n)
+	for i := range x {
+		for j := range x {
+			distances[j] = math.Abs(x[j] - x[i])
+		}
+		sorted := append([]float64(nil), distances...)
+		sort.Float64s(sorted)
+		h := sorted[span-1]
+		if h == 0 {
+			h = 1
+		}
+
+		var sw, swx, swy, swxx, swxy float64
+		for j := range x {
+			u := distances[j] / h
+			if u >= 1 {
+				continue
+			}
+			w := math.Pow(1-u*u*u, 3)
+			sw += w
+			swx += w * x[j]
+			swy += w * y[j]
+			swxx += w * x[j] * x[j]
+			swxy += w * x[j] * y[j]
+		}
+		denom := sw*swxx - swx*swx
+		switch {
+		case sw == 0:
+			smoothed[i] = y[i]
+		case math.Abs(denom) < 1e-12:
+			smoothed[i] = swy / sw
+		default:
+			slope := (sw*swxy - swx*swy) / denom
+			smoothed[i] = (swy-slope*swx)/sw + slope*x[i]
+		}
+	}
+	return smoothed
+}
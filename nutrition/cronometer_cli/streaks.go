@@ -0,0 +1,103 @@
+package main
+
+import (
+	"math"
+	"sort"
+	"time"
+)
+
+// AdherenceStreak is the number of consecutive days every goal was met
+type AdherenceStreak struct {
+	Current int `json:"current"`
+	Longest int `json:"longest"`
+}
+
+// GoalAdherenceStreak counts consecutive calendar days on which every goal
+// was met. A missed goal or an unlogged day ends a streak. The current streak
+// is the one ending on end (the range's last YYYY-MM-DD date) or today; a
+// streak that stopped before then has already been broken, so current is 0.
+func GoalAdherenceStreak(records []DailyNutrition, goals map[string]float64, end string) (current, longest int) {
+	days := make([]DailyNutrition, len(records))
+	copy(days, records)
+	sort.Slice(days, func(i, j int) bool {
+		return days[i].Date < days[j].Date
+	})
+
+	var previous time.Time
+	var last string
+	for _, d := range days {
+		date, err := time.Parse("2006-01-02", d.Date)
+		if err != nil {
+			continue
+		}
+		if !previous.IsZero() && !date.Equal(previous.AddDate(0, 0, 1)) {
+			current = 0
+		}
+		previous = date
+
+		met := true
+		for name, target := range goals {
+			if !dayMeetsGoal(d, name, target) {
+				met = false
+				break
+			}
+		}
+		if !met {
+			current = 0
+			continue
+		}
+		current++
+		last = d.Date
+		if current > longest {
+			longest = current
+		}
+	}
+	if last != end && last != time.Now().Format("2006-01-02") {
+		current = 0
+	}
+	return current, longest
+}
+
+// GoalTimeline is how many consecutive successful days a goal needs to reach
+// the target adherence
+type GoalTimeline struct {
+	Adherence  float64 `json:"adherence"`
+	Target     float64 `json:"target"`
+	DaysNeeded int     `json:"days_needed"`
+}
+
+// ProjectGoalTimeline returns the minimum consecutive successes that lift
+// adherence over a rolling window of windowDays from historicalAdherence to
+// targetAdherence, assuming each success replaces a day at the historical
+// rate. It is 0 when the target is already met and at most windowDays.
+func ProjectGoalTimeline(historicalAdherence float64, targetAdherence float64, windowDays int) int {
+	if historicalAdherence >= targetAdherence {
+		return 0
+	}
+	needed := float64(windowDays) * (targetAdherence - historicalAdherence) / (1 - historicalAdherence)
+	// Trim floating point noise before rounding up, e.g. 12.000000000000002
+	return int(math.Ceil(needed - 1e-9))
+}
+
+// projectGoalTimelines projects the timeline for every goal over the records
+func projectGoalTimelines(records []DailyNutrition, goals map[string]float64, targetAdherence float64) map[string]GoalTimeline {
+	timelines := make(map[string]GoalTimeline, len(goals))
+	if len(records) == 0 {
+		return timelines
+	}
+	for name, target := range goals {
+		met := 0
+		for _, d := range records {
+			if dayMeetsGoal(d, name, target) {
+				met++
+			}
+		}
+		adherence := float64(met) / float64(len(records))
+		timelines[name] = GoalTimeline{
+			Adherence:  adherence,
+			Target:     targetAdherence,
+			DaysNeeded: ProjectGoalTimeline(adherence, targetAdherence, len(records)),
+		}
+	}
+	return timelines
+}
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestGoalAdherenceStreak(t *testing.T) {
+	// Protein is a floor and calories a ceiling
+	goals := map[string]float64{"protein": 150, "calories": 2500}
+	met := func(date string) DailyNutrition { return DailyNutrition{Date: date, Protein: 160, Calories: 2300} }
+	tests := []struct {
+		name        string
+		records     []DailyNutrition
+		end         string
+		wantCurrent int
+		wantLongest int
+	}{
+		{"runs to the end", []DailyNutrition{met("2024-03-01"), met("2024-03-02"), met("2024-03-03")}, "2024-03-03", 3, 3},
+		{
+			"some goals met and some missed on one day",
+			[]DailyNutrition{met("2024-03-01"), {Date: "2024-03-02", Protein: 170, Calories: 3100}, met("2024-03-03")},
+			"2024-03-03", 1, 1,
+		},
+		{
+			"missed day ends the current streak",
+			[]DailyNutrition{met("2024-03-01"), met("2024-03-02"), {Date: "2024-03-03", Protein: 90, Calories: 2000}},
+			"2024-03-03", 0, 2,
+		},
+		{"unlogged days before the end", []DailyNutrition{met("2024-03-01"), met("2024-03-02")}, "2024-03-05", 0, 2},
+		{"unlogged day inside the range", []DailyNutrition{met("2024-03-01"), met("2024-03-03")}, "2024-03-03", 1, 1},
+		{"out of order records", []DailyNutrition{met("2024-03-03"), met("2024-03-01"), met("2024-03-02")}, "2024-03-03", 3, 3},
+		{"no records", nil, "2024-03-03", 0, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			current, longest := GoalAdherenceStreak(tt.records, goals, tt.end)
+			if current != tt.wantCurrent || longest != tt.wantLongest {
+				t.Errorf("GoalAdherenceStreak = (%d, %d), want (%d, %d)", current, longest, tt.wantCurrent, tt.wantLongest)
+			}
+		})
+	}
+}
+
+func TestGoalAdherenceStreakEndingToday(t *testing.T) {
+	goals := map[string]float64{"protein": 150}
+	today := time.Now()
+	records := []DailyNutrition{
+		{Date: today.AddDate(0, 0, -1).Format("2006-01-02"), Protein: 160},
+		{Date: today.Format("2006-01-02"), Protein: 160},
+	}
+	// A range ending in the future still counts a streak running through today
+	end := today.AddDate(0, 0, 7).Format("2006-01-02")
+	if current, _ := GoalAdherenceStreak(records, goals, end); current != 2 {
+		t.Errorf("current = %d, want 2 for a streak through today", current)
+	}
+}
@@ -0,0 +1,155 @@
+package main
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// CorrelationResult compares calories on days tagged with a stress mood
+// against all other days. PValue is from Welch's t-test and is nil when either
+// group has fewer than two days or no variance.
+type CorrelationResult struct {
+	StressDays         int      `json:"stress_days"`
+	OtherDays          int      `json:"other_days"`
+	StressMeanCalories float64  `json:"stress_mean_calories"`
+	OtherMeanCalories  float64  `json:"other_mean_calories"`
+	Difference         float64  `json:"difference"`
+	PValue             *float64 `json:"p_value,omitempty"`
+}
+
+// fetchMoods exports the Cronometer notes and joins each day's notes into one
+// lowercased string, keyed by YYYY-MM-DD date
+func fetchMoods(ctx context.Context, client Client, start, end time.Time) (map[string]string, error) {
+	data, err := client.ExportNotes(ctx, start, end)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := csv.NewReader(strings.NewReader(data)).ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("parsing notes: %v", err)
+	}
+	if len(rows) == 0 {
+		return map[string]string{}, nil
+	}
+
+	dateIdx := findColumn(rows[0], "Day")
+	if dateIdx == -1 {
+		dateIdx = findColumn(rows[0], "Date")
+	}
+	noteIdx := findColumn(rows[0], "Note")
+	if dateIdx == -1 || noteIdx == -1 {
+		return nil, fmt.Errorf("notes export has no Day and Note columns")
+	}
+
+	moods := make(map[string]string)
+	for _, row := range rows[1:] {
+		if len(row) <= dateIdx || len(row) <= noteIdx {
+			continue
+		}
+		date := row[dateIdx]
+		moods[date] = strings.TrimSpace(moods[date] + " " + strings.ToLower(row[noteIdx]))
+	}
+	return moods, nil
+}
+
+// parseTags splits a comma-separated tag list into lowercased tags
+func parseTags(value string) []string {
+	var tags []string
+	for _, tag := range strings.Split(value, ",") {
+		if tag = strings.ToLower(strings.TrimSpace(tag)); tag != "" {
+			tags = append(tags, tag)
+		}
+	}
+	return tags
+}
+
+// negations are words that flip the mood tag after them, as in "not good"
+var negations = map[string]bool{
+	"not": true, "no": true, "never": true, "isn't": true, "wasn't": true, "don't": true, "didn't": true,
+}
+
+// moodWords splits a mood into lowercased words, keeping apostrophes
+func moodWords(mood string) []string {
+	return strings.FieldsFunc(strings.ToLower(mood), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '\''
+	})
+}
+
+// hasMoodTag reports whether any of tags appears in mood as whole words, so
+// "bad" doesn't match "badminton". A tag right after a negation, as in "not
+// good", doesn't count unless the tag itself starts with one.
+func hasMoodTag(mood string, tags []string) bool {
+	words := moodWords(mood)
+	for _, tag := range tags {
+		tagWords := moodWords(tag)
+		if len(tagWords) == 0 {
+			continue
+		}
+		for i := 0; i+len(tagWords) <= len(words); i++ {
+			if !wordsMatch(words[i:i+len(tagWords)], tagWords) {
+				continue
+			}
+			if i > 0 && negations[words[i-1]] && !negations[tagWords[0]] {
+				continue
+			}
+			return true
+		}
+	}
+	return false
+}
+
+// wordsMatch reports whether two word lists are equal
+func wordsMatch(a, b []string) bool {
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// StressEatingCorrelation splits the records into days whose mood mentions any
+// of stressTags and all other days, and compares their mean calories
+func StressEatingCorrelation(records []DailyNutrition, moods map[string]string, stressTags []string) CorrelationResult {
+	var stress, other []float64
+	for _, d := range records {
+		if hasMoodTag(moods[d.Date], stressTags) {
+			stress = append(stress, d.Calories)
+		} else {
+			other = append(other, d.Calories)
+		}
+	}
+
+	result := CorrelationResult{
+		StressDays:         len(stress),
+		OtherDays:          len(other),
+		StressMeanCalories: mean(stress),
+		OtherMeanCalories:  mean(other),
+	}
+	result.Difference = result.StressMeanCalories - result.OtherMeanCalories
+	if p, ok := welchTTest(stress, other); ok {
+		result.PValue = &p
+	}
+	return result
+}
+
+// welchTTest returns the two-sided p-value of Welch's unequal variances t-test
+func welchTTest(a, b []float64) (float64, bool) {
+	if len(a) < 2 || len(b) < 2 {
+		return 0, false
+	}
+	va := sampleStdDev(a) * sampleStdDev(a) / float64(len(a))
+	vb := sampleStdDev(b) * sampleStdDev(b) / float64(len(b))
+	if va+vb == 0 {
+		return 0, false
+	}
+	t := (mean(a) - mean(b)) / math.Sqrt(va+vb)
+	// Welch-Satterthwaite degrees of freedom
+	df := (va + vb) * (va + vb) / (va*va/float64(len(a)-1) + vb*vb/float64(len(b)-1))
+	return 2 * (1 - studentTCDF(math.Abs(t), df)), true
+}
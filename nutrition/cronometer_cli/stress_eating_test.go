@@ -0,0 +1,57 @@
+package main
+
+import "testing"
+
+func TestHasMoodTag(t *testing.T) {
+	tests := []struct {
+		name string
+		mood string
+		tags []string
+		want bool
+	}{
+		{"whole word", "felt stressed at work", []string{"stressed"}, true},
+		{"punctuation", "Stressed, tired.", []string{"stressed"}, true},
+		{"prefix of a longer word", "played badminton", []string{"bad"}, false},
+		{"suffix of a longer word", "feeling goodish", []string{"good"}, false},
+		{"negated", "not good today", []string{"good"}, false},
+		{"negated with a contraction", "wasn't stressed", []string{"stressed"}, false},
+		{"negated once, stated later", "not good this morning but good by evening", []string{"good"}, true},
+		{"multi-word tag", "sleep deprived again", []string{"sleep deprived"}, true},
+		{"negated tag given explicitly", "not good", []string{"not good"}, true},
+		{"any of several tags", "anxious", []string{"stressed", "anxious"}, true},
+		{"no notes", "", []string{"stressed"}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := hasMoodTag(tt.mood, tt.tags); got != tt.want {
+				t.Errorf("hasMoodTag(%q, %q) = %v, want %v", tt.mood, tt.tags, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStressEatingCorrelationWholeTags(t *testing.T) {
+	records := []DailyNutrition{
+		{Date: "2024-03-01", Calories: 2800},
+		{Date: "2024-03-02", Calories: 2600},
+		{Date: "2024-03-03", Calories: 2000},
+		{Date: "2024-03-04", Calories: 2100},
+	}
+	moods := map[string]string{
+		"2024-03-01": "bad day",
+		"2024-03-02": "stressed",
+		"2024-03-03": "badminton after work",
+		"2024-03-04": "not stressed at all",
+	}
+	result := StressEatingCorrelation(records, moods, []string{"bad", "stressed"})
+	if result.StressDays != 2 || result.StressMeanCalories != 2700 || result.OtherMeanCalories != 2050 {
+		t.Errorf("result = %+v, want 2 stress days at 2700 kcal against 2050", result)
+	}
+}
+
+func TestParseTags(t *testing.T) {
+	got := parseTags(" Stressed, ,ANXIOUS ,")
+	if len(got) != 2 || got[0] != "stressed" || got[1] != "anxious" {
+		t.Errorf("parseTags = %q, want [stressed anxious]", got)
+	}
+}
@@ -0,0 +1,228 @@
+package main
+
+import "reflect"
+
+// Summary holds aggregate statistics across the exported date range
+type Summary struct {
+	DaysLogged          int                       `json:"days_logged,omitempty"`
+	Averages            map[string]float64        `json:"averages,omitempty"`
+	ExcludedDates       []string                  `json:"excluded_dates,omitempty"`
+	CalorieDiscrepancy  *CalorieDiscrepancy       `json:"calorie_discrepancy,omitempty"`
+	LoggingQuality      *float64                  `json:"logging_quality,omitempty"`
+	Preferences         *FoodPreferenceModel      `json:"preferences,omitempty"`
+	TopCalorieSources   []CalorieSource           `json:"top_calorie_sources,omitempty"`
+	FoodFrequency       []FrequencyEntry          `json:"food_frequency,omitempty"`
+	ServingChanges      []NormalizationChange     `json:"serving_changes,omitempty"`
+	Hydration           *HydrationSummary         `json:"hydration,omitempty"`
+	AlcoholWeeks        []WeekAlcohol             `json:"alcohol_weeks,omitempty"`
+	FoodAggregates      []FoodAggregate           `json:"food_aggregates,omitempty"`
+	FoodPairings        []FoodPair                `json:"food_pairings,omitempty"`
+	CaloricDensity      *CaloricDensityReport     `json:"caloric_density,omitempty"`
+	CategoryBudget      []CategoryBudgetResult    `json:"food_category_budget,omitempty"`
+	NutrientEfficiency  *NutrientEfficiencyReport `json:"nutrient_calorie_efficiency,omitempty"`
+	MacroSplit          *MacroSplitReport         `json:"macro_split,omitempty"`
+	MacroDRIFraction    *float64                  `json:"macro_dri_compliant_fraction,omitempty"`
+	Monotony            []MonotonyDay             `json:"monotony,omitempty"`
+	Phytochemicals      *PhytochemicalReport      `json:"phytochemicals,omitempty"`
+	Fasting             *FastingReport            `json:"fasting,omitempty"`
+	FastingProtocol     *ProtocolReport           `json:"fasting_protocol,omitempty"`
+	ProteinDistribution *ProteinDistReport        `json:"protein_distribution,omitempty"`
+	PostWorkout         []PostWorkoutReport       `json:"post_workout,omitempty"`
+	Leucine             *LeucineReport            `json:"leucine,omitempty"`
+	MealSpacing         *MealSpacingReport        `json:"meal_spacing,omitempty"`
+	Supplements         *SupplementReport         `json:"supplements,omitempty"`
+	SupplementDepends   map[string]bool           `json:"supplement_dependencies,omitempty"`
+	AdherenceTrend      []WeekAdherence           `json:"adherence_trend,omitempty"`
+	AdherenceStreak     *AdherenceStreak          `json:"adherence_streak,omitempty"`
+	GoalTimeline        map[string]GoalTimeline   `json:"goal_timeline,omitempty"`
+	HabitSuggestions    []HabitSuggestion         `json:"habit_suggestions,omitempty"`
+	RandomMeal          *MealTemplate             `json:"random_meal,omitempty"`
+	FoodSwap            *SwapImpact               `json:"food_swap,omitempty"`
+	SodiumCategories    map[string]int            `json:"sodium_categories,omitempty"`
+	MagnesiumSleep      *MagnesiumSleep           `json:"magnesium_sleep,omitempty"`
+	B12                 *B12Report                `json:"b12,omitempty"`
+	StressEating        *CorrelationResult        `json:"stress_eating,omitempty"`
+	TryptophanMood      *TryptophanMood           `json:"tryptophan_mood,omitempty"`
+	Seasons             map[string]DailyNutrition `json:"seasons,omitempty"`
+	Population          map[string]float64        `json:"population_percentiles,omitempty"`
+	LongevityTrend      []MonthScore              `json:"longevity_trend,omitempty"`
+	MicronutrientTrend  []MicronutrientPeriod     `json:"micronutrient_trend,omitempty"`
+	DiversityTrend      []WeekScore               `json:"diversity_trend,omitempty"`
+	DietBreaks          []DietBreak               `json:"diet_breaks,omitempty"`
+	WeightAnomalies     []WeightAnomaly           `json:"weight_anomalies,omitempty"`
+	LeanMass            []LeanMassEntry           `json:"lean_mass,omitempty"`
+	WeightProjection    *WeightProjection         `json:"weight_projection,omitempty"`
+	PredictedNextWeek   []PredictedDay            `json:"predicted_next_7_days,omitempty"`
+	BMRComparison       *BMRComparisonReport      `json:"bmr_comparison,omitempty"`
+	REE                 *REEReport                `json:"resting_energy_expenditure,omitempty"`
+	GoalAdjustment      *GoalAdjustment           `json:"goal_adjustment,omitempty"`
+}
+
+// summaryNutrients are averaged over the period by -summary-only
+var summaryNutrients = []string{"calories", "fat", "carbs", "protein", "fiber", "sodium"}
+
+// averageNutrients returns the mean of each summary nutrient across records
+func averageNutrients(records []DailyNutrition) map[string]float64 {
+	if len(records) == 0 {
+		return nil
+	}
+	averages := make(map[string]float64, len(summaryNutrients))
+	for _, name := range summaryNutrients {
+		var total float64
+		for i := range records {
+			total += *nutrientField(&records[i], name)
+		}
+		averages[name] = total / float64(len(records))
+	}
+	return averages
+}
+
+// Report is the JSON output when summary statistics are requested
+type Report struct {
+	Days    []DailyNutrition `json:"days"`
+	Summary Summary          `json:"summary"`
+}
+
+// isEmpty reports whether no summary statistics were requested
+func (s Summary) isEmpty() bool {
+	return reflect.DeepEqual(s, Summary{})
+}
+
+// formatDates rewrites the summary's YYYY-MM-DD dates using the output layout
+func (s *Summary) formatDates(layout string) error {
+	for i, date := range s.ExcludedDates {
+		formatted, err := formatDate(date, layout)
+		if err != nil {
+			return err
+		}
+		s.ExcludedDates[i] = formatted
+	}
+	for i := range s.ServingChanges {
+		formatted, err := formatDate(s.ServingChanges[i].Date, layout)
+		if err != nil {
+			return err
+		}
+		s.ServingChanges[i].Date = formatted
+	}
+	for i := range s.PostWorkout {
+		formatted, err := formatDate(s.PostWorkout[i].Date, layout)
+		if err != nil {
+			return err
+		}
+		s.PostWorkout[i].Date = formatted
+	}
+	if s.Leucine != nil {
+		for i := range s.Leucine.Meals {
+			formatted, err := formatDate(s.Leucine.Meals[i].Date, layout)
+			if err != nil {
+				return err
+			}
+			s.Leucine.Meals[i].Date = formatted
+		}
+	}
+	if s.MealSpacing != nil {
+		for i := range s.MealSpacing.Days {
+			formatted, err := formatDate(s.MealSpacing.Days[i].Date, layout)
+			if err != nil {
+				return err
+			}
+			s.MealSpacing.Days[i].Date = formatted
+		}
+	}
+	if s.MacroSplit != nil {
+		for i := range s.MacroSplit.Days {
+			formatted, err := formatDate(s.MacroSplit.Days[i].Date, layout)
+			if err != nil {
+				return err
+			}
+			s.MacroSplit.Days[i].Date = formatted
+		}
+		if s.MacroSplit.ClosestDayToTarget != "" {
+			formatted, err := formatDate(s.MacroSplit.ClosestDayToTarget, layout)
+			if err != nil {
+				return err
+			}
+			s.MacroSplit.ClosestDayToTarget = formatted
+		}
+	}
+	if s.Phytochemicals != nil {
+		for i := range s.Phytochemicals.Days {
+			formatted, err := formatDate(s.Phytochemicals.Days[i].Date, layout)
+			if err != nil {
+				return err
+			}
+			s.Phytochemicals.Days[i].Date = formatted
+		}
+	}
+	for i := range s.MicronutrientTrend {
+		p := &s.MicronutrientTrend[i]
+		start, err := formatDate(p.Start, layout)
+		if err != nil {
+			return err
+		}
+		end, err := formatDate(p.End, layout)
+		if err != nil {
+			return err
+		}
+		p.Start, p.End = start, end
+	}
+	for i := range s.Monotony {
+		formatted, err := formatDate(s.Monotony[i].Date, layout)
+		if err != nil {
+			return err
+		}
+		s.Monotony[i].Date = formatted
+	}
+	if s.Fasting != nil {
+		for i := range s.Fasting.Days {
+			formatted, err := formatDate(s.Fasting.Days[i].Date, layout)
+			if err != nil {
+				return err
+			}
+			s.Fasting.Days[i].Date = formatted
+		}
+	}
+	for i := range s.DietBreaks {
+		for _, date := range []*string{&s.DietBreaks[i].StartDate, &s.DietBreaks[i].EndDate} {
+			formatted, err := formatDate(*date, layout)
+			if err != nil {
+				return err
+			}
+			*date = formatted
+		}
+	}
+	for i := range s.WeightAnomalies {
+		formatted, err := formatDate(s.WeightAnomalies[i].Date, layout)
+		if err != nil {
+			return err
+		}
+		s.WeightAnomalies[i].Date = formatted
+	}
+	for i := range s.LeanMass {
+		formatted, err := formatDate(s.LeanMass[i].Date, layout)
+		if err != nil {
+			return err
+		}
+		s.LeanMass[i].Date = formatted
+	}
+	for i := range s.PredictedNextWeek {
+		formatted, err := formatDate(s.PredictedNextWeek[i].Date, layout)
+		if err != nil {
+			return err
+		}
+		s.PredictedNextWeek[i].Date = formatted
+	}
+	if p := s.WeightProjection; p != nil {
+		for _, date := range []*string{&p.ProjectedDate, &p.LowerBound, &p.UpperBound} {
+			if *date == "" {
+				continue
+			}
+			formatted, err := formatDate(*date, layout)
+			if err != nil {
+				return err
+			}
+			*date = formatted
+		}
+	}
+	return nil
+}
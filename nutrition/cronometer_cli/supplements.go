@@ -0,0 +1,169 @@
+package main
+
+import (
+	"sort"
+	"strings"
+)
+
+// supplementKeywords are lowercase name fragments that mark a diary entry as a supplement
+var supplementKeywords = []string{
+	"vitamin", "mineral", "multivitamin", "supplement", "whey", "casein",
+	"creatine", "protein powder", "fish oil", "omega-3", "capsule",
+	"tablet", "softgel", "collagen", "probiotic", "electrolyte",
+}
+
+// NutrientContribution totals the macros from a group of diary entries
+type NutrientContribution struct {
+	Entries  int     `json:"entries"`
+	Calories float64 `json:"calories"`
+	Fat      float64 `json:"fat"`
+	Carbs    float64 `json:"carbs"`
+	Protein  float64 `json:"protein"`
+}
+
+// SupplementReport separates the diary's food contributions from its supplement contributions
+type SupplementReport struct {
+	Food            NutrientContribution `json:"food"`
+	Supplements     NutrientContribution `json:"supplements"`
+	SupplementNames []string             `json:"supplement_names"`
+}
+
+// IsSupplementHeuristic reports whether an entry looks like a supplement,
+// either from its Cronometer category or from common supplement keywords
+func IsSupplementHeuristic(entry FoodEntry) bool {
+	if strings.Contains(strings.ToLower(entry.Category), "supplement") {
+		return true
+	}
+	name := strings.ToLower(entry.FoodName)
+	for _, keyword := range supplementKeywords {
+		if strings.Contains(name, keyword) {
+			return true
+		}
+	}
+	return false
+}
+
+// splitSupplements partitions diary entries into food and supplement entries
+func splitSupplements(entries []FoodEntry) (food, supplements []FoodEntry) {
+	for _, e := range entries {
+		if IsSupplementHeuristic(e) {
+			supplements = append(supplements, e)
+		} else {
+			food = append(food, e)
+		}
+	}
+	return food, supplements
+}
+
+// trackSupplements reports food and supplement contributions separately
+func trackSupplements(entries []FoodEntry) SupplementReport {
+	food, supplements := splitSupplements(entries)
+	report := SupplementReport{
+		Food:            contribution(food),
+		Supplements:     contribution(supplements),
+		SupplementNames: []string{},
+	}
+
+	seen := make(map[string]bool)
+	for _, e := range supplements {
+		if key := foodKey(e.FoodName); !seen[key] {
+			seen[key] = true
+			report.SupplementNames = append(report.SupplementNames, e.FoodName)
+		}
+	}
+	sort.Strings(report.SupplementNames)
+	return report
+}
+
+// contribution totals the macros across entries
+func contribution(entries []FoodEntry) NutrientContribution {
+	c := NutrientContribution{Entries: len(entries)}
+	for _, e := range entries {
+		c.Calories += e.Calories
+		c.Fat += e.Fat
+		c.Carbs += e.Carbs
+		c.Protein += e.Protein
+	}
+	return c
+}
+
+// supplementDependencyGoals are the default goals for the nutrients checked by
+// -detect-supplement-dependency when no goal is set: the vitamin D and B12
+// RDAs and the omega-3 (ALA) AI for men, in g
+var supplementDependencyGoals = map[string]float64{
+	"vitamin_d": vitaminDRDAIU,
+	"b12":       b12RDAUg,
+	"omega_3":   1.6,
+}
+
+// entryNutrient returns a diary entry's amount of a supplement dependency nutrient
+func entryNutrient(e FoodEntry, name string) float64 {
+	switch name {
+	case "vitamin_d":
+		return e.VitaminD
+	case "b12":
+		return e.B12
+	case "omega_3":
+		return e.Omega3
+	case "vitamin_c":
+		return e.VitaminC
+	case "vitamin_e":
+		return e.VitaminE
+	case "folate":
+		return e.Folate
+	case "calcium":
+		return e.Calcium
+	case "magnesium":
+		return e.Magnesium
+	case "iron":
+		return e.Iron
+	case "zinc":
+		return e.Zinc
+	case "copper":
+		return e.Copper
+	case "selenium":
+		return e.Selenium
+	case "phosphorus":
+		return e.Phosphorus
+	case "potassium":
+		return e.Potassium
+	}
+	return 0
+}
+
+// IsSupplementDependent reports whether a day's goal for the nutrient is met
+// by its total intake but not by food alone
+func IsSupplementDependent(nutrient string, totalIntake, foodOnlyIntake, goal float64) bool {
+	return goalMet(nutrient, totalIntake, goal) && !goalMet(nutrient, foodOnlyIntake, goal)
+}
+
+// SupplementDependencies reports, for vitamin D, B12, and omega-3, whether the
+// goal was only ever met on days where supplements in the diary made up the
+// difference: true when it was met at least once and never by food alone.
+// Goals come from goals when set and supplementDependencyGoals otherwise.
+func SupplementDependencies(records []DailyNutrition, diary []FoodEntry, goals map[string]float64) map[string]bool {
+	byDate := groupEntriesByDate(diary)
+	dependencies := make(map[string]bool, len(supplementDependencyGoals))
+	for name, goal := range supplementDependencyGoals {
+		if target, ok := goals[name]; ok {
+			goal = target
+		}
+		var metWithSupplements, metByFood bool
+		for i := range records {
+			total := *nutrientField(&records[i], name)
+			_, supplements := splitSupplements(byDate[records[i].Date])
+			foodOnly := total
+			for _, e := range supplements {
+				foodOnly -= entryNutrient(e, name)
+			}
+			switch {
+			case IsSupplementDependent(name, total, foodOnly, goal):
+				metWithSupplements = true
+			case goalMet(name, foodOnly, goal):
+				metByFood = true
+			}
+		}
+		dependencies[name] = metWithSupplements && !metByFood
+	}
+	return dependencies
+}
@@ -0,0 +1,41 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// tableColumns are the nutrients shown in table output, in order
+var tableColumns = []string{"calories", "fat", "carbs", "protein", "fiber"}
+
+// writeTable prints the records as a fixed-width text table. With color
+// set, values with a goal are colored by ColorizeValue, with minimumGoals
+// giving each goal's direction.
+func writeTable(w io.Writer, records []DailyNutrition, color bool) {
+	dateWidth := len("DATE")
+	for _, d := range records {
+		if len(d.Date) > dateWidth {
+			dateWidth = len(d.Date)
+		}
+	}
+
+	header := fmt.Sprintf("%-*s", dateWidth, "DATE")
+	for _, name := range tableColumns {
+		header += fmt.Sprintf("  %10s", strings.ToUpper(name))
+	}
+	fmt.Fprintln(w, header)
+
+	for _, d := range records {
+		line := fmt.Sprintf("%-*s", dateWidth, d.Date)
+		for _, name := range tableColumns {
+			value := *nutrientField(&d, name)
+			goal := 0.0
+			if color {
+				goal = d.Goals[name].Target
+			}
+			line += "  " + ColorizeValue(value, goal, minimumGoals[name], "%10.1f")
+		}
+		fmt.Fprintln(w, line)
+	}
+}
@@ -0,0 +1,24 @@
+// Package test documents how the cronometer_cli tests are run.
+//
+// The default run is unit tests only, with the Cronometer client, storage,
+// parser, and formatter mocked (see mock_test.go), so it needs no account or
+// network:
+//
+//	cd nutrition/cronometer_cli
+//	go test -cover ./...
+//
+// With -cover, TestMain warns when statement coverage is below 70%. Without
+// it coverage is not measured and no warning is printed.
+//
+// A test that logs in to the real Cronometer API must start with the build
+// constraint
+//
+//	//go:build integration
+//
+// so that it only runs when the tag is given:
+//
+//	go test -tags integration ./...
+//
+// None of the current tests do. The 70% coverage target applies to the
+// default, mocked run.
+package test
@@ -0,0 +1,25 @@
+package main
+
+// Share of each macro's calories burned digesting it
+const (
+	tefProtein = 0.25
+	tefCarbs   = 0.08
+	tefFat     = 0.03
+)
+
+// ThermicEffect estimates the calories burned digesting the day's food from
+// the Atwater calories of each macro
+func ThermicEffect(d DailyNutrition) float64 {
+	return tefProtein*kcalPerGramProtein*d.Protein + tefCarbs*kcalPerGramCarbs*d.Carbs + tefFat*kcalPerGramFat*d.Fat
+}
+
+// applyThermicEffect sets each day's thermic effect and the calories left after it
+func applyThermicEffect(records []DailyNutrition) {
+	for i := range records {
+		d := &records[i]
+		tef := ThermicEffect(*d)
+		net := d.Calories - tef
+		d.ThermalEffectKcal = &tef
+		d.NetCaloriesAfterTEF = &net
+	}
+}
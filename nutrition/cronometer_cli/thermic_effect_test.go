@@ -0,0 +1,43 @@
+package main
+
+import (
+	"math"
+	"testing"
+)
+
+func TestThermicEffect(t *testing.T) {
+	tests := []struct {
+		name                string
+		protein, carbs, fat float64
+		want                float64
+	}{
+		{"no food", 0, 0, 0, 0},
+		// 100g protein is 400 kcal, 25% of which is burned
+		{"protein only", 100, 0, 0, 100},
+		// 250g carbs is 1000 kcal at 8%
+		{"carbs only", 0, 250, 0, 80},
+		// 100g fat is 900 kcal at 3%
+		{"fat only", 0, 0, 100, 27},
+		{"mixed day", 150, 200, 70, 150 + 64 + 18.9},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := DailyNutrition{Protein: tt.protein, Carbs: tt.carbs, Fat: tt.fat}
+			if got := ThermicEffect(d); math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("ThermicEffect = %g, want %g", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestApplyThermicEffectNetCalories(t *testing.T) {
+	records := []DailyNutrition{{Calories: 2200, Protein: 150, Carbs: 200, Fat: 70}}
+	applyThermicEffect(records)
+	d := records[0]
+	if d.ThermalEffectKcal == nil || math.Abs(*d.ThermalEffectKcal-232.9) > 1e-9 {
+		t.Fatalf("thermic effect = %v, want 232.9", d.ThermalEffectKcal)
+	}
+	if math.Abs(*d.NetCaloriesAfterTEF-(2200-232.9)) > 1e-9 {
+		t.Errorf("net calories = %g, want %g", *d.NetCaloriesAfterTEF, 2200-232.9)
+	}
+}
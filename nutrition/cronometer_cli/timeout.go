@@ -0,0 +1,21 @@
+package main
+
+import (
+	"errors"
+	"strings"
+)
+
+// errRequestTimeout replaces errors from requests cut off by -timeout-seconds
+var errRequestTimeout = errors.New("request timed out (raise -timeout-seconds for slow connections)")
+
+// timeoutMarker is how net/http describes a request cut off by http.Client.Timeout
+const timeoutMarker = "Client.Timeout exceeded"
+
+// describeTimeout replaces an HTTP client timeout error with errRequestTimeout.
+// gocronometer formats errors with %s, so the message is the only way to tell.
+func describeTimeout(err error) error {
+	if err != nil && strings.Contains(err.Error(), timeoutMarker) {
+		return errRequestTimeout
+	}
+	return err
+}
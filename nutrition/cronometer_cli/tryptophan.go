@@ -0,0 +1,94 @@
+package main
+
+// tryptophanMgPerKg is the WHO adult tryptophan requirement; without
+// -weight-kg it is applied to a 70 kg adult
+const (
+	tryptophanMgPerKg = 4.0
+	defaultWeightKg   = 70.0
+)
+
+// TryptophanReport is a day's tryptophan against its requirement, with the
+// ratio to the large neutral amino acids that compete with it to cross the
+// blood-brain barrier
+type TryptophanReport struct {
+	TryptophanG float64 `json:"tryptophan_g"`
+	LNAAG       float64 `json:"lnaa_g"`
+	// RatioToLNAA is omitted when no LNAA columns were exported
+	RatioToLNAA *float64 `json:"ratio_to_lnaa,omitempty"`
+	RequiredG   float64  `json:"required_g"`
+	Status      string   `json:"status"`
+}
+
+// TryptophanMood correlates daily tryptophan with days whose notes mention a
+// positive mood, over the days with notes
+type TryptophanMood struct {
+	Days         int     `json:"days"`
+	PositiveDays int     `json:"positive_days"`
+	Correlation  float64 `json:"correlation"`
+}
+
+// largeNeutralAminoAcids sums leucine, isoleucine, valine, phenylalanine, and tyrosine
+func largeNeutralAminoAcids(d DailyNutrition) float64 {
+	return d.Leucine + d.Isoleucine + d.Valine + d.Phenylalanine + d.Tyrosine
+}
+
+// checkTryptophan rates a day's tryptophan as "adequate" or "low" for weightKg
+func checkTryptophan(d DailyNutrition, weightKg float64) TryptophanReport {
+	report := TryptophanReport{
+		TryptophanG: d.Tryptophan,
+		LNAAG:       largeNeutralAminoAcids(d),
+		RequiredG:   tryptophanMgPerKg * weightKg / 1000,
+		Status:      "low",
+	}
+	if report.LNAAG > 0 {
+		ratio := d.Tryptophan / report.LNAAG
+		report.RatioToLNAA = &ratio
+	}
+	if d.Tryptophan >= report.RequiredG {
+		report.Status = "adequate"
+	}
+	return report
+}
+
+// applyTryptophan adds a tryptophan report to each record
+func applyTryptophan(records []DailyNutrition, weightKg float64) {
+	if weightKg <= 0 {
+		weightKg = defaultWeightKg
+	}
+	for i := range records {
+		report := checkTryptophan(records[i], weightKg)
+		records[i].TryptophanReport = &report
+	}
+}
+
+// correlateTryptophanMood correlates tryptophan with a 1/0 positive mood
+// indicator over the days with notes. It returns nil when fewer than three
+// days have notes or every day has the same mood.
+func correlateTryptophanMood(records []DailyNutrition, moods map[string]string, positiveTags []string) *TryptophanMood {
+	var tryptophan, positive []float64
+	for _, d := range records {
+		mood, ok := moods[d.Date]
+		if !ok {
+			continue
+		}
+		tryptophan = append(tryptophan, d.Tryptophan)
+		if hasMoodTag(mood, positiveTags) {
+			positive = append(positive, 1)
+		} else {
+			positive = append(positive, 0)
+		}
+	}
+	if len(tryptophan) < 3 {
+		return nil
+	}
+	correlation, err := pearson(tryptophan, positive)
+	if err != nil {
+		return nil
+	}
+
+	result := &TryptophanMood{Days: len(tryptophan), Correlation: correlation}
+	for _, p := range positive {
+		result.PositiveDays += int(p)
+	}
+	return result
+}
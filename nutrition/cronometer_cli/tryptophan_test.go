@@ -0,0 +1,36 @@
+package main
+
+import "testing"
+
+func TestCorrelateTryptophanMoodNegatedTags(t *testing.T) {
+	records := []DailyNutrition{
+		{Date: "2024-03-01", Tryptophan: 1400},
+		{Date: "2024-03-02", Tryptophan: 1300},
+		{Date: "2024-03-03", Tryptophan: 600},
+		{Date: "2024-03-04", Tryptophan: 500},
+	}
+	moods := map[string]string{
+		"2024-03-01": "good",
+		"2024-03-02": "calm and happy",
+		"2024-03-03": "not good",
+		"2024-03-04": "goodness, what a day",
+	}
+	result := correlateTryptophanMood(records, moods, []string{"good", "calm", "happy"})
+	if result == nil {
+		t.Fatal("no correlation")
+	}
+	if result.PositiveDays != 2 || result.Days != 4 {
+		t.Errorf("result = %+v, want 2 positive days of 4", result)
+	}
+	if result.Correlation < 0.9 {
+		t.Errorf("correlation = %g, want strongly positive", result.Correlation)
+	}
+}
+
+func TestCorrelateTryptophanMoodTooFewDays(t *testing.T) {
+	records := []DailyNutrition{{Date: "2024-03-01", Tryptophan: 1000}, {Date: "2024-03-02", Tryptophan: 900}}
+	moods := map[string]string{"2024-03-01": "good", "2024-03-02": "tired"}
+	if result := correlateTryptophanMood(records, moods, []string{"good"}); result != nil {
+		t.Errorf("result = %+v, want nil with fewer than three days of notes", result)
+	}
+}
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// vaultSecret is a Vault read response. KV v2 nests the secret under
+// data.data; KV v1 puts it directly under data.
+type vaultSecret struct {
+	Data map[string]interface{} `json:"data"`
+}
+
+// fetchVaultCredentials reads the username and password from the secret at
+// path, authenticating with token
+func fetchVaultCredentials(ctx context.Context, addr, path, token string, timeout time.Duration) (accountCredentials, error) {
+	if token == "" {
+		return accountCredentials{}, fmt.Errorf("VAULT_TOKEN is not set")
+	}
+
+	url := strings.TrimRight(addr, "/") + "/v1/" + strings.TrimLeft(path, "/")
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return accountCredentials{}, fmt.Errorf("building Vault request: %v", err)
+	}
+	req.Header.Set("X-Vault-Token", token)
+
+	resp, err := (&http.Client{Timeout: timeout}).Do(req)
+	if err != nil {
+		return accountCredentials{}, fmt.Errorf("reading %s: %v", path, describeTimeout(err))
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return accountCredentials{}, fmt.Errorf("reading Vault response: %v", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return accountCredentials{}, fmt.Errorf("Vault returned status %d for %s", resp.StatusCode, path)
+	}
+
+	var secret vaultSecret
+	if err := json.Unmarshal(body, &secret); err != nil {
+		return accountCredentials{}, fmt.Errorf("decoding Vault response: %v", err)
+	}
+	data := secret.Data
+	if nested, ok := data["data"].(map[string]interface{}); ok {
+		data = nested
+	}
+	username, _ := data["username"].(string)
+	password, _ := data["password"].(string)
+	if username == "" || password == "" {
+		return accountCredentials{}, fmt.Errorf("secret %s has no username and password", path)
+	}
+	return accountCredentials{Username: username, Password: password}, nil
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"math"
+	"time"
+)
+
+// vitaminDRDAIU is the adult recommended dietary allowance for vitamin D
+const vitaminDRDAIU = 600.0
+
+// Rough sun synthesis model: no meaningful UVB below this noon sun elevation,
+// rising linearly to maxSunSynthesisIU with the sun overhead. This assumes
+// about 15 minutes of midday sun on the face and arms.
+const (
+	minSynthesisElevation = 45.0
+	maxSunSynthesisIU     = 1000.0
+)
+
+// VitaminDReport compares a day's dietary vitamin D plus estimated sun
+// synthesis against the RDA
+type VitaminDReport struct {
+	DietaryIU        float64 `json:"dietary_iu"`
+	NoonSunElevation float64 `json:"noon_sun_elevation"`
+	SunEstimateIU    float64 `json:"sun_estimate_iu"`
+	TotalIU          float64 `json:"total_iu"`
+	RDAIU            float64 `json:"rda_iu"`
+	Sufficient       bool    `json:"sufficient"`
+}
+
+// noonSunElevation returns the sun's elevation in degrees at solar noon
+func noonSunElevation(date time.Time, latitude float64) float64 {
+	declination := 23.44 * math.Sin(2*math.Pi*float64(284+date.YearDay())/365)
+	return 90 - math.Abs(latitude-declination)
+}
+
+// VitaminDSufficiency estimates total vitamin D for the day from diet and the
+// sun synthesis likely at this latitude and time of year
+func VitaminDSufficiency(d DailyNutrition, date time.Time, latitude float64) VitaminDReport {
+	elevation := noonSunElevation(date, latitude)
+	report := VitaminDReport{
+		DietaryIU:        d.VitaminD,
+		NoonSunElevation: elevation,
+		RDAIU:            vitaminDRDAIU,
+	}
+	if elevation > minSynthesisElevation {
+		report.SunEstimateIU = maxSunSynthesisIU * (elevation - minSynthesisElevation) / (90 - minSynthesisElevation)
+	}
+	report.TotalIU = report.DietaryIU + report.SunEstimateIU
+	report.Sufficient = report.TotalIU >= vitaminDRDAIU
+	return report
+}
+
+// applyVitaminD adds a vitamin D report to each record
+func applyVitaminD(records []DailyNutrition, latitude float64) {
+	for i := range records {
+		date, err := time.Parse("2006-01-02", records[i].Date)
+		if err != nil {
+			continue
+		}
+		report := VitaminDSufficiency(records[i], date, latitude)
+		records[i].VitaminDReport = &report
+	}
+}
@@ -0,0 +1,55 @@
+package main
+
+import "math"
+
+// Vitamin K targets: the adult male AI for K1 (phylloquinone), and the K2
+// (menaquinone) dose used in bone and arterial calcification research
+const (
+	vitaminK1AIUg   = 120.0
+	vitaminK2DoseUg = 45.0
+)
+
+// vitaminK2Note is added to every vitamin K report
+const vitaminK2Note = "MK-7, the K2 form in natto and other fermented foods, is the most bioavailable and stays in the blood longest."
+
+// VitaminKReport rates a day's K1 and K2 separately. K2 helps direct calcium
+// to bone rather than arteries.
+type VitaminKReport struct {
+	K1Ug       float64 `json:"k1_ug"`
+	K2Ug       float64 `json:"k2_ug"`
+	K1Adequacy string  `json:"k1_adequacy"`
+	K2Adequacy string  `json:"k2_adequacy"`
+	Note       string  `json:"note"`
+}
+
+// checkVitaminK rates a day's K1 against the AI and K2 against the research
+// dose as "adequate" or "low". When the export has only total vitamin K, K1
+// is the total less any K2.
+func checkVitaminK(d DailyNutrition) VitaminKReport {
+	k1 := d.VitaminK1
+	if k1 == 0 {
+		k1 = math.Max(d.VitaminK-d.VitaminK2, 0)
+	}
+	report := VitaminKReport{
+		K1Ug:       k1,
+		K2Ug:       d.VitaminK2,
+		K1Adequacy: "low",
+		K2Adequacy: "low",
+		Note:       vitaminK2Note,
+	}
+	if k1 >= vitaminK1AIUg {
+		report.K1Adequacy = "adequate"
+	}
+	if d.VitaminK2 >= vitaminK2DoseUg {
+		report.K2Adequacy = "adequate"
+	}
+	return report
+}
+
+// applyVitaminK adds a vitamin K report to each record
+func applyVitaminK(records []DailyNutrition) {
+	for i := range records {
+		report := checkVitaminK(records[i])
+		records[i].VitaminKReport = &report
+	}
+}
@@ -0,0 +1,126 @@
+package main
+
+import (
+	"sort"
+	"time"
+)
+
+// weeklyCalorieTrendPct is how much a week's mean calories must change from
+// the week before, in percent, to count as a trend
+const weeklyCalorieTrendPct = 5.0
+
+// WeekSummary aggregates one ISO week of logged days
+type WeekSummary struct {
+	Week             string   `json:"week"`
+	WeekStart        string   `json:"week_start"`
+	WeekEnd          string   `json:"week_end"`
+	DaysLogged       int      `json:"days_logged"`
+	TotalCalories    float64  `json:"total_calories"`
+	MeanCalories     float64  `json:"mean_calories"`
+	MeanProtein      float64  `json:"mean_protein"`
+	MeanFat          float64  `json:"mean_fat"`
+	MeanCarbs        float64  `json:"mean_carbs"`
+	GoalAdherencePct *float64 `json:"goal_adherence_pct,omitempty"`
+	Trend            string   `json:"trend,omitempty"`
+}
+
+// WeeklyTrendSummary aggregates the records per ISO week, sorted by week.
+// GoalAdherencePct is the share of days meeting every goal, left out when
+// there are no goals. Trend compares mean calories with the week before:
+// ↑/↓ for a change of more than 5%, → otherwise.
+func WeeklyTrendSummary(records []DailyNutrition, goals map[string]float64) []WeekSummary {
+	byWeek := make(map[string]*WeekSummary)
+	met := make(map[string]int)
+	for _, d := range records {
+		date, err := time.Parse("2006-01-02", d.Date)
+		if err != nil {
+			continue
+		}
+		year, week := date.ISOWeek()
+		key, _ := isoWeek(d.Date)
+		summary, ok := byWeek[key]
+		if !ok {
+			start := isoWeekStart(year, week)
+			summary = &WeekSummary{
+				Week:      key,
+				WeekStart: start.Format("2006-01-02"),
+				WeekEnd:   start.AddDate(0, 0, 6).Format("2006-01-02"),
+			}
+			byWeek[key] = summary
+		}
+		summary.DaysLogged++
+		summary.TotalCalories += d.Calories
+		summary.MeanProtein += d.Protein
+		summary.MeanFat += d.Fat
+		summary.MeanCarbs += d.Carbs
+
+		allMet := true
+		for name, target := range goals {
+			if !dayMeetsGoal(d, name, target) {
+				allMet = false
+				break
+			}
+		}
+		if allMet {
+			met[key]++
+		}
+	}
+
+	weeks := make([]WeekSummary, 0, len(byWeek))
+	for key, summary := range byWeek {
+		n := float64(summary.DaysLogged)
+		summary.MeanCalories = summary.TotalCalories / n
+		summary.MeanProtein /= n
+		summary.MeanFat /= n
+		summary.MeanCarbs /= n
+		if len(goals) > 0 {
+			pct := float64(met[key]) / n * 100
+			summary.GoalAdherencePct = &pct
+		}
+		weeks = append(weeks, *summary)
+	}
+	sort.Slice(weeks, func(i, j int) bool {
+		return weeks[i].Week < weeks[j].Week
+	})
+
+	for i := 1; i < len(weeks); i++ {
+		prior := weeks[i-1].MeanCalories
+		if prior == 0 {
+			continue
+		}
+		change := (weeks[i].MeanCalories - prior) / prior * 100
+		switch {
+		case change > weeklyCalorieTrendPct:
+			weeks[i].Trend = trendImproving
+		case change < -weeklyCalorieTrendPct:
+			weeks[i].Trend = trendWorsening
+		default:
+			weeks[i].Trend = trendStable
+		}
+	}
+	return weeks
+}
+
+// isoWeekStart returns the Monday that starts the given ISO week
+func isoWeekStart(year, week int) time.Time {
+	// January 4th is always in week 1
+	jan4 := time.Date(year, time.January, 4, 0, 0, 0, 0, time.UTC)
+	offset := (int(jan4.Weekday()) + 6) % 7
+	return jan4.AddDate(0, 0, -offset+(week-1)*7)
+}
+
+// formatWeekDates rewrites the weeks' YYYY-MM-DD dates using the output layout
+func formatWeekDates(weeks []WeekSummary, layout string) error {
+	for i := range weeks {
+		start, err := formatDate(weeks[i].WeekStart, layout)
+		if err != nil {
+			return err
+		}
+		end, err := formatDate(weeks[i].WeekEnd, layout)
+		if err != nil {
+			return err
+		}
+		weeks[i].WeekStart, weeks[i].WeekEnd = start, end
+	}
+	return nil
+}
@@ -0,0 +1,121 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+)
+
+// projectionConfidence is the confidence level of the projected date's bounds
+const projectionConfidence = 0.8
+
+// WeightProjection is the projected date for reaching a target weight
+type WeightProjection struct {
+	TargetWeightLbs  float64     `json:"target_weight_lbs"`
+	CurrentWeightLbs float64     `json:"current_weight_lbs"`
+	LbsPerDay        float64     `json:"lbs_per_day"`
+	ProjectedDate    string      `json:"projected_date"`
+	LowerBound       string      `json:"lower_bound,omitempty"`
+	UpperBound       string      `json:"upper_bound,omitempty"`
+	Model            LinearModel `json:"model"`
+}
+
+// fitWeightModel regresses weight in pounds on the cumulative calorie deficit
+// (tdee minus calories) logged before each weigh-in. The model's origin is the
+// last weigh-in and XPerDay the average daily deficit.
+func fitWeightModel(records []DailyNutrition, weights []WeightMeasurement, tdee float64) (LinearModel, error) {
+	if len(records) == 0 {
+		return LinearModel{}, fmt.Errorf("no nutrition data")
+	}
+	days := make([]DailyNutrition, len(records))
+	copy(days, records)
+	sort.Slice(days, func(i, j int) bool {
+		return days[i].Date < days[j].Date
+	})
+
+	var x, y []float64
+	var cumulative float64
+	next := 0
+	for _, w := range weights {
+		if w.Date < days[0].Date {
+			continue
+		}
+		for next < len(days) && days[next].Date < w.Date {
+			cumulative += tdee - days[next].Calories
+			next++
+		}
+		x = append(x, cumulative)
+		y = append(y, w.WeightKg/kgPerLb)
+	}
+
+	model, err := fitOLS(x, y)
+	if err != nil {
+		return LinearModel{}, fmt.Errorf("fitting weight model: %v", err)
+	}
+
+	var totalDeficit float64
+	for _, d := range days {
+		totalDeficit += tdee - d.Calories
+	}
+	model.Origin, err = time.Parse("2006-01-02", weights[len(weights)-1].Date)
+	if err != nil {
+		return LinearModel{}, err
+	}
+	model.OriginX = x[len(x)-1]
+	model.XPerDay = totalDeficit / float64(len(days))
+	return model, nil
+}
+
+// ProjectTargetDate returns the date the model's fitted weight reaches targetWeight
+func ProjectTargetDate(model LinearModel, targetWeight float64) (time.Time, error) {
+	current := model.Intercept + model.Slope*model.OriginX
+	perDay := model.Slope * model.XPerDay
+	if perDay == 0 {
+		return time.Time{}, fmt.Errorf("weight is not changing at the current intake")
+	}
+	days := (targetWeight - current) / perDay
+	if days < 0 {
+		return time.Time{}, fmt.Errorf("weight is moving away from %.1f at the current intake", targetWeight)
+	}
+	return model.Origin.AddDate(0, 0, int(math.Ceil(days))), nil
+}
+
+// projectWeight projects the target date with bounds from the slope's confidence interval.
+// A bound is left empty when the target is not reached at that end of the interval.
+func projectWeight(model LinearModel, targetWeight float64) (*WeightProjection, error) {
+	projected, err := ProjectTargetDate(model, targetWeight)
+	if err != nil {
+		return nil, err
+	}
+	projection := &WeightProjection{
+		TargetWeightLbs:  targetWeight,
+		CurrentWeightLbs: model.Intercept + model.Slope*model.OriginX,
+		LbsPerDay:        model.Slope * model.XPerDay,
+		ProjectedDate:    projected.Format("2006-01-02"),
+		Model:            model,
+	}
+
+	// Vary the slope about the mean x, where the fitted line is best determined
+	margin := studentTQuantile(1-(1-projectionConfidence)/2, float64(model.N-2)) * model.SlopeSE
+	var bounds []time.Time
+	for _, slope := range []float64{model.Slope - margin, model.Slope + margin} {
+		varied := model
+		varied.Slope = slope
+		varied.Intercept = model.Intercept + (model.Slope-slope)*model.MeanX
+		if date, err := ProjectTargetDate(varied, targetWeight); err == nil {
+			bounds = append(bounds, date)
+		}
+	}
+	sort.Slice(bounds, func(i, j int) bool {
+		return bounds[i].Before(bounds[j])
+	})
+	switch {
+	case len(bounds) == 2:
+		projection.LowerBound = bounds[0].Format("2006-01-02")
+		projection.UpperBound = bounds[1].Format("2006-01-02")
+	case len(bounds) == 1:
+		projection.LowerBound = bounds[0].Format("2006-01-02")
+	}
+	return projection, nil
+}
@@ -0,0 +1,46 @@
+package main
+
+import (
+	"math"
+	"time"
+)
+
+// maxDailyWeightChangeLbs is the most weight can plausibly change per day
+const maxDailyWeightChangeLbs = 2.0
+
+// WeightAnomaly is a change between consecutive weigh-ins that is too large
+// to be real, usually a logging error or unit mismatch
+type WeightAnomaly struct {
+	Date          string  `json:"date"`
+	PriorWeight   float64 `json:"prior_weight_lbs"`
+	CurrentWeight float64 `json:"current_weight_lbs"`
+	Delta         float64 `json:"delta_lbs"`
+}
+
+// ValidateWeightTransitions flags weigh-ins that differ from the previous one
+// by more than maxDailyChangeLbs per day elapsed
+func ValidateWeightTransitions(entries []BiometricEntry, maxDailyChangeLbs float64) []WeightAnomaly {
+	series := weightSeries(entries)
+
+	var anomalies []WeightAnomaly
+	for i := 1; i < len(series); i++ {
+		prior, current := series[i-1], series[i]
+		priorDate, err1 := time.Parse("2006-01-02", prior.Date)
+		currentDate, err2 := time.Parse("2006-01-02", current.Date)
+		if err1 != nil || err2 != nil {
+			continue
+		}
+
+		days := currentDate.Sub(priorDate).Hours() / 24
+		delta := (current.WeightKg - prior.WeightKg) / kgPerLb
+		if math.Abs(delta) > maxDailyChangeLbs*days {
+			anomalies = append(anomalies, WeightAnomaly{
+				Date:          current.Date,
+				PriorWeight:   prior.WeightKg / kgPerLb,
+				CurrentWeight: current.WeightKg / kgPerLb,
+				Delta:         delta,
+			})
+		}
+	}
+	return anomalies
+}